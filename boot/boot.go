@@ -0,0 +1,90 @@
+// Package sysdboot gives the current boot ID and the boot time of the system, and helps
+// invalidating persisted state when the system has been rebooted since it was written.
+package sysdboot
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	sysdid128 "github.com/iguanesolutions/go-systemd/v6/id128"
+)
+
+// ID returns the ID of the current boot, which changes on every boot
+// (the _BOOT_ID field of journal entries).
+func ID() (sysdid128.ID128, error) {
+	return sysdid128.BootID()
+}
+
+// Since returns the time elapsed since the kernel booted, suspend excluded (CLOCK_MONOTONIC),
+// which is the clock of the monotonic timestamps of the journal and of the manager.
+func Since() (time.Duration, error) {
+	return clock(clockMonotonic)
+}
+
+// Uptime returns the time elapsed since the kernel booted, suspend included (CLOCK_BOOTTIME).
+func Uptime() (time.Duration, error) {
+	return clock(clockBoottime)
+}
+
+// Time returns when the kernel booted, computed from the realtime clock and Uptime:
+// it moves if the realtime clock is changed (eg: by NTP).
+func Time() (time.Time, error) {
+	now := time.Now()
+	uptime, err := Uptime()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return now.Add(-uptime).Round(0), nil
+}
+
+// SameBoot tells if id is the ID of the current boot, ie: the system has not been rebooted since id was read.
+// id: boot ID, eg: read from persisted state or from the _BOOT_ID field of a journal entry
+func SameBoot(id sysdid128.ID128) (bool, error) {
+	current, err := ID()
+	if err != nil {
+		return false, err
+	}
+	return current == id, nil
+}
+
+// Rebooted tells if the system has been rebooted since the last call with the same file, so state
+// persisted across restarts of the service (eg: in $STATE_DIRECTORY) which does not survive a reboot
+// (eg: PIDs, runtime paths, monotonic timestamps) can be invalidated. The current boot ID is stored in file,
+// a missing or unreadable file counting as a reboot.
+// file: path of the file storing the boot ID
+func Rebooted(file string) (bool, error) {
+	current, err := ID()
+	if err != nil {
+		return false, err
+	}
+	data, err := os.ReadFile(file)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return false, err
+	}
+	if err == nil {
+		if stored, err := sysdid128.Parse(strings.TrimSpace(string(data))); err == nil && stored == current {
+			return false, nil
+		}
+	}
+	// write then rename so a crash does not leave a truncated file behind
+	tmp, err := os.CreateTemp(filepath.Dir(file), "."+filepath.Base(file)+".*")
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.WriteString(current.String() + "\n"); err != nil {
+		tmp.Close()
+		return false, err
+	}
+	if err = tmp.Close(); err != nil {
+		return false, err
+	}
+	if err = os.Rename(tmp.Name(), file); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,57 @@
+package sysdboot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	sysdid128 "github.com/iguanesolutions/go-systemd/v6/id128"
+)
+
+func TestClocks(t *testing.T) {
+	since, err := Since()
+	if err != nil {
+		t.Fatal(err)
+	}
+	uptime, err := Uptime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if since <= 0 || uptime < since {
+		t.Errorf("unexpected clocks: since %s, uptime %s", since, uptime)
+	}
+	booted, err := Time()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !booted.Before(time.Now()) {
+		t.Errorf("unexpected boot time %s", booted)
+	}
+}
+
+func TestRebooted(t *testing.T) {
+	if _, err := ID(); err != nil {
+		t.Skip("no boot ID:", err)
+	}
+	file := filepath.Join(t.TempDir(), "boot-id")
+	for i, expected := range []bool{true, false, false} {
+		rebooted, err := Rebooted(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rebooted != expected {
+			t.Errorf("call %d: expected %v, got %v", i, expected, rebooted)
+		}
+	}
+	// simulates a state written during another boot
+	if err := os.WriteFile(file, []byte(sysdid128.Null.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if rebooted, err := Rebooted(file); err != nil || !rebooted {
+		t.Errorf("expected a reboot, got %v %v", rebooted, err)
+	}
+	if same, err := SameBoot(sysdid128.Null); err != nil || same {
+		t.Errorf("expected another boot, got %v %v", same, err)
+	}
+}
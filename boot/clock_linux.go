@@ -0,0 +1,21 @@
+package sysdboot
+
+import (
+	"fmt"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+const (
+	clockMonotonic = 1 // CLOCK_MONOTONIC
+	clockBoottime  = 7 // CLOCK_BOOTTIME
+)
+
+func clock(id uintptr) (time.Duration, error) {
+	var ts syscall.Timespec
+	if _, _, errno := syscall.RawSyscall(syscall.SYS_CLOCK_GETTIME, id, uintptr(unsafe.Pointer(&ts)), 0); errno != 0 {
+		return 0, fmt.Errorf("clock_gettime: %w", errno)
+	}
+	return time.Duration(ts.Nano()), nil
+}
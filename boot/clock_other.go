@@ -0,0 +1,18 @@
+//go:build !linux
+
+package sysdboot
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	clockMonotonic = iota
+	clockBoottime
+)
+
+// clock fails with errors.ErrUnsupported, the boot clocks are read the Linux way only.
+func clock(id uintptr) (time.Duration, error) {
+	return 0, errors.ErrUnsupported
+}
@@ -0,0 +1,192 @@
+// Package sysdcgroup introspects the cgroup (v2) of the calling process: the unit and slice it runs in
+// and the resource limits and usage enforced by the manager (MemoryMax=, CPUQuota=, TasksMax=...),
+// so services can size their caches and worker pools after their unit limits.
+package sysdcgroup
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iguanesolutions/go-systemd/v6/internal/cgroupfs"
+	"github.com/iguanesolutions/go-systemd/v6/systemd1"
+)
+
+// Unlimited is the value of the limits which are not set.
+const Unlimited = math.MaxInt64
+
+// Cgroup is the cgroup of a process.
+type Cgroup struct {
+	Path     string // cgroup path, eg: /system.slice/foo.service
+	Unit     string // unit of the system manager, eg: foo.service
+	Slice    string // slice of Unit, eg: system.slice
+	UserUnit string // unit of the user manager when Unit is one, eg: foo.service
+}
+
+// Self returns the cgroup of the calling process, it does not need a connection to the manager.
+func Self() (Cgroup, error) {
+	cu, err := systemd1.CurrentCgroupUnit()
+	if err != nil && !errors.Is(err, systemd1.ErrNoUnit) {
+		return Cgroup{}, err
+	}
+	return Cgroup{
+		Path:     cu.Cgroup,
+		Unit:     cu.Unit,
+		Slice:    cu.Slice,
+		UserUnit: cu.UserUnit,
+	}, nil
+}
+
+// Dir returns the directory of the cgroup in the cgroup v2 hierarchy.
+func (c Cgroup) Dir() string {
+	return cgroupfs.Dir(c.Path)
+}
+
+// Resources are the resource limits and usage of a cgroup. Limits are the effective ones:
+// the lowest value set on the cgroup and its ancestors (eg: MemoryMax= of the slice).
+type Resources struct {
+	MemoryCurrent int64         // bytes used by the cgroup
+	MemoryHigh    int64         // bytes above which the cgroup is throttled, Unlimited if not set
+	MemoryMax     int64         // bytes above which the OOM killer is invoked, Unlimited if not set
+	CPUQuota      time.Duration // CPU time allowed per CPUPeriod, 0 if not set
+	CPUPeriod     time.Duration // 0 if CPUQuota is not set
+	PIDsCurrent   int64         // tasks in the cgroup
+	PIDsMax       int64         // maximum number of tasks, Unlimited if not set
+}
+
+// Resources returns the resource limits and usage of the cgroup. Controllers not enabled on the
+// cgroup (eg: no MemoryAccounting=) leave their limits Unlimited and their usage 0.
+func (c Cgroup) Resources() (Resources, error) {
+	return readResources(cgroupfs.Root, c.Path)
+}
+
+// SelfResources returns the resource limits and usage of the cgroup of the calling process.
+func SelfResources() (Resources, error) {
+	c, err := Self()
+	if err != nil {
+		return Resources{}, err
+	}
+	return c.Resources()
+}
+
+// CPUs returns the number of CPUs the cgroup may use (CPUQuota / CPUPeriod), rounded up and
+// bounded by the CPUs usable by the process. Handy to size a worker pool.
+func (r Resources) CPUs() int {
+	cpus := runtime.NumCPU()
+	if r.CPUQuota <= 0 || r.CPUPeriod <= 0 {
+		return cpus
+	}
+	quota := int(math.Ceil(float64(r.CPUQuota) / float64(r.CPUPeriod)))
+	return max(1, min(quota, cpus))
+}
+
+// MemoryAvailable returns the bytes the cgroup may still allocate before reaching MemoryHigh or
+// MemoryMax, Unlimited if none is set.
+func (r Resources) MemoryAvailable() int64 {
+	limit := min(r.MemoryHigh, r.MemoryMax)
+	if limit == Unlimited {
+		return Unlimited
+	}
+	return max(0, limit-r.MemoryCurrent)
+}
+
+func readResources(root, cgroup string) (r Resources, err error) {
+	dir := filepath.Join(root, cgroup)
+	if r.MemoryCurrent, err = readInt(filepath.Join(dir, "memory.current"), 0); err != nil {
+		return
+	}
+	if r.PIDsCurrent, err = readInt(filepath.Join(dir, "pids.current"), 0); err != nil {
+		return
+	}
+	r.MemoryHigh, r.MemoryMax, r.PIDsMax = Unlimited, Unlimited, Unlimited
+	// walk up to the root, the limits of the ancestors apply too
+	for p := path.Clean("/" + cgroup); ; p = path.Dir(p) {
+		dir = filepath.Join(root, p)
+		if err = minInt(&r.MemoryHigh, filepath.Join(dir, "memory.high")); err != nil {
+			return
+		}
+		if err = minInt(&r.MemoryMax, filepath.Join(dir, "memory.max")); err != nil {
+			return
+		}
+		if err = minInt(&r.PIDsMax, filepath.Join(dir, "pids.max")); err != nil {
+			return
+		}
+		if err = minCPU(&r, filepath.Join(dir, "cpu.max")); err != nil {
+			return
+		}
+		if p == "/" {
+			return
+		}
+	}
+}
+
+// readInt reads a cgroup file holding an integer or "max" (Unlimited), def is returned if it does not exist.
+func readInt(file string, def int64) (int64, error) {
+	data, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return def, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return parseInt(file, strings.TrimSpace(string(data)))
+}
+
+func parseInt(file, value string) (int64, error) {
+	if value == "max" {
+		return Unlimited, nil
+	}
+	v, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("can't parse %s: %w", file, err)
+	}
+	return v, nil
+}
+
+func minInt(v *int64, file string) error {
+	limit, err := readInt(file, Unlimited)
+	if err != nil {
+		return err
+	}
+	*v = min(*v, limit)
+	return nil
+}
+
+// minCPU reads a cpu.max file ("$MAX $PERIOD") and keeps it if it is lower than the current quota.
+func minCPU(r *Resources, file string) error {
+	data, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) != 2 {
+		return fmt.Errorf("can't parse %s: unexpected content %q", file, data)
+	}
+	quota, err := parseInt(file, fields[0])
+	if err != nil || quota == Unlimited {
+		return err
+	}
+	period, err := parseInt(file, fields[1])
+	if err != nil {
+		return err
+	}
+	if period <= 0 {
+		return fmt.Errorf("can't parse %s: invalid period %d", file, period)
+	}
+	quotaD, periodD := time.Duration(quota)*time.Microsecond, time.Duration(period)*time.Microsecond
+	if r.CPUPeriod == 0 || float64(quotaD)/float64(periodD) < float64(r.CPUQuota)/float64(r.CPUPeriod) {
+		r.CPUQuota, r.CPUPeriod = quotaD, periodD
+	}
+	return nil
+}
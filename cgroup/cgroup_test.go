@@ -0,0 +1,53 @@
+package sysdcgroup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReadResources(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"system.slice/cpu.max":                    "50000 100000\n",
+		"system.slice/memory.max":                 "1073741824\n",
+		"system.slice/foo.service/memory.current": "104857600\n",
+		"system.slice/foo.service/memory.high":    "max\n",
+		"system.slice/foo.service/memory.max":     "2147483648\n",
+		"system.slice/foo.service/cpu.max":        "max 100000\n",
+		"system.slice/foo.service/pids.current":   "12\n",
+		"system.slice/foo.service/pids.max":       "4915\n",
+	}
+	for name, content := range files {
+		file := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r, err := readResources(root, "/system.slice/foo.service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Resources{
+		MemoryCurrent: 104857600,
+		MemoryHigh:    Unlimited,
+		MemoryMax:     1073741824,
+		CPUQuota:      50 * time.Millisecond,
+		CPUPeriod:     100 * time.Millisecond,
+		PIDsCurrent:   12,
+		PIDsMax:       4915,
+	}
+	if r != expected {
+		t.Errorf("expected %+v, got %+v", expected, r)
+	}
+	if available := r.MemoryAvailable(); available != 1073741824-104857600 {
+		t.Errorf("unexpected available memory %d", available)
+	}
+	if cpus := r.CPUs(); cpus != 1 {
+		t.Errorf("expected 1 CPU, got %d", cpus)
+	}
+}
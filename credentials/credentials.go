@@ -0,0 +1,132 @@
+// Package sysdcredentials reads the credentials systemd passes to services
+// (LoadCredential=, LoadCredentialEncrypted=, SetCredential=, ImportCredential=) thru the
+// directory pointed by $CREDENTIALS_DIRECTORY, the standard way to deliver secrets to services.
+package sysdcredentials
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrNoCredentialsDirectory is returned when $CREDENTIALS_DIRECTORY is not set or does not exist,
+	// ie: the process has not been started by systemd with credentials.
+	ErrNoCredentialsDirectory = errors.New("no credentials directory")
+	// ErrNotFound is returned when no credential has been passed under the requested name.
+	ErrNotFound = errors.New("no credential with that name")
+)
+
+// Credential describes a credential passed to the service.
+type Credential struct {
+	Name string
+	Size int64  // bytes
+	Path string // absolute path of the credential file
+}
+
+// Dir returns the credentials directory, ErrNoCredentialsDirectory if there is none.
+func Dir() (string, error) {
+	dir := os.Getenv("CREDENTIALS_DIRECTORY")
+	if dir == "" {
+		return "", fmt.Errorf("%w: CREDENTIALS_DIRECTORY is not set", ErrNoCredentialsDirectory)
+	}
+	info, err := os.Stat(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("%w: %s does not exist", ErrNoCredentialsDirectory, dir)
+	}
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%w: %s is not a directory", ErrNoCredentialsDirectory, dir)
+	}
+	return dir, nil
+}
+
+// List returns the credentials passed to the service, sorted by name.
+func List() ([]Credential, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	creds := make([]Credential, 0, len(entries))
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			// removed meanwhile
+			continue
+		}
+		creds = append(creds, Credential{
+			Name: e.Name(),
+			Size: info.Size(),
+			Path: filepath.Join(dir, e.Name()),
+		})
+	}
+	sort.Slice(creds, func(i, j int) bool {
+		return creds[i].Name < creds[j].Name
+	})
+	return creds, nil
+}
+
+// Stat returns the credential name, ErrNotFound if it has not been passed.
+func Stat(name string) (*Credential, error) {
+	path, err := Path(name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Credential{
+		Name: name,
+		Size: info.Size(),
+		Path: path,
+	}, nil
+}
+
+// Path returns the path of the credential name, for libraries wanting a file path
+// (eg: TLS keys). The credential existence is not checked.
+func Path(name string) (string, error) {
+	if name == "" || name == "." || name == ".." || strings.ContainsRune(name, '/') {
+		return "", fmt.Errorf("invalid credential name %q", name)
+	}
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// Read returns the content of the credential name, ErrNotFound if it has not been passed.
+func Read(name string) ([]byte, error) {
+	path, err := Path(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return data, err
+}
+
+// ReadString returns the content of the credential name as a string, as is (trailing newline included).
+func ReadString(name string) (string, error) {
+	data, err := Read(name)
+	return string(data), err
+}
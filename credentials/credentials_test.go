@@ -0,0 +1,39 @@
+package sysdcredentials
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCredentials(t *testing.T) {
+	t.Setenv("CREDENTIALS_DIRECTORY", "")
+	if _, err := List(); !errors.Is(err, ErrNoCredentialsDirectory) {
+		t.Errorf("unexpected error without directory: %v", err)
+	}
+	dir := t.TempDir()
+	t.Setenv("CREDENTIALS_DIRECTORY", dir)
+	if err := os.WriteFile(filepath.Join(dir, "db-password"), []byte("hunter2"), 0o400); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "api-token"), []byte("t0k3n\n"), 0o400); err != nil {
+		t.Fatal(err)
+	}
+	creds, err := List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(creds) != 2 || creds[0].Name != "api-token" || creds[0].Size != 6 || creds[1].Name != "db-password" {
+		t.Errorf("unexpected credentials: %+v", creds)
+	}
+	if s, err := ReadString("db-password"); err != nil || s != "hunter2" {
+		t.Errorf("unexpected credential %q, %v", s, err)
+	}
+	if _, err = Read("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("unexpected error for a missing credential: %v", err)
+	}
+	if _, err = Read("../etc/passwd"); err == nil {
+		t.Error("path traversal accepted")
+	}
+}
@@ -0,0 +1,107 @@
+package sysd
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/iguanesolutions/go-systemd/v6/internal/cgroupfs"
+	"github.com/iguanesolutions/go-systemd/v6/systemd1"
+)
+
+// RunningUnderSystemd tells if the process has been started by a systemd manager (system or user),
+// either as a service or as one of its children, but not from a login shell (scope units).
+// It relies on $INVOCATION_ID, $MANAGERPID and $NOTIFY_SOCKET, then on the cgroup of the process
+// when they have been stripped by an intermediate process.
+func RunningUnderSystemd() bool {
+	for _, name := range []string{"INVOCATION_ID", "MANAGERPID", "NOTIFY_SOCKET"} {
+		if _, ok := os.LookupEnv(name); ok {
+			return true
+		}
+	}
+	_, ok := serviceUnit()
+	return ok
+}
+
+// IsUserInstance tells if the process has been started by a per-user systemd instance
+// rather than the system manager. It is false if not RunningUnderSystemd.
+func IsUserInstance() bool {
+	if _, ok := os.LookupEnv("MANAGERPID"); ok {
+		// only set by user managers
+		return true
+	}
+	if socket, ok := os.LookupEnv("NOTIFY_SOCKET"); ok {
+		if runtime := os.Getenv("XDG_RUNTIME_DIR"); runtime != "" && strings.HasPrefix(socket, runtime+"/") {
+			return true
+		}
+	}
+	cu, ok := serviceUnit()
+	return ok && cu.UserUnit != ""
+}
+
+// ManagerPID returns the PID of the systemd manager which started the process:
+// $MANAGERPID for user instances, the process of user@UID.service otherwise found thru the cgroups,
+// and 1 for the system manager. ok is false if not RunningUnderSystemd.
+func ManagerPID() (pid int, ok bool) {
+	if value, set := os.LookupEnv("MANAGERPID"); set {
+		if pid, err := strconv.Atoi(value); err == nil && pid > 0 {
+			return pid, true
+		}
+	}
+	if !RunningUnderSystemd() {
+		return
+	}
+	if !IsUserInstance() {
+		return 1, true
+	}
+	cu, err := systemd1.CurrentCgroupUnit()
+	if err != nil || cu.UserUnit == "" {
+		return
+	}
+	// the user manager runs in the init.scope of its user@UID.service unit
+	slice, _, found := strings.Cut(cu.Cgroup, "/"+cu.Unit+"/")
+	if !found {
+		return
+	}
+	data, err := os.ReadFile(filepath.Join(cgroupfs.Dir(slice), cu.Unit, "init.scope/cgroup.procs"))
+	if err != nil {
+		return
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return
+	}
+	if pid, err = strconv.Atoi(fields[0]); err != nil {
+		return 0, false
+	}
+	return pid, true
+}
+
+// IsMainProcess tells if the process is the main process of its unit, ie: it has been forked
+// by the manager itself and not by another process of the unit (eg: ExecReload= commands or children).
+// The children of Type=forking services are not detected as the main process.
+func IsMainProcess() bool {
+	if value, ok := os.LookupEnv("MAINPID"); ok {
+		// set for the control processes (ExecReload=, ExecStop=...) only
+		if pid, err := strconv.Atoi(value); err == nil && pid != os.Getpid() {
+			return false
+		}
+	}
+	pid, ok := ManagerPID()
+	return ok && os.Getppid() == pid
+}
+
+// serviceUnit returns the units the process belongs to according to its cgroup,
+// ok is false if it does not run in a service (of the system or user manager).
+func serviceUnit() (cu systemd1.CgroupUnit, ok bool) {
+	cu, err := systemd1.CurrentCgroupUnit()
+	if err != nil {
+		return
+	}
+	unit := cu.Unit
+	if cu.UserUnit != "" {
+		unit = cu.UserUnit
+	}
+	return cu, strings.HasSuffix(unit, ".service")
+}
@@ -0,0 +1,242 @@
+// Package sysdfdstore covers both sides of systemd's restart persistence story:
+// pushing open file descriptors into the service's fd store before stopping
+// (FileDescriptorStoreMax= must be set on the unit), and getting them back,
+// along with socket activated ones, as typed Go objects on the next start.
+package sysdfdstore
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+
+	sysdnotify "github.com/iguanesolutions/go-systemd/v6/notify"
+)
+
+// listenFDsStart is the first file descriptor passed by systemd (SD_LISTEN_FDS_START)
+const listenFDsStart = 3
+
+// ErrNotFound is returned when no file descriptor has been passed under the requested name.
+var ErrNotFound = errors.New("no file descriptor with that name")
+
+// Store holds the file descriptors passed by systemd at startup, indexed by name.
+// Files claimed with one of the getters are owned by the caller, the others are closed by Close().
+type Store struct {
+	files map[string][]*os.File
+}
+
+// Load returns the file descriptors passed by systemd thru LISTEN_FDS, LISTEN_PID and LISTEN_FDNAMES.
+// An empty store is returned if nothing has been passed or if they are meant for another process.
+// If unsetEnv is true, those variables are removed from the environment so children do not inherit them.
+func Load(unsetEnv bool) (*Store, error) {
+	if unsetEnv {
+		defer func() {
+			os.Unsetenv("LISTEN_PID")
+			os.Unsetenv("LISTEN_FDS")
+			os.Unsetenv("LISTEN_FDNAMES")
+		}()
+	}
+	s := &Store{
+		files: make(map[string][]*os.File),
+	}
+	names, err := parseEnv(os.Getenv("LISTEN_PID"), os.Getenv("LISTEN_FDS"), os.Getenv("LISTEN_FDNAMES"))
+	if err != nil || names == nil {
+		return s, err
+	}
+	for i, name := range names {
+		fd := listenFDsStart + i
+		syscall.CloseOnExec(fd)
+		s.files[name] = append(s.files[name], os.NewFile(uintptr(fd), name))
+	}
+	return s, nil
+}
+
+func parseEnv(pid, fds, fdNames string) (names []string, err error) {
+	if pid == "" || fds == "" {
+		return
+	}
+	pidTyped, err := strconv.Atoi(pid)
+	if err != nil {
+		return nil, fmt.Errorf("can't convert LISTEN_PID as int: %w", err)
+	}
+	if pidTyped != os.Getpid() {
+		return // not for us
+	}
+	nfds, err := strconv.Atoi(fds)
+	if err != nil {
+		return nil, fmt.Errorf("can't convert LISTEN_FDS as int: %w", err)
+	}
+	if nfds <= 0 {
+		return
+	}
+	names = make([]string, nfds)
+	var given []string
+	if fdNames != "" {
+		given = strings.Split(fdNames, ":")
+	}
+	for i := range names {
+		if i < len(given) && given[i] != "" {
+			names[i] = given[i]
+		} else {
+			names[i] = "unknown" // same default name as sd_listen_fds_with_names()
+		}
+	}
+	return
+}
+
+// Names returns the names of the file descriptors not claimed yet.
+func (s *Store) Names() []string {
+	names := make([]string, 0, len(s.files))
+	for name := range s.files {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Files returns and claims every file passed under name.
+func (s *Store) Files(name string) []*os.File {
+	files := s.files[name]
+	delete(s.files, name)
+	return files
+}
+
+// File returns and claims the first file passed under name.
+func (s *Store) File(name string) (*os.File, error) {
+	files := s.files[name]
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if len(files) == 1 {
+		delete(s.files, name)
+	} else {
+		s.files[name] = files[1:]
+	}
+	return files[0], nil
+}
+
+// Listener returns the first file passed under name as a net.Listener.
+func (s *Store) Listener(name string) (net.Listener, error) {
+	f, err := s.File(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() // net.FileListener works on a dup
+	return net.FileListener(f)
+}
+
+// PacketConn returns the first file passed under name as a net.PacketConn.
+func (s *Store) PacketConn(name string) (net.PacketConn, error) {
+	f, err := s.File(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() // net.FilePacketConn works on a dup
+	return net.FilePacketConn(f)
+}
+
+// Conn returns the first file passed under name as a net.Conn (eg: a database connection socket).
+func (s *Store) Conn(name string) (net.Conn, error) {
+	f, err := s.File(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() // net.FileConn works on a dup
+	return net.FileConn(f)
+}
+
+// Close closes the files which have not been claimed.
+func (s *Store) Close() (err error) {
+	for name, files := range s.files {
+		for _, f := range files {
+			if cerr := f.Close(); cerr != nil && err == nil {
+				err = cerr
+			}
+		}
+		delete(s.files, name)
+	}
+	return
+}
+
+// Push sends files to the fd store under name (FDSTORE=1), they are passed back on the next start.
+// The caller keeps ownership of files, systemd holds its own copies.
+// FDPOLL=0 is set automatically when one of the files can't be polled (regular files, directories).
+func Push(name string, files ...*os.File) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return errors.New("no file to push")
+	}
+	state := "FDSTORE=1\nFDNAME=" + name
+	fds := make([]int, len(files))
+	for i, f := range files {
+		fi, err := f.Stat()
+		if err != nil {
+			return fmt.Errorf("can't stat %s: %w", f.Name(), err)
+		}
+		if fi.Mode().IsRegular() || fi.IsDir() {
+			state = "FDSTORE=1\nFDPOLL=0\nFDNAME=" + name
+		}
+		// f.Fd() would switch the file to blocking mode
+		rc, err := f.SyscallConn()
+		if err != nil {
+			return fmt.Errorf("can't get %s file descriptor: %w", f.Name(), err)
+		}
+		if err = rc.Control(func(fd uintptr) { fds[i] = int(fd) }); err != nil {
+			return fmt.Errorf("can't get %s file descriptor: %w", f.Name(), err)
+		}
+	}
+	return sysdnotify.SendWithFDs(state, fds...)
+}
+
+// PushListener sends the file descriptor of l to the fd store under name.
+func PushListener(name string, l net.Listener) error {
+	return pushFiler(name, l)
+}
+
+// PushPacketConn sends the file descriptor of c to the fd store under name.
+func PushPacketConn(name string, c net.PacketConn) error {
+	return pushFiler(name, c)
+}
+
+// PushConn sends the file descriptor of c to the fd store under name.
+func PushConn(name string, c net.Conn) error {
+	return pushFiler(name, c)
+}
+
+func pushFiler(name string, v any) error {
+	filer, ok := v.(interface{ File() (*os.File, error) })
+	if !ok {
+		return fmt.Errorf("%T does not expose its file descriptor", v)
+	}
+	f, err := filer.File()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return Push(name, f)
+}
+
+// Remove removes every file descriptor stored under name from the fd store (FDSTOREREMOVE=1).
+func Remove(name string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	return sysdnotify.Send("FDSTOREREMOVE=1\nFDNAME=" + name)
+}
+
+// ValidateName checks that name is usable as a FDNAME: at most 255 printable ASCII characters, without ':'.
+func ValidateName(name string) error {
+	if name == "" || len(name) > 255 {
+		return fmt.Errorf("invalid fd name %q: length must be between 1 and 255", name)
+	}
+	for i := 0; i < len(name); i++ {
+		if c := name[i]; c <= ' ' || c >= 0x7f || c == ':' {
+			return fmt.Errorf("invalid fd name %q: only printable ASCII characters except ':' are allowed", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package sysdfdstore
+
+import (
+	"os"
+	"strconv"
+	"testing"
+)
+
+func TestParseEnv(t *testing.T) {
+	pid := strconv.Itoa(os.Getpid())
+	names, err := parseEnv(pid, "3", "http::db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"http", "unknown", "db"}
+	if len(names) != len(expected) {
+		t.Fatal("unexpected names", names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Error("unexpected name", i, names[i])
+		}
+	}
+	if names, err = parseEnv("1", "3", ""); err != nil || names != nil {
+		t.Error("fds meant for another process should be ignored", names, err)
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	for name, valid := range map[string]bool{
+		"http":     true,
+		"db-conn1": true,
+		"":         false,
+		"a:b":      false,
+		"a b":      false,
+	} {
+		if err := ValidateName(name); (err == nil) != valid {
+			t.Errorf("ValidateName(%q) = %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Package home1 is a pure Go implementation of the org.freedesktop.home1 dbus interface,
+// which allows to query and manage the home areas of systemd-homed.
+package home1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+const (
+	dbusDest      = "org.freedesktop.home1"
+	dbusInterface = "org.freedesktop.home1.Manager"
+	dbusPath      = "/org/freedesktop/home1"
+)
+
+// Conn represents a systemd-homed dbus connection.
+type Conn struct {
+	conn  *dbus.Conn
+	obj   dbus.BusObject
+	flags dbus.Flags
+}
+
+// ConnOption configures a Conn, see NewConn.
+type ConnOption func(c *Conn) error
+
+// WithInteractiveAuthorization sets the ALLOW_INTERACTIVE_AUTHORIZATION flag on method calls,
+// so polkit may prompt the user for privileged operations (eg: ActivateHome) instead of
+// failing with an access denied error. Calls may then block until the user answers.
+func WithInteractiveAuthorization() ConnOption {
+	return func(c *Conn) error {
+		c.flags |= dbus.FlagAllowInteractiveAuthorization
+		return nil
+	}
+}
+
+// NewConn returns a new and ready to use dbus connection.
+// You must close that connection when you have been done with it.
+func NewConn(opts ...ConnOption) (*Conn, error) {
+	c := &Conn{}
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+	conn, err := sysdbus.SystemBus()
+	if err != nil {
+		return nil, err
+	}
+	c.conn = conn
+	c.obj = conn.Object(dbusDest, dbus.ObjectPath(dbusPath))
+	return c, nil
+}
+
+// Call wraps obj.CallWithContext by using the connection flags (see WithInteractiveAuthorization)
+// and format the method with the dbus manager interface.
+func (c *Conn) Call(ctx context.Context, method string, args ...interface{}) *dbus.Call {
+	return c.obj.CallWithContext(ctx, fmt.Sprintf("%s.%s", dbusInterface, method), c.flags, args...)
+}
+
+// Close closes the current dbus connection.
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,27 @@
+package home1
+
+import (
+	"errors"
+
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+var (
+	// ErrNoSuchHome is returned when the user has no home area managed by homed.
+	ErrNoSuchHome = errors.New("no such home")
+	// ErrBadPassword is returned when the given secret does not unlock the home area.
+	ErrBadPassword = errors.New("bad password")
+	// ErrAccessDenied is returned when polkit (or the caller privileges) denied the operation,
+	// see WithInteractiveAuthorization.
+	ErrAccessDenied = sysdbus.ErrAccessDenied
+)
+
+var dbusErrors = map[string]error{
+	dbusDest + ".NoSuchHome":  ErrNoSuchHome,
+	dbusDest + ".BadPassword": ErrBadPassword,
+}
+
+// typedError wraps the known homed dbus errors into the Err* errors, so they can be tested with errors.Is.
+func typedError(err error) error {
+	return sysdbus.TypedError(err, dbusErrors)
+}
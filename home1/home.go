@@ -0,0 +1,121 @@
+package home1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// HomeStatus represents a home area as returned by ListHomes.
+type HomeStatus struct {
+	UserName      string
+	UID           uint32
+	State         string // eg: inactive, active, locked, absent, dirty
+	GID           uint32
+	RealName      string
+	HomeDirectory string
+	Shell         string
+	Path          dbus.ObjectPath
+}
+
+// ListHomes returns the home areas managed by homed.
+// ctx: Context to use
+func (c *Conn) ListHomes(ctx context.Context) (homes []HomeStatus, err error) {
+	err = c.Call(ctx, "ListHomes").Store(&homes)
+	return
+}
+
+// UserRecord holds the most used fields of a JSON user record, see https://systemd.io/USER_RECORD.
+// Fields missing from the record are left empty, Raw holds the whole record.
+type UserRecord struct {
+	UserName      string          `json:"userName"`
+	RealName      string          `json:"realName"`
+	UID           uint32          `json:"uid"`
+	GID           uint32          `json:"gid"`
+	MemberOf      []string        `json:"memberOf"`
+	HomeDirectory string          `json:"homeDirectory"`
+	Shell         string          `json:"shell"`
+	Disposition   string          `json:"disposition"` // eg: regular, system, intrinsic
+	Storage       string          `json:"storage"`     // eg: luks, directory, subvolume, fscrypt, cifs
+	Locked        bool            `json:"locked"`
+	DiskSize      uint64          `json:"diskSize"` // bytes
+	PasswordHint  string          `json:"passwordHint"`
+	EmailAddress  string          `json:"emailAddress"`
+	Location      string          `json:"location"`
+	IconName      string          `json:"iconName"`
+	LastChange    time.Time       `json:"-"`
+	Incomplete    bool            `json:"-"` // privileged sections were stripped, the caller lacking privileges
+	Raw           json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON parses a JSON user record.
+func (r *UserRecord) UnmarshalJSON(data []byte) error {
+	type plain UserRecord
+	aux := struct {
+		*plain
+		LastChangeUSec uint64 `json:"lastChangeUSec"`
+	}{plain: (*plain)(r)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if aux.LastChangeUSec != 0 {
+		r.LastChange = time.UnixMicro(int64(aux.LastChangeUSec))
+	}
+	r.Raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// GetUserRecordByName returns the user record of the home area of user.
+// ctx: Context to use
+// user: user name
+func (c *Conn) GetUserRecordByName(ctx context.Context, user string) (*UserRecord, error) {
+	var (
+		payload    string
+		incomplete bool
+		path       dbus.ObjectPath
+	)
+	if err := c.Call(ctx, "GetUserRecordByName", user).Store(&payload, &incomplete, &path); err != nil {
+		return nil, typedError(err)
+	}
+	r := &UserRecord{}
+	if err := json.Unmarshal([]byte(payload), r); err != nil {
+		return nil, fmt.Errorf("failed to parse user record: %w", err)
+	}
+	r.Incomplete = incomplete
+	return r, nil
+}
+
+// secret is the JSON secret record passed to the methods needing to unlock a home area.
+type secret struct {
+	Password []string `json:"password,omitempty"`
+}
+
+func secretJSON(passwords []string) (string, error) {
+	data, err := json.Marshal(secret{Password: passwords})
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// ActivateHome unlocks and mounts the home area of user, like logging in does.
+// ctx: Context to use
+// user: user name
+// passwords: candidate passwords, tried in order
+func (c *Conn) ActivateHome(ctx context.Context, user string, passwords ...string) error {
+	s, err := secretJSON(passwords)
+	if err != nil {
+		return err
+	}
+	return typedError(c.Call(ctx, "ActivateHome", user, s).Store())
+}
+
+// DeactivateHome unmounts and locks the home area of user, it fails while the user has processes using it.
+// ctx: Context to use
+// user: user name
+func (c *Conn) DeactivateHome(ctx context.Context, user string) error {
+	return typedError(c.Call(ctx, "DeactivateHome", user).Store())
+}
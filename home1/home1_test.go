@@ -0,0 +1,25 @@
+package home1
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestUserRecord(t *testing.T) {
+	payload := `{"userName": "alice", "realName": "Alice", "uid": 60001, "gid": 60001, "memberOf": ["wheel"],
+		"disposition": "regular", "storage": "luks", "lastChangeUSec": 1700000000000000,
+		"binding": {"0123456789abcdef0123456789abcdef": {"homeDirectory": "/home/alice"}}}`
+	var r UserRecord
+	if err := json.Unmarshal([]byte(payload), &r); err != nil {
+		t.Fatal(err)
+	}
+	if r.UserName != "alice" || r.UID != 60001 || len(r.MemberOf) != 1 || r.Storage != "luks" || r.LastChange.Unix() != 1700000000 {
+		t.Errorf("unexpected record: %+v", r)
+	}
+	if len(r.Raw) != len(payload) {
+		t.Error("raw record not kept")
+	}
+	if s, _ := secretJSON([]string{"hunter2"}); s != `{"password":["hunter2"]}` {
+		t.Errorf("unexpected secret %s", s)
+	}
+}
@@ -0,0 +1,81 @@
+// Package hostname1 is a pure Go implementation of the org.freedesktop.hostname1 dbus interface,
+// which allows to query and manage the hostname and machine metadata handled by systemd-hostnamed.
+package hostname1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+const (
+	dbusDest      = "org.freedesktop.hostname1"
+	dbusInterface = "org.freedesktop.hostname1"
+	dbusPath      = "/org/freedesktop/hostname1"
+)
+
+// Conn represents a systemd-hostnamed dbus connection.
+type Conn struct {
+	conn  *dbus.Conn
+	obj   dbus.BusObject
+	flags dbus.Flags
+}
+
+// ConnOption configures a Conn, see NewConn.
+type ConnOption func(c *Conn) error
+
+// WithInteractiveAuthorization sets the ALLOW_INTERACTIVE_AUTHORIZATION flag on method calls
+// and the interactive argument of the Set* methods, so polkit may prompt the user instead of
+// failing with an access denied error. Calls may then block until the user answers.
+func WithInteractiveAuthorization() ConnOption {
+	return func(c *Conn) error {
+		c.flags |= dbus.FlagAllowInteractiveAuthorization
+		return nil
+	}
+}
+
+// NewConn returns a new and ready to use dbus connection.
+// You must close that connection when you have been done with it.
+func NewConn(opts ...ConnOption) (*Conn, error) {
+	c := &Conn{}
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+	conn, err := sysdbus.SystemBus()
+	if err != nil {
+		return nil, err
+	}
+	c.conn = conn
+	c.obj = conn.Object(dbusDest, dbus.ObjectPath(dbusPath))
+	return c, nil
+}
+
+// Call wraps obj.CallWithContext by using the connection flags (see WithInteractiveAuthorization)
+// and format the method with the dbus hostname1 interface.
+func (c *Conn) Call(ctx context.Context, method string, args ...interface{}) *dbus.Call {
+	return c.obj.CallWithContext(ctx, fmt.Sprintf("%s.%s", dbusInterface, method), c.flags, args...)
+}
+
+// Close closes the current dbus connection.
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+// interactive tells if interactive authorization is allowed, for the methods taking it as argument.
+func (c *Conn) interactive() bool {
+	return c.flags&dbus.FlagAllowInteractiveAuthorization != 0
+}
+
+// stringProperty returns the string property name.
+func (c *Conn) stringProperty(ctx context.Context, name string) (string, error) {
+	v, err := sysdbus.GetProperty(ctx, c.obj, dbusInterface, name)
+	if err != nil {
+		return "", typedError(err)
+	}
+	s, _ := v.Value().(string)
+	return s, nil
+}
@@ -0,0 +1,87 @@
+package hostname1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Description holds the host facts returned by Describe, fields unknown to the running
+// systemd version are left empty.
+type Description struct {
+	Hostname                  string
+	StaticHostname            string
+	PrettyHostname            string
+	DefaultHostname           string
+	HostnameSource            string // static, transient or default
+	IconName                  string
+	Chassis                   string
+	Deployment                string
+	Location                  string
+	KernelName                string
+	KernelRelease             string
+	KernelVersion             string
+	OperatingSystemPrettyName string
+	OperatingSystemCPEName    string
+	OperatingSystemHomeURL    string
+	OperatingSystemSupportEnd time.Time         `json:"-"`
+	OSRelease                 map[string]string `json:"-"` // os-release(5) fields (eg: ID, VERSION_ID)
+	HardwareVendor            string
+	HardwareModel             string
+	HardwareSerial            string // only set for privileged callers
+	FirmwareVersion           string
+	FirmwareVendor            string
+	FirmwareDate              time.Time `json:"-"`
+	MachineID                 string
+	BootID                    string
+	ProductUUID               string // only set for privileged callers
+}
+
+// UnmarshalJSON parses the JSON payload of Describe.
+func (d *Description) UnmarshalJSON(data []byte) error {
+	type plain Description
+	aux := struct {
+		*plain
+		OperatingSystemSupportEnd  uint64
+		OperatingSystemReleaseData []string
+		FirmwareDate               uint64
+	}{plain: (*plain)(d)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	d.OperatingSystemSupportEnd = usecTime(aux.OperatingSystemSupportEnd)
+	d.FirmwareDate = usecTime(aux.FirmwareDate)
+	if len(aux.OperatingSystemReleaseData) > 0 {
+		d.OSRelease = make(map[string]string, len(aux.OperatingSystemReleaseData))
+		for _, kv := range aux.OperatingSystemReleaseData {
+			if k, v, ok := strings.Cut(kv, "="); ok {
+				d.OSRelease[k] = v
+			}
+		}
+	}
+	return nil
+}
+
+// Describe returns every host fact known by hostnamed in a single call (systemd v249 or later).
+// ctx: Context to use
+func (c *Conn) Describe(ctx context.Context) (*Description, error) {
+	var payload string
+	if err := c.Call(ctx, "Describe").Store(&payload); err != nil {
+		return nil, err
+	}
+	d := &Description{}
+	if err := json.Unmarshal([]byte(payload), d); err != nil {
+		return nil, fmt.Errorf("failed to parse description: %w", err)
+	}
+	return d, nil
+}
+
+// usecTime returns the µs since epoch usec as a time.Time, zero if unset.
+func usecTime(usec uint64) time.Time {
+	if usec == 0 || usec == ^uint64(0) {
+		return time.Time{}
+	}
+	return time.UnixMicro(int64(usec))
+}
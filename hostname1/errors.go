@@ -0,0 +1,27 @@
+package hostname1
+
+import (
+	"errors"
+
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+var (
+	// ErrNoProductUUID is returned when the firmware does not expose a product UUID.
+	ErrNoProductUUID = errors.New("no product UUID")
+	// ErrNoHardwareSerial is returned when the firmware does not expose a hardware serial.
+	ErrNoHardwareSerial = errors.New("no hardware serial")
+	// ErrAccessDenied is returned when polkit (or the caller privileges) denied the operation,
+	// see WithInteractiveAuthorization.
+	ErrAccessDenied = sysdbus.ErrAccessDenied
+)
+
+var dbusErrors = map[string]error{
+	dbusDest + ".NoProductUUID":    ErrNoProductUUID,
+	dbusDest + ".NoHardwareSerial": ErrNoHardwareSerial,
+}
+
+// typedError wraps the known hostnamed dbus errors into the Err* errors, so they can be tested with errors.Is.
+func typedError(err error) error {
+	return sysdbus.TypedError(err, dbusErrors)
+}
@@ -0,0 +1,88 @@
+package hostname1
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+// MachineIDState is the initialization state of /etc/machine-id.
+type MachineIDState int
+
+const (
+	// MachineIDMissing means /etc/machine-id is missing or empty: the image has not been booted yet
+	// (systemd will consider the next boot as the first one).
+	MachineIDMissing MachineIDState = iota
+	// MachineIDUninitialized means /etc/machine-id holds "uninitialized": the first boot is in progress
+	// and the machine ID will be committed once first boot units completed.
+	MachineIDUninitialized
+	// MachineIDInitialized means /etc/machine-id holds a machine ID.
+	MachineIDInitialized
+)
+
+func (s MachineIDState) String() string {
+	switch s {
+	case MachineIDMissing:
+		return "missing"
+	case MachineIDUninitialized:
+		return "uninitialized"
+	case MachineIDInitialized:
+		return "initialized"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	machineIDPath = "/etc/machine-id"
+	firstBootFlag = "/run/systemd/first-boot"
+)
+
+// GetMachineIDState returns the initialization state of /etc/machine-id.
+func GetMachineIDState() (MachineIDState, error) {
+	data, err := os.ReadFile(machineIDPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return MachineIDMissing, nil
+	}
+	if err != nil {
+		return MachineIDMissing, err
+	}
+	switch id := strings.TrimSpace(string(data)); id {
+	case "":
+		return MachineIDMissing, nil
+	case "uninitialized":
+		return MachineIDUninitialized, nil
+	default:
+		return MachineIDInitialized, nil
+	}
+}
+
+// FirstBoot tells if the system is in its first boot (ConditionFirstBoot=yes semantics),
+// provisioning flows may use it to decide whether to run their first boot setup.
+func FirstBoot() (bool, error) {
+	_, err := os.Stat(firstBootFlag)
+	if err == nil {
+		return true, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return false, err
+	}
+	// older systemd versions do not create the flag file
+	state, err := GetMachineIDState()
+	return state == MachineIDUninitialized, err
+}
+
+// MachineID returns the machine ID as known by hostnamed, as 32 hex digits.
+// ctx: Context to use
+func (c *Conn) MachineID(ctx context.Context) (string, error) {
+	v, err := sysdbus.GetProperty(ctx, c.obj, dbusInterface, "MachineID")
+	if err != nil {
+		return "", typedError(err)
+	}
+	id, _ := v.Value().([]byte)
+	return hex.EncodeToString(id), nil
+}
@@ -0,0 +1,64 @@
+package hostname1
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+// GetProductUUID returns the product UUID of the machine as exposed by the firmware (DMI), formatted
+// as 8-4-4-4-12 hex digits. It requires privileges and fails with ErrAccessDenied or ErrNoProductUUID.
+// ctx: Context to use
+func (c *Conn) GetProductUUID(ctx context.Context) (string, error) {
+	var b []byte
+	if err := typedError(c.Call(ctx, "GetProductUUID", c.interactive()).Store(&b)); err != nil {
+		return "", err
+	}
+	if len(b) != 16 {
+		return "", fmt.Errorf("invalid product UUID length: %d", len(b))
+	}
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// GetHardwareSerial returns the serial number of the machine as exposed by the firmware.
+// It requires privileges and fails with ErrAccessDenied or ErrNoHardwareSerial.
+// ctx: Context to use
+func (c *Conn) GetHardwareSerial(ctx context.Context) (serial string, err error) {
+	err = typedError(c.Call(ctx, "GetHardwareSerial").Store(&serial))
+	return
+}
+
+// Firmware holds the firmware information of the machine, fields are empty when unknown.
+type Firmware struct {
+	Version string
+	Vendor  string
+	Date    time.Time
+}
+
+// Firmware returns the firmware information of the machine.
+// ctx: Context to use
+func (c *Conn) Firmware(ctx context.Context) (*Firmware, error) {
+	props, err := sysdbus.GetAllProperties(ctx, c.obj, dbusInterface)
+	if err != nil {
+		return nil, err
+	}
+	return &Firmware{
+		Version: sysdbus.Prop[string](props, "FirmwareVersion"),
+		Vendor:  sysdbus.Prop[string](props, "FirmwareVendor"),
+		Date:    sysdbus.Timestamp(props, "FirmwareDate"),
+	}, nil
+}
+
+// HardwareVendor returns the hardware vendor of the machine.
+// ctx: Context to use
+func (c *Conn) HardwareVendor(ctx context.Context) (string, error) {
+	return c.stringProperty(ctx, "HardwareVendor")
+}
+
+// HardwareModel returns the hardware model of the machine.
+// ctx: Context to use
+func (c *Conn) HardwareModel(ctx context.Context) (string, error) {
+	return c.stringProperty(ctx, "HardwareModel")
+}
@@ -0,0 +1,96 @@
+package hostname1
+
+import "context"
+
+// Hostname returns the transient hostname, the one currently set in the kernel.
+// ctx: Context to use
+func (c *Conn) Hostname(ctx context.Context) (string, error) {
+	return c.stringProperty(ctx, "Hostname")
+}
+
+// StaticHostname returns the static hostname, stored in /etc/hostname, empty if unset.
+// ctx: Context to use
+func (c *Conn) StaticHostname(ctx context.Context) (string, error) {
+	return c.stringProperty(ctx, "StaticHostname")
+}
+
+// PrettyHostname returns the free form UTF-8 hostname presented to the user, empty if unset.
+// ctx: Context to use
+func (c *Conn) PrettyHostname(ctx context.Context) (string, error) {
+	return c.stringProperty(ctx, "PrettyHostname")
+}
+
+// IconName returns the icon name of the machine (eg: computer-laptop).
+// ctx: Context to use
+func (c *Conn) IconName(ctx context.Context) (string, error) {
+	return c.stringProperty(ctx, "IconName")
+}
+
+// Chassis returns the chassis type (eg: desktop, laptop, server, vm, container).
+// ctx: Context to use
+func (c *Conn) Chassis(ctx context.Context) (string, error) {
+	return c.stringProperty(ctx, "Chassis")
+}
+
+// Deployment returns the deployment environment (eg: development, staging, production).
+// ctx: Context to use
+func (c *Conn) Deployment(ctx context.Context) (string, error) {
+	return c.stringProperty(ctx, "Deployment")
+}
+
+// Location returns the free form location of the machine (eg: "Rack 2, Room 42").
+// ctx: Context to use
+func (c *Conn) Location(ctx context.Context) (string, error) {
+	return c.stringProperty(ctx, "Location")
+}
+
+// SetHostname sets the transient hostname, which is lost on reboot and may be overridden
+// by the network configuration (eg: DHCP).
+// ctx: Context to use
+// hostname: new transient hostname, empty to revert to the static or default hostname
+func (c *Conn) SetHostname(ctx context.Context, hostname string) error {
+	return typedError(c.Call(ctx, "SetHostname", hostname, c.interactive()).Store())
+}
+
+// SetStaticHostname sets the static hostname stored in /etc/hostname, which is also applied
+// as the transient hostname.
+// ctx: Context to use
+// hostname: new static hostname, empty to remove it
+func (c *Conn) SetStaticHostname(ctx context.Context, hostname string) error {
+	return typedError(c.Call(ctx, "SetStaticHostname", hostname, c.interactive()).Store())
+}
+
+// SetPrettyHostname sets the free form UTF-8 hostname presented to the user.
+// ctx: Context to use
+// hostname: new pretty hostname, empty to remove it
+func (c *Conn) SetPrettyHostname(ctx context.Context, hostname string) error {
+	return typedError(c.Call(ctx, "SetPrettyHostname", hostname, c.interactive()).Store())
+}
+
+// SetIconName sets the icon name of the machine.
+// ctx: Context to use
+// name: icon name, empty to use the default one
+func (c *Conn) SetIconName(ctx context.Context, name string) error {
+	return typedError(c.Call(ctx, "SetIconName", name, c.interactive()).Store())
+}
+
+// SetChassis sets the chassis type.
+// ctx: Context to use
+// chassis: chassis type (eg: server), empty to detect it
+func (c *Conn) SetChassis(ctx context.Context, chassis string) error {
+	return typedError(c.Call(ctx, "SetChassis", chassis, c.interactive()).Store())
+}
+
+// SetDeployment sets the deployment environment.
+// ctx: Context to use
+// deployment: deployment environment (eg: production), empty to remove it
+func (c *Conn) SetDeployment(ctx context.Context, deployment string) error {
+	return typedError(c.Call(ctx, "SetDeployment", deployment, c.interactive()).Store())
+}
+
+// SetLocation sets the location of the machine.
+// ctx: Context to use
+// location: free form location, empty to remove it
+func (c *Conn) SetLocation(ctx context.Context, location string) error {
+	return typedError(c.Call(ctx, "SetLocation", location, c.interactive()).Store())
+}
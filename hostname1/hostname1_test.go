@@ -0,0 +1,49 @@
+package hostname1
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDescription(t *testing.T) {
+	var d Description
+	err := json.Unmarshal([]byte(`{"Hostname":"web1","Chassis":"server","HardwareVendor":"ACME",`+
+		`"FirmwareDate":1700000000000000,"OperatingSystemReleaseData":["ID=debian","VERSION_ID=12"],`+
+		`"ProductUUID":null}`), &d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Hostname != "web1" || d.Chassis != "server" || d.HardwareVendor != "ACME" {
+		t.Errorf("unexpected description: %+v", d)
+	}
+	if !d.FirmwareDate.Equal(time.UnixMicro(1700000000000000)) || !d.OperatingSystemSupportEnd.IsZero() {
+		t.Errorf("unexpected timestamps: %s, %s", d.FirmwareDate, d.OperatingSystemSupportEnd)
+	}
+	if d.OSRelease["ID"] != "debian" || d.OSRelease["VERSION_ID"] != "12" {
+		t.Errorf("unexpected os-release: %v", d.OSRelease)
+	}
+}
+
+func TestHostnames(t *testing.T) {
+	for _, name := range []string{"web1", "web-1.example.com", "A1"} {
+		if !ValidHostname(name) {
+			t.Errorf("%q should be valid", name)
+		}
+	}
+	for _, name := range []string{"", "-web", "web-", "a..b", "Bob's Laptop", "été"} {
+		if ValidHostname(name) {
+			t.Errorf("%q should be invalid", name)
+		}
+	}
+	for pretty, expected := range map[string]string{
+		"Bob's Laptop":  "bobs-laptop",
+		"  Web Server ": "web-server",
+		"..a..b..":      "a.b",
+		"€€€":           "",
+	} {
+		if name := CleanHostname(pretty); name != expected {
+			t.Errorf("CleanHostname(%q) = %q, expected %q", pretty, name, expected)
+		}
+	}
+}
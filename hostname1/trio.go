@@ -0,0 +1,117 @@
+package hostname1
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+// HostnameMax is the maximum length of a hostname.
+const HostnameMax = 64
+
+// Hostnames holds the three hostnames of the machine:
+//   - the static hostname, stored in /etc/hostname, used at boot
+//   - the transient hostname, the one of the kernel, which defaults to the static one
+//     but may be changed at runtime (eg: by DHCP)
+//   - the pretty hostname, a free form UTF-8 name presented to the user ("Bob's Laptop")
+type Hostnames struct {
+	Static    string // empty if unset
+	Transient string
+	Pretty    string // empty if unset
+	Default   string // hostname used when there is no static hostname
+	Source    string // where the transient hostname comes from: static, transient or default
+}
+
+// Hostnames returns the static, transient and pretty hostnames at once.
+// ctx: Context to use
+func (c *Conn) Hostnames(ctx context.Context) (*Hostnames, error) {
+	props, err := sysdbus.GetAllProperties(ctx, c.obj, dbusInterface)
+	if err != nil {
+		return nil, typedError(err)
+	}
+	return &Hostnames{
+		Static:    sysdbus.Prop[string](props, "StaticHostname"),
+		Transient: sysdbus.Prop[string](props, "Hostname"),
+		Pretty:    sysdbus.Prop[string](props, "PrettyHostname"),
+		Default:   sysdbus.Prop[string](props, "DefaultHostname"),
+		Source:    sysdbus.Prop[string](props, "HostnameSource"),
+	}, nil
+}
+
+// SetTransientHostname is SetHostname: it only changes the kernel hostname until reboot,
+// use SetStaticHostname (or SetAllHostnames) to make it persistent.
+// ctx: Context to use
+// hostname: new transient hostname, empty to revert to the static or default hostname
+func (c *Conn) SetTransientHostname(ctx context.Context, hostname string) error {
+	return c.SetHostname(ctx, hostname)
+}
+
+// SetAllHostnames sets the three hostnames from name, like "hostnamectl hostname" does:
+// if name is a valid hostname it becomes the static (and transient) hostname and the pretty
+// hostname is removed, otherwise name becomes the pretty hostname and its cleaned up version
+// (see CleanHostname) the static one.
+// ctx: Context to use
+// name: hostname or pretty hostname
+func (c *Conn) SetAllHostnames(ctx context.Context, name string) error {
+	static, pretty := name, ""
+	if !ValidHostname(name) {
+		static, pretty = CleanHostname(name), name
+		if static == "" {
+			return errors.New("no valid hostname can be derived from " + name)
+		}
+	}
+	if err := c.SetPrettyHostname(ctx, pretty); err != nil {
+		return err
+	}
+	// the static hostname is applied as the transient one too
+	return c.SetStaticHostname(ctx, static)
+}
+
+// ValidHostname tells if name is a valid hostname: dot separated labels of ASCII letters,
+// digits and hyphens, not starting nor ending with a hyphen, HostnameMax characters at most.
+func ValidHostname(name string) bool {
+	if name == "" || len(name) > HostnameMax {
+		return false
+	}
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for i := 0; i < len(label); i++ {
+			if c := label[i]; !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// CleanHostname derives a valid hostname from a pretty one (eg: "Bob's Laptop" gives "bobs-laptop"),
+// it returns an empty string if nothing valid remains.
+func CleanHostname(pretty string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(pretty) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '-', r == '_', r == ' ', r == '\t':
+			b.WriteByte('-')
+		case r == '.':
+			b.WriteByte('.')
+		}
+	}
+	// drop empty labels and surrounding hyphens
+	var labels []string
+	for _, label := range strings.Split(b.String(), ".") {
+		if label = strings.Trim(label, "-"); label != "" {
+			labels = append(labels, label)
+		}
+	}
+	name := strings.Join(labels, ".")
+	for len(name) > HostnameMax {
+		name = strings.TrimRight(name[:HostnameMax], "-.")
+	}
+	return name
+}
@@ -0,0 +1,127 @@
+// Package sysdid128 handles the 128 bit IDs used by systemd (sd-id128): machine ID, boot ID,
+// invocation ID, and the application specific IDs derived from them.
+package sysdid128
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ID128 is a 128 bit ID.
+type ID128 [16]byte
+
+// Null is the all zero ID, used by systemd for unset IDs.
+var Null ID128
+
+// ErrInvalid is returned when a string is not a valid ID.
+var ErrInvalid = errors.New("invalid 128 bit ID")
+
+// Parse parses an ID either in plain form (32 hex digits) or in UUID form (8-4-4-4-12 hex digits).
+func Parse(s string) (ID128, error) {
+	var id ID128
+	plain := s
+	if len(s) == 36 {
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return Null, fmt.Errorf("%w: %q", ErrInvalid, s)
+		}
+		plain = strings.ReplaceAll(s, "-", "")
+	}
+	if len(plain) != 32 {
+		return Null, fmt.Errorf("%w: %q", ErrInvalid, s)
+	}
+	if _, err := hex.Decode(id[:], []byte(plain)); err != nil {
+		return Null, fmt.Errorf("%w: %q", ErrInvalid, s)
+	}
+	return id, nil
+}
+
+// String returns the ID in plain form (32 lowercase hex digits), as in /etc/machine-id.
+func (id ID128) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// UUID returns the ID in UUID form (8-4-4-4-12 lowercase hex digits), as in /proc/sys/kernel/random/boot_id.
+func (id ID128) UUID() string {
+	s := id.String()
+	return s[:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+}
+
+// IsNull tells if id is the all zero ID.
+func (id ID128) IsNull() bool {
+	return id == Null
+}
+
+// AppSpecific derives an application specific ID from id, like sd_id128_get_app_specific does:
+// the result is stable for a given id and app, but id can't be recovered from it. Use it instead of
+// exposing the machine or boot ID to the network.
+// app: application ID, a constant random ID chosen for the application (eg: with systemd-id128 new)
+func (id ID128) AppSpecific(app ID128) ID128 {
+	mac := hmac.New(sha256.New, id[:])
+	mac.Write(app[:])
+	var derived ID128
+	copy(derived[:], mac.Sum(nil))
+	// turn it into a v4 UUID
+	derived[6] = derived[6]&0x0f | 0x40
+	derived[8] = derived[8]&0x3f | 0x80
+	return derived
+}
+
+// MachineID returns the machine ID, read from /etc/machine-id.
+func MachineID() (ID128, error) {
+	return readID("/etc/machine-id")
+}
+
+// BootID returns the ID of the current boot, read from /proc/sys/kernel/random/boot_id.
+func BootID() (ID128, error) {
+	return readID("/proc/sys/kernel/random/boot_id")
+}
+
+// InvocationID returns the invocation ID of the unit the process runs in, from $INVOCATION_ID.
+// It fails if the process has not been started by systemd (v232 or later).
+func InvocationID() (ID128, error) {
+	s, ok := os.LookupEnv("INVOCATION_ID")
+	if !ok {
+		return Null, errors.New("INVOCATION_ID is not set")
+	}
+	return Parse(s)
+}
+
+// MachineAppSpecific returns the machine ID derived for app, see ID128.AppSpecific.
+// app: application ID
+func MachineAppSpecific(app ID128) (ID128, error) {
+	id, err := MachineID()
+	if err != nil {
+		return Null, err
+	}
+	return id.AppSpecific(app), nil
+}
+
+// BootAppSpecific returns the boot ID derived for app, see ID128.AppSpecific.
+// app: application ID
+func BootAppSpecific(app ID128) (ID128, error) {
+	id, err := BootID()
+	if err != nil {
+		return Null, err
+	}
+	return id.AppSpecific(app), nil
+}
+
+func readID(path string) (ID128, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Null, err
+	}
+	id, err := Parse(strings.TrimSpace(string(data)))
+	if err != nil {
+		return Null, fmt.Errorf("%s: %w", path, err)
+	}
+	if id.IsNull() {
+		return Null, fmt.Errorf("%s: %w: null ID", path, ErrInvalid)
+	}
+	return id, nil
+}
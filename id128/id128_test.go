@@ -0,0 +1,37 @@
+package sysdid128
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	plain, err := Parse("0123456789abcdef0123456789abcdef")
+	if err != nil {
+		t.Fatal(err)
+	}
+	uuid, err := Parse("01234567-89ab-cdef-0123-456789ABCDEF")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain != uuid || plain.UUID() != "01234567-89ab-cdef-0123-456789abcdef" {
+		t.Errorf("unexpected IDs %s and %s", plain, uuid.UUID())
+	}
+	for _, s := range []string{"", "0123", "0123456789abcdef0123456789abcdeg", "01234567-89ab-cdef-0123_456789abcdef"} {
+		if _, err = Parse(s); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Parse(%q) = %v", s, err)
+		}
+	}
+}
+
+func TestAppSpecific(t *testing.T) {
+	base, _ := Parse("0123456789abcdef0123456789abcdef")
+	app, _ := Parse("fedcba9876543210fedcba9876543210")
+	id := base.AppSpecific(app)
+	if id == base || id != base.AppSpecific(app) {
+		t.Errorf("unexpected derived ID %s", id)
+	}
+	if id[6]>>4 != 4 || id[8]>>6 != 2 {
+		t.Errorf("derived ID %s is not a v4 UUID", id.UUID())
+	}
+}
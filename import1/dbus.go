@@ -0,0 +1,150 @@
+// Package import1 is a pure Go implementation of the org.freedesktop.import1 dbus interface,
+// which allows to import, export and download machine images with systemd-importd.
+package import1
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+const (
+	dbusDest      = "org.freedesktop.import1"
+	dbusInterface = "org.freedesktop.import1.Manager"
+	dbusPath      = "/org/freedesktop/import1"
+)
+
+// Conn represents a systemd-importd dbus connection.
+type Conn struct {
+	conn  *dbus.Conn
+	obj   dbus.BusObject
+	flags dbus.Flags
+
+	sigs      chan *dbus.Signal
+	mu        sync.Mutex
+	started   map[uint32]struct{} // transfers started by this Conn and not removed yet
+	results   map[uint32]string   // results of the removed transfers started by this Conn, not waited yet
+	unclaimed []removedTransfer   // latest transfers removed before start recorded them, if ever
+	removed   chan struct{}       // closed and renewed when a transfer is removed
+}
+
+type removedTransfer struct {
+	id     uint32
+	result string
+}
+
+// maxUnclaimed bounds the results kept for transfers not (yet) known to be started by the Conn:
+// the TransferRemoved signal of a quick transfer may be dispatched before its start call returns.
+const maxUnclaimed = 16
+
+// ConnOption configures a Conn, see NewConn.
+type ConnOption func(c *Conn) error
+
+// WithInteractiveAuthorization sets the ALLOW_INTERACTIVE_AUTHORIZATION flag on method calls,
+// so polkit may prompt the user for privileged operations (eg: PullTar, ImportRaw) instead of
+// failing with an access denied error. Calls may then block until the user answers.
+func WithInteractiveAuthorization() ConnOption {
+	return func(c *Conn) error {
+		c.flags |= dbus.FlagAllowInteractiveAuthorization
+		return nil
+	}
+}
+
+// NewConn returns a new and ready to use dbus connection.
+// You must close that connection when you have been done with it.
+func NewConn(opts ...ConnOption) (*Conn, error) {
+	c := &Conn{
+		sigs:    make(chan *dbus.Signal, 16),
+		started: make(map[uint32]struct{}),
+		results: make(map[uint32]string),
+		removed: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+	conn, err := sysdbus.SystemBus()
+	if err != nil {
+		return nil, err
+	}
+	c.conn = conn
+	c.obj = conn.Object(dbusDest, dbus.ObjectPath(dbusPath))
+	// transfers results are only known thru the TransferRemoved signal: listen to it
+	// from the start so it is not missed for transfers ending quickly
+	err = conn.AddMatchSignal(
+		dbus.WithMatchObjectPath(dbusPath),
+		dbus.WithMatchInterface(dbusInterface),
+		dbus.WithMatchMember("TransferRemoved"),
+	)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to add TransferRemoved signal match: %w", err)
+	}
+	conn.Signal(c.sigs)
+	go c.dispatch()
+	return c, nil
+}
+
+// Call wraps obj.CallWithContext by using the connection flags (see WithInteractiveAuthorization)
+// and format the method with the dbus manager interface.
+func (c *Conn) Call(ctx context.Context, method string, args ...interface{}) *dbus.Call {
+	return c.obj.CallWithContext(ctx, fmt.Sprintf("%s.%s", dbusInterface, method), c.flags, args...)
+}
+
+// Close closes the current dbus connection.
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+// dispatch records the TransferRemoved results until the connection is closed.
+func (c *Conn) dispatch() {
+	for sig := range c.sigs {
+		if sig.Name != dbusInterface+".TransferRemoved" {
+			continue
+		}
+		var (
+			id     uint32
+			path   dbus.ObjectPath
+			result string
+		)
+		if dbus.Store(sig.Body, &id, &path, &result) != nil {
+			continue
+		}
+		c.transferRemoved(id, result)
+	}
+}
+
+// transferRemoved records the result of the transfer id if it has been started by this Conn.
+func (c *Conn) transferRemoved(id uint32, result string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.started[id]; ok {
+		delete(c.started, id)
+		c.results[id] = result
+		close(c.removed)
+		c.removed = make(chan struct{})
+		return
+	}
+	if len(c.unclaimed) == maxUnclaimed {
+		c.unclaimed = append(c.unclaimed[:0], c.unclaimed[1:]...)
+	}
+	c.unclaimed = append(c.unclaimed, removedTransfer{id: id, result: result})
+}
+
+// transferStarted marks the transfer id as started by this Conn, so its result is recorded for Wait.
+func (c *Conn) transferStarted(id uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, t := range c.unclaimed {
+		if t.id == id {
+			c.unclaimed = append(c.unclaimed[:i], c.unclaimed[i+1:]...)
+			c.results[id] = t.result
+			return
+		}
+	}
+	c.started[id] = struct{}{}
+}
@@ -0,0 +1,29 @@
+package import1
+
+import (
+	"errors"
+
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+var (
+	// ErrNoSuchTransfer is returned when the transfer does not exist (anymore).
+	ErrNoSuchTransfer = errors.New("no such transfer")
+	// ErrTransferInProgress is returned when a transfer to the same local image is already running.
+	ErrTransferInProgress = errors.New("transfer in progress")
+	// ErrTransferFailed is returned by Transfer.Wait when the transfer failed or was canceled.
+	ErrTransferFailed = errors.New("transfer failed")
+	// ErrAccessDenied is returned when polkit (or the caller privileges) denied the operation,
+	// see WithInteractiveAuthorization.
+	ErrAccessDenied = sysdbus.ErrAccessDenied
+)
+
+var dbusErrors = map[string]error{
+	dbusDest + ".NoSuchTransfer":     ErrNoSuchTransfer,
+	dbusDest + ".TransferInProgress": ErrTransferInProgress,
+}
+
+// typedError wraps the known importd dbus errors into the Err* errors, so they can be tested with errors.Is.
+func typedError(err error) error {
+	return sysdbus.TypedError(err, dbusErrors)
+}
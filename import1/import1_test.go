@@ -0,0 +1,50 @@
+package import1
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestWait(t *testing.T) {
+	c := &Conn{
+		results: map[uint32]string{1: "done", 2: "canceled"},
+		removed: make(chan struct{}),
+	}
+	var last float64
+	progress := func(p float64) {
+		last = p
+	}
+	if err := (&Transfer{ID: 1, c: c}).Wait(context.Background(), progress); err != nil || last != 1 {
+		t.Errorf("unexpected result %v, progress %v", err, last)
+	}
+	if err := (&Transfer{ID: 2, c: c}).Wait(context.Background(), nil); !errors.Is(err, ErrTransferFailed) {
+		t.Errorf("unexpected result %v", err)
+	}
+	if len(c.results) != 0 {
+		t.Errorf("results not consumed: %v", c.results)
+	}
+}
+
+func TestTransferRemoved(t *testing.T) {
+	c := &Conn{
+		started: make(map[uint32]struct{}),
+		results: make(map[uint32]string),
+		removed: make(chan struct{}),
+	}
+	c.transferStarted(1)
+	c.transferRemoved(1, "done")
+	// removed before its start call returned
+	c.transferRemoved(2, "failed")
+	c.transferStarted(2)
+	if len(c.results) != 2 || c.results[1] != "done" || c.results[2] != "failed" || len(c.started) != 0 {
+		t.Errorf("unexpected results %v, started %v", c.results, c.started)
+	}
+	// transfers started by other clients
+	for id := uint32(100); id < 200; id++ {
+		c.transferRemoved(id, "done")
+	}
+	if len(c.results) != 2 || len(c.unclaimed) != maxUnclaimed {
+		t.Errorf("foreign transfers should not be kept: %d results, %d unclaimed", len(c.results), len(c.unclaimed))
+	}
+}
@@ -0,0 +1,164 @@
+package import1
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+const transferInterface = "org.freedesktop.import1.Transfer"
+
+// ProgressPollInterval is the interval at which Transfer.Wait reports the progress.
+const ProgressPollInterval = time.Second
+
+// VerifyMode is the verification mode of the downloaded images.
+type VerifyMode string
+
+const (
+	VerifyNo        VerifyMode = "no"
+	VerifyChecksum  VerifyMode = "checksum"  // SHA256SUMS file
+	VerifySignature VerifyMode = "signature" // SHA256SUMS file and its GPG signature
+)
+
+// TransferStatus represents a transfer as returned by ListTransfers.
+type TransferStatus struct {
+	ID       uint32
+	Type     string // eg: import-tar, pull-raw
+	Remote   string // URL or file name
+	Local    string // local image name
+	Progress float64
+	Path     dbus.ObjectPath
+}
+
+// ListTransfers returns the running transfers.
+// ctx: Context to use
+func (c *Conn) ListTransfers(ctx context.Context) (transfers []TransferStatus, err error) {
+	err = c.Call(ctx, "ListTransfers").Store(&transfers)
+	return
+}
+
+// CancelTransfer cancels the transfer id.
+// ctx: Context to use
+// id: transfer ID
+func (c *Conn) CancelTransfer(ctx context.Context, id uint32) error {
+	return typedError(c.Call(ctx, "CancelTransfer", id).Store())
+}
+
+// Transfer is a running importd transfer.
+type Transfer struct {
+	ID   uint32
+	Path dbus.ObjectPath
+	c    *Conn
+	obj  dbus.BusObject
+}
+
+func (c *Conn) start(ctx context.Context, method string, args ...interface{}) (*Transfer, error) {
+	t := &Transfer{c: c}
+	if err := c.Call(ctx, method, args...).Store(&t.ID, &t.Path); err != nil {
+		return nil, typedError(err)
+	}
+	c.transferStarted(t.ID)
+	t.obj = c.conn.Object(dbusDest, t.Path)
+	return t, nil
+}
+
+// ImportTar imports the tarball read from f as the image localName (compression is detected).
+// ctx: Context to use
+// f: tarball
+// localName: image name
+// force: replace an existing image of the same name
+// readOnly: make the image read only
+func (c *Conn) ImportTar(ctx context.Context, f *os.File, localName string, force, readOnly bool) (*Transfer, error) {
+	return c.start(ctx, "ImportTar", dbus.UnixFD(f.Fd()), localName, force, readOnly)
+}
+
+// ImportRaw imports the raw disk image read from f as the image localName (compression is detected).
+// ctx: Context to use
+// f: raw disk image
+// localName: image name
+// force: replace an existing image of the same name
+// readOnly: make the image read only
+func (c *Conn) ImportRaw(ctx context.Context, f *os.File, localName string, force, readOnly bool) (*Transfer, error) {
+	return c.start(ctx, "ImportRaw", dbus.UnixFD(f.Fd()), localName, force, readOnly)
+}
+
+// PullTar downloads the tarball at url as the image localName.
+// ctx: Context to use
+// url: http or https URL
+// localName: image name, empty to derive it from url
+// verify: verification mode
+// force: replace an existing image of the same name
+func (c *Conn) PullTar(ctx context.Context, url, localName string, verify VerifyMode, force bool) (*Transfer, error) {
+	return c.start(ctx, "PullTar", url, localName, string(verify), force)
+}
+
+// PullRaw downloads the raw disk image at url as the image localName.
+// ctx: Context to use
+// url: http or https URL
+// localName: image name, empty to derive it from url
+// verify: verification mode
+// force: replace an existing image of the same name
+func (c *Conn) PullRaw(ctx context.Context, url, localName string, verify VerifyMode, force bool) (*Transfer, error) {
+	return c.start(ctx, "PullRaw", url, localName, string(verify), force)
+}
+
+// Progress returns the progress of the transfer, between 0 and 1.
+// ctx: Context to use
+func (t *Transfer) Progress(ctx context.Context) (float64, error) {
+	v, err := sysdbus.GetProperty(ctx, t.obj, transferInterface, "Progress")
+	if err != nil {
+		return 0, typedError(err)
+	}
+	p, _ := v.Value().(float64)
+	return p, nil
+}
+
+// Cancel cancels the transfer.
+// ctx: Context to use
+func (t *Transfer) Cancel(ctx context.Context) error {
+	return t.c.CancelTransfer(ctx, t.ID)
+}
+
+// Wait blocks until the transfer ends, calling progress (if not nil) every ProgressPollInterval.
+// It fails with ErrTransferFailed if the transfer failed or was canceled. The transfer
+// is not canceled when ctx is done.
+// ctx: Context to use
+// progress: progress callback, receiving values between 0 and 1
+func (t *Transfer) Wait(ctx context.Context, progress func(float64)) error {
+	ticker := time.NewTicker(ProgressPollInterval)
+	defer ticker.Stop()
+	for {
+		t.c.mu.Lock()
+		result, done := t.c.results[t.ID]
+		if done {
+			delete(t.c.results, t.ID)
+		}
+		removed := t.c.removed
+		t.c.mu.Unlock()
+		if done {
+			if result != "done" {
+				return fmt.Errorf("%w: transfer %d result: %s", ErrTransferFailed, t.ID, result)
+			}
+			if progress != nil {
+				progress(1)
+			}
+			return nil
+		}
+		select {
+		case <-removed:
+		case <-ticker.C:
+			if progress == nil {
+				continue
+			}
+			if p, err := t.Progress(ctx); err == nil {
+				progress(p)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
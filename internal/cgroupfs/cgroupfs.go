@@ -0,0 +1,66 @@
+// Package cgroupfs locates the cgroup of processes in the cgroup hierarchy systemd manages.
+package cgroupfs
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Root is the mount point of the cgroup v2 hierarchy (of the cgroup v1 hierarchies on legacy systems).
+const Root = "/sys/fs/cgroup"
+
+// Dir returns the directory of the cgroup path in the systemd hierarchy:
+// under Root with cgroup v2, under its name=systemd hierarchy otherwise.
+func Dir(cgroup string) string {
+	if Unified() {
+		return filepath.Join(Root, cgroup)
+	}
+	return filepath.Join(Root, "systemd", cgroup)
+}
+
+// Read returns the cgroup path of the process pid (0 being the caller) in the systemd hierarchy
+// by parsing /proc/<pid>/cgroup, eg: /system.slice/foo.service.
+func Read(pid int) (string, error) {
+	proc := "self"
+	if pid > 0 {
+		proc = strconv.Itoa(pid)
+	}
+	fd, err := os.Open("/proc/" + proc + "/cgroup")
+	if err != nil {
+		return "", err
+	}
+	defer fd.Close()
+	return Parse(fd)
+}
+
+// Parse returns the cgroup path of the systemd hierarchy in /proc/<pid>/cgroup content:
+// the name=systemd one (cgroup v1 and hybrid) or the unified one (cgroup v2).
+func Parse(r io.Reader) (string, error) {
+	var unified string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		// hierarchy-ID:controller-list:cgroup-path
+		fields := strings.SplitN(scanner.Text(), ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		switch {
+		case fields[1] == "name=systemd":
+			return fields[2], nil
+		case fields[0] == "0" && fields[1] == "":
+			unified = fields[2]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	if unified == "" {
+		return "", errors.New("systemd cgroup hierarchy not found")
+	}
+	return unified, nil
+}
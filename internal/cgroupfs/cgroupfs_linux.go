@@ -0,0 +1,12 @@
+package cgroupfs
+
+import "syscall"
+
+// cgroup2SuperMagic is the filesystem type of cgroup v2 (CGROUP2_SUPER_MAGIC).
+const cgroup2SuperMagic = 0x63677270
+
+// Unified tells if Root is the cgroup v2 hierarchy, like systemd cg_all_unified does.
+func Unified() bool {
+	var fs syscall.Statfs_t
+	return syscall.Statfs(Root, &fs) == nil && fs.Type == cgroup2SuperMagic
+}
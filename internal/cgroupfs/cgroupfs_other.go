@@ -0,0 +1,8 @@
+//go:build !linux
+
+package cgroupfs
+
+// Unified tells if Root is the cgroup v2 hierarchy, cgroups only exist on Linux.
+func Unified() bool {
+	return false
+}
@@ -0,0 +1,20 @@
+package cgroupfs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	for content, expected := range map[string]string{
+		"0::/system.slice/nginx.service\n":                                     "/system.slice/nginx.service",
+		"12:cpu,cpuacct:/\n1:name=systemd:/system.slice/nginx.service\n0::/\n": "/system.slice/nginx.service",
+	} {
+		if cgroup, err := Parse(strings.NewReader(content)); err != nil || cgroup != expected {
+			t.Errorf("Parse(%q) = %q, %v", content, cgroup, err)
+		}
+	}
+	if _, err := Parse(strings.NewReader("12:cpu,cpuacct:/\n")); err == nil {
+		t.Error("expected an error without systemd hierarchy")
+	}
+}
@@ -0,0 +1,62 @@
+// Package osrelease parses os-release(5) like files, also used for machine-info(5).
+package osrelease
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// Read returns the os-release fields of the running host, from /etc/os-release
+// or /usr/lib/os-release if the former does not exist.
+func Read() (map[string]string, error) {
+	data, err := os.ReadFile("/etc/os-release")
+	if errors.Is(err, fs.ErrNotExist) {
+		data, err = os.ReadFile("/usr/lib/os-release")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data), nil
+}
+
+// Parse parses os-release(5) content: comments and invalid lines are skipped and values are unquoted.
+func Parse(data []byte) map[string]string {
+	fields := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		k, v, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		fields[k] = unquote(v)
+	}
+	return fields
+}
+
+// unquote removes the shell quotes of v: single quoted values are literal, and backslash
+// escapes the next character in double quoted values.
+func unquote(v string) string {
+	if len(v) < 2 || (v[0] != '"' && v[0] != '\'') || v[len(v)-1] != v[0] {
+		return v
+	}
+	quote, v := v[0], v[1:len(v)-1]
+	if quote == '\'' {
+		return v
+	}
+	var b strings.Builder
+	for i := 0; i < len(v); i++ {
+		if v[i] == '\\' && i+1 < len(v) {
+			i++
+		}
+		b.WriteByte(v[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,14 @@
+package osrelease
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	fields := Parse([]byte("# comment\nID=debian\nPRETTY_NAME=\"Debian GNU/Linux 12 (bookworm)\"\nVERSION_ID='12'\n" +
+		"\nbroken\nVARIANT=\"say \\\"hi\\\" \\\\o/\"\n"))
+	if len(fields) != 4 || fields["ID"] != "debian" || fields["PRETTY_NAME"] != "Debian GNU/Linux 12 (bookworm)" || fields["VERSION_ID"] != "12" {
+		t.Errorf("unexpected fields: %v", fields)
+	}
+	if v := fields["VARIANT"]; v != `say "hi" \o/` {
+		t.Errorf("unexpected unquoting: %q", v)
+	}
+}
@@ -0,0 +1,78 @@
+package sysdbus
+
+import (
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// PropertyChange is a PropertiesChanged signal of a dbus interface.
+type PropertyChange struct {
+	Changed     map[string]dbus.Variant // changed properties and their new value
+	Invalidated []string                // changed properties which value must be read again
+}
+
+// Has tells if the property name changed.
+func (ch PropertyChange) Has(name string) bool {
+	if _, ok := ch.Changed[name]; ok {
+		return true
+	}
+	for _, n := range ch.Invalidated {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// PropertySubscription delivers PropertyChange events, see SubscribeProperties.
+type PropertySubscription struct {
+	// C receives the changes, it is closed by Close.
+	C <-chan PropertyChange
+
+	out  chan PropertyChange
+	stop func()
+	done chan struct{}
+	once sync.Once
+	mu   sync.Mutex
+}
+
+// SubscribeProperties returns a new subscription to the property changes of the iface interface
+// of the object path, on top of WatchProperties. Close must be called once done with it.
+func SubscribeProperties(conn *dbus.Conn, path dbus.ObjectPath, iface string) (*PropertySubscription, error) {
+	s := &PropertySubscription{
+		out:  make(chan PropertyChange, 16),
+		done: make(chan struct{}),
+	}
+	s.C = s.out
+	stop, err := WatchProperties(conn, path, iface, func(changed map[string]dbus.Variant, invalidated []string) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		select {
+		case <-s.done:
+			// closed while waiting for the lock
+			return
+		default:
+		}
+		select {
+		case s.out <- PropertyChange{Changed: changed, Invalidated: invalidated}:
+		case <-s.done:
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.stop = stop
+	return s, nil
+}
+
+// Close stops the subscription and closes its channel.
+func (s *PropertySubscription) Close() {
+	s.once.Do(func() {
+		close(s.done)
+		s.stop()
+		s.mu.Lock()
+		close(s.out)
+		s.mu.Unlock()
+	})
+}
@@ -0,0 +1,35 @@
+package sysdbus
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// ErrAccessDenied is returned when polkit (or the caller privileges) denied the operation.
+// The dbus packages re-export it so it can be tested with errors.Is whatever the package.
+var ErrAccessDenied = errors.New("access denied")
+
+var accessDeniedErrors = map[string]struct{}{
+	"org.freedesktop.DBus.Error.AccessDenied":                     {},
+	"org.freedesktop.DBus.Error.InteractiveAuthorizationRequired": {},
+}
+
+// TypedError wraps err into the error known maps its dbus error name to, or into ErrAccessDenied
+// for the access denied errors, so it can be tested with errors.Is. Other errors are returned as is.
+// err: error returned by a method call
+// known: daemon specific errors by dbus error name
+func TypedError(err error, known map[string]error) error {
+	var dbusErr dbus.Error
+	if !errors.As(err, &dbusErr) {
+		return err
+	}
+	if typed, ok := known[dbusErr.Name]; ok {
+		return fmt.Errorf("%w: %w", typed, err)
+	}
+	if _, ok := accessDeniedErrors[dbusErr.Name]; ok {
+		return fmt.Errorf("%w: %w", ErrAccessDenied, err)
+	}
+	return err
+}
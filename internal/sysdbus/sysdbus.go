@@ -0,0 +1,186 @@
+// Package sysdbus holds the dbus plumbing shared by the systemd dbus packages.
+package sysdbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const propertiesInterface = "org.freedesktop.DBus.Properties"
+
+// SystemBus returns a new private and authenticated connection to the system bus.
+func SystemBus() (*dbus.Conn, error) {
+	conn, err := dbus.SystemBusPrivate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init private conn to system bus: %w", err)
+	}
+	return authAndHello(conn)
+}
+
+// SessionBus returns a new private and authenticated connection to the session bus.
+func SessionBus() (*dbus.Conn, error) {
+	conn, err := dbus.SessionBusPrivate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init private conn to session bus: %w", err)
+	}
+	return authAndHello(conn)
+}
+
+// UserManagerPrivate returns a new authenticated peer to peer connection to the private socket
+// of the per-user systemd instance ($XDG_RUNTIME_DIR/systemd/private), which works without session bus.
+func UserManagerPrivate() (*dbus.Conn, error) {
+	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	if runtimeDir == "" {
+		return nil, errors.New("XDG_RUNTIME_DIR is not set")
+	}
+	conn, err := dbus.Dial("unix:path=" + runtimeDir + "/systemd/private")
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial user manager private socket: %w", err)
+	}
+	methods := []dbus.Auth{dbus.AuthExternal(strconv.Itoa(os.Getuid()))}
+	if err = conn.Auth(methods); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to auth with external method: %w", err)
+	}
+	// no Hello: there is no bus daemon on a peer to peer connection
+	return conn, nil
+}
+
+func authAndHello(conn *dbus.Conn) (*dbus.Conn, error) {
+	methods := []dbus.Auth{dbus.AuthExternal(strconv.Itoa(os.Getuid()))}
+	err := conn.Auth(methods)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to auth with external method: %w", err)
+	}
+	err = conn.Hello()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to make hello call: %w", err)
+	}
+	return conn, nil
+}
+
+// GetProperty returns the value of the property iface.name of obj.
+func GetProperty(ctx context.Context, obj dbus.BusObject, iface, name string) (v dbus.Variant, err error) {
+	err = obj.CallWithContext(ctx, propertiesInterface+".Get", 0, iface, name).Store(&v)
+	return
+}
+
+// GetAllProperties returns every property of the iface interface of obj.
+func GetAllProperties(ctx context.Context, obj dbus.BusObject, iface string) (props map[string]dbus.Variant, err error) {
+	err = obj.CallWithContext(ctx, propertiesInterface+".GetAll", 0, iface).Store(&props)
+	return
+}
+
+// SetProperty sets the property iface.name of obj to value.
+func SetProperty(ctx context.Context, obj dbus.BusObject, flags dbus.Flags, iface, name string, value interface{}) error {
+	return obj.CallWithContext(ctx, propertiesInterface+".Set", flags, iface, name, dbus.MakeVariant(value)).Store()
+}
+
+// Prop returns the property name from props, or the zero value if missing or of another type.
+func Prop[T any](props map[string]dbus.Variant, name string) (v T) {
+	if variant, ok := props[name]; ok {
+		v, _ = variant.Value().(T)
+	}
+	return
+}
+
+// Timestamp returns the µs since epoch property name as a time.Time, zero if unset.
+func Timestamp(props map[string]dbus.Variant, name string) time.Time {
+	usec := Prop[uint64](props, name)
+	if usec == 0 {
+		return time.Time{}
+	}
+	return time.UnixMicro(int64(usec))
+}
+
+// StoreProp stores the property name of props, a dbus struct or array of structs, into dst.
+// dst is left untouched if the property is missing.
+func StoreProp(props map[string]dbus.Variant, name string, dst interface{}) error {
+	v, ok := props[name]
+	if !ok {
+		return nil
+	}
+	if err := dbus.Store([]interface{}{v.Value()}, dst); err != nil {
+		return fmt.Errorf("failed to store property %s: %w", name, err)
+	}
+	return nil
+}
+
+// UnescapePathElement reverts the bus path escaping (_xx for every byte not in [A-Za-z0-9])
+// used by systemd for object path elements (eg: unit names, session IDs).
+func UnescapePathElement(escaped string) string {
+	var b strings.Builder
+	for i := 0; i < len(escaped); i++ {
+		if escaped[i] == '_' && i+2 < len(escaped) {
+			if v, err := strconv.ParseUint(escaped[i+1:i+3], 16, 8); err == nil {
+				b.WriteByte(byte(v))
+				i += 2
+				continue
+			}
+		}
+		b.WriteByte(escaped[i])
+	}
+	return b.String()
+}
+
+// WatchProperties calls fn for every PropertiesChanged signal of the iface interface of the object path,
+// from a dedicated goroutine, until stop is called. fn may block: signals are queued meanwhile.
+func WatchProperties(conn *dbus.Conn, path dbus.ObjectPath, iface string, fn func(changed map[string]dbus.Variant, invalidated []string)) (stop func(), err error) {
+	opts := []dbus.MatchOption{
+		dbus.WithMatchObjectPath(path),
+		dbus.WithMatchInterface(propertiesInterface),
+		dbus.WithMatchMember("PropertiesChanged"),
+		dbus.WithMatchArg(0, iface),
+	}
+	if err = conn.AddMatchSignal(opts...); err != nil {
+		return nil, fmt.Errorf("failed to add properties signals match: %w", err)
+	}
+	// ch is owned by godbus which closes it when the connection is closed
+	ch := make(chan *dbus.Signal, 16)
+	done := make(chan struct{})
+	conn.Signal(ch)
+	go func() {
+		for {
+			var sig *dbus.Signal
+			select {
+			case <-done:
+				return
+			case s, ok := <-ch:
+				if !ok {
+					return
+				}
+				sig = s
+			}
+			if sig.Path != path || sig.Name != propertiesInterface+".PropertiesChanged" {
+				continue
+			}
+			var (
+				name        string
+				changed     map[string]dbus.Variant
+				invalidated []string
+			)
+			if dbus.Store(sig.Body, &name, &changed, &invalidated) != nil || name != iface {
+				continue
+			}
+			fn(changed, invalidated)
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			conn.RemoveSignal(ch)
+			conn.RemoveMatchSignal(opts...)
+			close(done)
+		})
+	}, nil
+}
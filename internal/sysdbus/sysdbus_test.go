@@ -0,0 +1,38 @@
+package sysdbus
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestTypedError(t *testing.T) {
+	errNoSuchThing := errors.New("no such thing")
+	known := map[string]error{"org.example.NoSuchThing": errNoSuchThing}
+	for name, expected := range map[string]error{
+		"org.example.NoSuchThing":                                     errNoSuchThing,
+		"org.freedesktop.DBus.Error.AccessDenied":                     ErrAccessDenied,
+		"org.freedesktop.DBus.Error.InteractiveAuthorizationRequired": ErrAccessDenied,
+	} {
+		err := TypedError(dbus.Error{Name: name}, known)
+		var dbusErr dbus.Error
+		if !errors.Is(err, expected) || !errors.As(err, &dbusErr) || dbusErr.Name != name {
+			t.Errorf("%s: unexpected error %v", name, err)
+		}
+	}
+	unknown := dbus.Error{Name: "org.example.Other"}
+	if err := TypedError(unknown, known); errors.Is(err, ErrAccessDenied) || errors.Is(err, errNoSuchThing) {
+		t.Errorf("unexpected typed error %v", err)
+	}
+}
+
+func TestPropertyChangeHas(t *testing.T) {
+	ch := PropertyChange{
+		Changed:     map[string]dbus.Variant{"Hostname": dbus.MakeVariant("box")},
+		Invalidated: []string{"StaticHostname"},
+	}
+	if !ch.Has("Hostname") || !ch.Has("StaticHostname") || ch.Has("Chassis") {
+		t.Errorf("unexpected Has results for %+v", ch)
+	}
+}
@@ -0,0 +1,149 @@
+// Package varlink is a minimal varlink client, the IPC protocol some systemd services
+// (eg: networkd, resolved, machined) expose alongside or instead of dbus.
+// Messages are JSON objects terminated by a NUL byte exchanged over a unix socket.
+package varlink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// Error is a varlink error reply.
+type Error struct {
+	Name       string          // qualified error name, eg: io.systemd.Network.NoSuchLink
+	Parameters json.RawMessage // error parameters, if any
+}
+
+func (e *Error) Error() string {
+	if len(e.Parameters) == 0 || string(e.Parameters) == "{}" {
+		return "varlink error: " + e.Name
+	}
+	return fmt.Sprintf("varlink error: %s: %s", e.Name, e.Parameters)
+}
+
+// ErrBroken is returned by the calls made on a connection after a call has been interrupted
+// (context done, I/O error, unexpected reply): its replies can't be matched with the calls anymore.
+var ErrBroken = errors.New("varlink connection broken by a previous call")
+
+// Conn is a varlink connection, calls are serialized.
+type Conn struct {
+	mu     sync.Mutex
+	conn   net.Conn
+	r      *bufio.Reader
+	broken bool
+}
+
+// Dial connects to the varlink unix socket at path.
+func Dial(ctx context.Context, path string) (*Conn, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial varlink socket: %w", err)
+	}
+	return &Conn{
+		conn: conn,
+		r:    bufio.NewReader(conn),
+	}, nil
+}
+
+type call struct {
+	Method     string      `json:"method"`
+	Parameters interface{} `json:"parameters,omitempty"`
+}
+
+type reply struct {
+	Parameters json.RawMessage `json:"parameters"`
+	Error      string          `json:"error"`
+	Continues  bool            `json:"continues"`
+}
+
+// Call calls method with params (a struct or map, nil for none) and stores the reply parameters
+// into reply, which may be nil to discard them. Error replies are returned as *Error.
+// If the call does not complete (eg: ctx is done before the reply), the connection is closed
+// and the following calls fail with ErrBroken.
+func (c *Conn) Call(ctx context.Context, method string, params, reply interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.broken {
+		return ErrBroken
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		c.conn.SetDeadline(deadline)
+	} else {
+		c.conn.SetDeadline(time.Time{})
+	}
+	stop := context.AfterFunc(ctx, func() {
+		// unblock the pending read or write
+		c.conn.SetDeadline(time.Unix(1, 0))
+	})
+	defer stop()
+	raw, synced, err := c.call(method, params)
+	if !synced {
+		// a late or partial reply would be read by the next call
+		c.broken = true
+		c.conn.Close()
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil && !synced {
+		return ctxErr
+	}
+	if err != nil {
+		return err
+	}
+	if reply == nil || len(raw) == 0 {
+		return nil
+	}
+	if err = json.Unmarshal(raw, reply); err != nil {
+		return fmt.Errorf("failed to decode %s reply: %w", method, err)
+	}
+	return nil
+}
+
+// call sends the call and reads its reply, synced is false if the reply has not been entirely read.
+func (c *Conn) call(method string, params interface{}) (raw json.RawMessage, synced bool, err error) {
+	msg, err := json.Marshal(call{
+		Method:     method,
+		Parameters: params,
+	})
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to encode %s call: %w", method, err)
+	}
+	if _, err = c.conn.Write(append(msg, 0)); err != nil {
+		return nil, false, fmt.Errorf("failed to send %s call: %w", method, err)
+	}
+	data, err := c.r.ReadBytes(0)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read %s reply: %w", method, err)
+	}
+	var r reply
+	if err = json.Unmarshal(data[:len(data)-1], &r); err != nil {
+		return nil, true, fmt.Errorf("failed to decode %s reply: %w", method, err)
+	}
+	if r.Error != "" {
+		return nil, true, &Error{
+			Name:       r.Error,
+			Parameters: r.Parameters,
+		}
+	}
+	if r.Continues {
+		// more replies will follow
+		return nil, false, errors.New("unexpected streamed reply to " + method)
+	}
+	return r.Parameters, true, nil
+}
+
+// Close closes the connection.
+func (c *Conn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.broken {
+		// already closed
+		return nil
+	}
+	return c.conn.Close()
+}
@@ -0,0 +1,83 @@
+package varlink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCall(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "varlink")
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for {
+			data, err := r.ReadBytes(0)
+			if err != nil {
+				return
+			}
+			var c struct {
+				Method     string
+				Parameters struct{ Name string }
+			}
+			json.Unmarshal(data[:len(data)-1], &c)
+			var out []byte
+			if c.Method == "org.example.Slow" {
+				// never replies
+				continue
+			}
+			if c.Method == "org.example.Hello" {
+				out, _ = json.Marshal(map[string]interface{}{
+					"parameters": map[string]string{"Greeting": "hello " + c.Parameters.Name},
+				})
+			} else {
+				out, _ = json.Marshal(map[string]interface{}{
+					"error":      "org.varlink.service.MethodNotFound",
+					"parameters": map[string]string{"method": c.Method},
+				})
+			}
+			conn.Write(append(out, 0))
+		}
+	}()
+	c, err := Dial(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	var reply struct{ Greeting string }
+	err = c.Call(context.Background(), "org.example.Hello", map[string]string{"Name": "world"}, &reply)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.Greeting != "hello world" {
+		t.Errorf("unexpected greeting %q", reply.Greeting)
+	}
+	var verr *Error
+	err = c.Call(context.Background(), "org.example.Nope", nil, nil)
+	if !errors.As(err, &verr) || verr.Name != "org.varlink.service.MethodNotFound" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err = c.Call(ctx, "org.example.Slow", nil, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a deadline error, got %v", err)
+	}
+	if err = c.Call(context.Background(), "org.example.Hello", nil, &reply); !errors.Is(err, ErrBroken) {
+		t.Errorf("expected ErrBroken after an interrupted call, got %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+// Package login1 is a pure Go implementation of the org.freedesktop.login1 dbus interface,
+// which allows to query and manage the sessions, users and seats tracked by systemd-logind.
+package login1
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+const (
+	dbusDest      = "org.freedesktop.login1"
+	dbusInterface = "org.freedesktop.login1.Manager"
+	dbusPath      = "/org/freedesktop/login1"
+)
+
+// Conn represents a systemd-logind dbus connection.
+type Conn struct {
+	conn  *dbus.Conn
+	obj   dbus.BusObject
+	flags dbus.Flags
+
+	sigMu    sync.Mutex
+	matches  map[string]struct{}
+	handlers map[*func(*dbus.Signal)]struct{}
+}
+
+// ConnOption configures a Conn, see NewConn.
+type ConnOption func(c *Conn) error
+
+// WithInteractiveAuthorization sets the ALLOW_INTERACTIVE_AUTHORIZATION flag on method calls,
+// so polkit may prompt the user for privileged operations (eg: PowerOff, TerminateSession)
+// instead of failing with an access denied error. Calls may then block until the user answers.
+func WithInteractiveAuthorization() ConnOption {
+	return func(c *Conn) error {
+		c.flags |= dbus.FlagAllowInteractiveAuthorization
+		return nil
+	}
+}
+
+// NewConn returns a new and ready to use dbus connection.
+// You must close that connection when you have been done with it.
+func NewConn(opts ...ConnOption) (*Conn, error) {
+	c := &Conn{
+		matches:  make(map[string]struct{}),
+		handlers: make(map[*func(*dbus.Signal)]struct{}),
+	}
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+	conn, err := sysdbus.SystemBus()
+	if err != nil {
+		return nil, err
+	}
+	c.conn = conn
+	c.obj = conn.Object(dbusDest, dbus.ObjectPath(dbusPath))
+	return c, nil
+}
+
+// Call wraps obj.CallWithContext by using the connection flags (see WithInteractiveAuthorization)
+// and format the method with the dbus manager interface.
+func (c *Conn) Call(ctx context.Context, method string, args ...interface{}) *dbus.Call {
+	return c.obj.CallWithContext(ctx, fmt.Sprintf("%s.%s", dbusInterface, method), c.flags, args...)
+}
+
+// interactive tells if interactive authorization is allowed, for the methods taking it as argument.
+func (c *Conn) interactive() bool {
+	return c.flags&dbus.FlagAllowInteractiveAuthorization != 0
+}
+
+// Close closes the current dbus connection.
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
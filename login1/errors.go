@@ -0,0 +1,30 @@
+package login1
+
+import (
+	"errors"
+
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+var (
+	// ErrNoSuchSession is returned when the session does not exist.
+	ErrNoSuchSession = errors.New("no such session")
+	// ErrNoSuchUser is returned when the user is not logged in (nor lingering).
+	ErrNoSuchUser = errors.New("no such user")
+	// ErrNoSuchSeat is returned when the seat does not exist.
+	ErrNoSuchSeat = errors.New("no such seat")
+	// ErrAccessDenied is returned when polkit (or the caller privileges) denied the operation,
+	// see WithInteractiveAuthorization.
+	ErrAccessDenied = sysdbus.ErrAccessDenied
+)
+
+var dbusErrors = map[string]error{
+	dbusDest + ".NoSuchSession": ErrNoSuchSession,
+	dbusDest + ".NoSuchUser":    ErrNoSuchUser,
+	dbusDest + ".NoSuchSeat":    ErrNoSuchSeat,
+}
+
+// typedError wraps the known logind dbus errors into the Err* errors, so they can be tested with errors.Is.
+func typedError(err error) error {
+	return sysdbus.TypedError(err, dbusErrors)
+}
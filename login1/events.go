@@ -0,0 +1,162 @@
+package login1
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// EventType is the type of an Event.
+type EventType int
+
+const (
+	SessionNew EventType = iota
+	SessionRemoved
+	UserNew
+	UserRemoved
+	SeatNew
+	SeatRemoved
+)
+
+func (t EventType) String() string {
+	switch t {
+	case SessionNew:
+		return "SessionNew"
+	case SessionRemoved:
+		return "SessionRemoved"
+	case UserNew:
+		return "UserNew"
+	case UserRemoved:
+		return "UserRemoved"
+	case SeatNew:
+		return "SeatNew"
+	case SeatRemoved:
+		return "SeatRemoved"
+	default:
+		return "EventType(" + strconv.Itoa(int(t)) + ")"
+	}
+}
+
+// Event is a login manager signal received thru an EventSubscription.
+type Event struct {
+	Type    EventType
+	Session string          // session events only: session ID
+	UID     uint32          // user events only: user ID
+	Seat    string          // seat events only: seat ID
+	Path    dbus.ObjectPath // session, user or seat object path
+}
+
+// EventSubscription delivers login events, see Conn.SubscribeEvents.
+type EventSubscription struct {
+	// C receives the events, it is closed by Close.
+	C <-chan Event
+
+	out     chan Event
+	remove  func()
+	mu      sync.Mutex
+	pending []Event
+	wake    chan struct{}
+	done    chan struct{}
+	closed  bool
+}
+
+var eventSignals = map[string]EventType{
+	"SessionNew":     SessionNew,
+	"SessionRemoved": SessionRemoved,
+	"UserNew":        UserNew,
+	"UserRemoved":    UserRemoved,
+	"SeatNew":        SeatNew,
+	"SeatRemoved":    SeatRemoved,
+}
+
+// SubscribeEvents returns a new subscription to the SessionNew, SessionRemoved, UserNew, UserRemoved,
+// SeatNew and SeatRemoved signals, ie: logins and logouts. Events are never dropped: when the
+// subscriber lags behind they are kept aside until received. Close must be called once done with it.
+func (c *Conn) SubscribeEvents() (*EventSubscription, error) {
+	for member := range eventSignals {
+		if err := c.addMatch(dbusInterface, member); err != nil {
+			return nil, err
+		}
+	}
+	s := &EventSubscription{
+		out:  make(chan Event, 64),
+		wake: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+	s.C = s.out
+	s.remove = c.handle(func(sig *dbus.Signal) {
+		if e, ok := parseEvent(sig); ok {
+			s.deliver(e)
+		}
+	})
+	go s.pump()
+	return s, nil
+}
+
+// Close stops the subscription and closes its channel.
+func (s *EventSubscription) Close() {
+	s.remove()
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.done)
+	}
+	s.mu.Unlock()
+}
+
+func parseEvent(sig *dbus.Signal) (e Event, ok bool) {
+	if sig.Path != dbusPath || len(sig.Name) <= len(dbusInterface) {
+		return
+	}
+	if e.Type, ok = eventSignals[sig.Name[len(dbusInterface)+1:]]; !ok {
+		return
+	}
+	var err error
+	switch e.Type {
+	case SessionNew, SessionRemoved:
+		err = dbus.Store(sig.Body, &e.Session, &e.Path)
+	case UserNew, UserRemoved:
+		err = dbus.Store(sig.Body, &e.UID, &e.Path)
+	default:
+		err = dbus.Store(sig.Body, &e.Seat, &e.Path)
+	}
+	return e, err == nil
+}
+
+func (s *EventSubscription) deliver(e Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.pending = append(s.pending, e)
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *EventSubscription) pump() {
+	defer close(s.out)
+	for {
+		s.mu.Lock()
+		if len(s.pending) == 0 {
+			s.mu.Unlock()
+			select {
+			case <-s.wake:
+				continue
+			case <-s.done:
+				return
+			}
+		}
+		e := s.pending[0]
+		s.pending = s.pending[1:]
+		s.mu.Unlock()
+		select {
+		case s.out <- e:
+		case <-s.done:
+			return
+		}
+	}
+}
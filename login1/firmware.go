@@ -0,0 +1,51 @@
+package login1
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+)
+
+// CanRebootToFirmwareSetup tells whether the next reboot may enter the firmware setup (EFI only).
+// ctx: Context to use
+func (c *Conn) CanRebootToFirmwareSetup(ctx context.Context) (CanResult, error) {
+	var res string
+	if err := c.Call(ctx, "CanRebootToFirmwareSetup").Store(&res); err != nil {
+		return "", err
+	}
+	return CanResult(res), nil
+}
+
+// SetRebootToFirmwareSetup asks the firmware to enter its setup on the next reboot.
+// It fails with ErrAccessDenied if not allowed.
+// ctx: Context to use
+// enable: whether to enter the firmware setup on the next reboot
+func (c *Conn) SetRebootToFirmwareSetup(ctx context.Context, enable bool) error {
+	return typedError(c.Call(ctx, "SetRebootToFirmwareSetup", enable).Store())
+}
+
+// CanRebootToBootLoaderMenu tells whether the next reboot may show the boot loader menu
+// (boot loaders implementing the boot loader interface only, eg: systemd-boot).
+// ctx: Context to use
+func (c *Conn) CanRebootToBootLoaderMenu(ctx context.Context) (CanResult, error) {
+	var res string
+	if err := c.Call(ctx, "CanRebootToBootLoaderMenu").Store(&res); err != nil {
+		return "", err
+	}
+	return CanResult(res), nil
+}
+
+// KExecLoaded tells if a kernel has been loaded for kexec, ie: if "systemctl kexec" can
+// reboot into it without going through the firmware.
+func KExecLoaded() (bool, error) {
+	data, err := os.ReadFile("/sys/kernel/kexec_loaded")
+	if errors.Is(err, os.ErrNotExist) {
+		// kernel built without kexec support
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(string(data)) == "1", nil
+}
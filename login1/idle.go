@@ -0,0 +1,47 @@
+package login1
+
+import (
+	"context"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+// CurrentSession returns the session of the caller: the session it belongs to or, if none,
+// the graphical session of its user (the "auto" session object of logind).
+func (c *Conn) CurrentSession() *Session {
+	return c.session("", dbus.ObjectPath(sessionPathPrefix+"auto"))
+}
+
+// SetIdleHint tells logind whether the session is idle, it is aggregated into the system
+// idle state used for IdleAction= of logind.conf. It may only be called on the caller's own
+// session, see CurrentSession.
+// ctx: Context to use
+// idle: whether the session is idle
+func (s *Session) SetIdleHint(ctx context.Context, idle bool) error {
+	return typedError(s.obj.CallWithContext(ctx, sessionInterface+".SetIdleHint", s.c.flags, idle).Store())
+}
+
+// IdleHint returns whether the session is idle, and since when if so.
+// ctx: Context to use
+func (s *Session) IdleHint(ctx context.Context) (idle bool, since time.Time, err error) {
+	return idleHint(ctx, s.obj, sessionInterface)
+}
+
+// IdleHint returns whether the whole system is idle (every session is), and since when if so.
+// ctx: Context to use
+func (c *Conn) IdleHint(ctx context.Context) (idle bool, since time.Time, err error) {
+	return idleHint(ctx, c.obj, dbusInterface)
+}
+
+func idleHint(ctx context.Context, obj dbus.BusObject, iface string) (idle bool, since time.Time, err error) {
+	props, err := sysdbus.GetAllProperties(ctx, obj, iface)
+	if err != nil {
+		return
+	}
+	if idle = sysdbus.Prop[bool](props, "IdleHint"); idle {
+		since = sysdbus.Timestamp(props, "IdleSinceHint")
+	}
+	return
+}
@@ -0,0 +1,50 @@
+package login1
+
+import (
+	"context"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// SessionStatus represents a session as returned by ListSessions.
+type SessionStatus struct {
+	ID   string          // session ID
+	UID  uint32          // user ID
+	User string          // user name
+	Seat string          // seat ID, empty if none
+	Path dbus.ObjectPath // session object path
+}
+
+// UserStatus represents a user as returned by ListUsers.
+type UserStatus struct {
+	UID  uint32          // user ID
+	Name string          // user name
+	Path dbus.ObjectPath // user object path
+}
+
+// SeatStatus represents a seat as returned by ListSeats.
+type SeatStatus struct {
+	ID   string          // seat ID (eg: seat0)
+	Path dbus.ObjectPath // seat object path
+}
+
+// ListSessions returns the current sessions.
+// ctx: Context to use
+func (c *Conn) ListSessions(ctx context.Context) (sessions []SessionStatus, err error) {
+	err = c.Call(ctx, "ListSessions").Store(&sessions)
+	return
+}
+
+// ListUsers returns the users having at least one session (or lingering).
+// ctx: Context to use
+func (c *Conn) ListUsers(ctx context.Context) (users []UserStatus, err error) {
+	err = c.Call(ctx, "ListUsers").Store(&users)
+	return
+}
+
+// ListSeats returns the available seats.
+// ctx: Context to use
+func (c *Conn) ListSeats(ctx context.Context) (seats []SeatStatus, err error) {
+	err = c.Call(ctx, "ListSeats").Store(&seats)
+	return
+}
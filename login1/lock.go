@@ -0,0 +1,134 @@
+package login1
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+const sessionPathPrefix = dbusPath + "/session/"
+
+// LockSession asks the session id to lock its screen, the Lock signal is sent to the session screen locker.
+// ctx: Context to use
+// id: session ID
+func (c *Conn) LockSession(ctx context.Context, id string) error {
+	return c.Call(ctx, "LockSession", id).Store()
+}
+
+// UnlockSession asks the session id to unlock its screen.
+// ctx: Context to use
+// id: session ID
+func (c *Conn) UnlockSession(ctx context.Context, id string) error {
+	return c.Call(ctx, "UnlockSession", id).Store()
+}
+
+// LockSessions asks every session to lock its screen.
+// ctx: Context to use
+func (c *Conn) LockSessions(ctx context.Context) error {
+	return c.Call(ctx, "LockSessions").Store()
+}
+
+// UnlockSessions asks every session to unlock its screen.
+// ctx: Context to use
+func (c *Conn) UnlockSessions(ctx context.Context) error {
+	return c.Call(ctx, "UnlockSessions").Store()
+}
+
+// SetLockedHint tells logind whether the session is locked, screen lockers must call it
+// from the session once locked and unlocked.
+// ctx: Context to use
+// locked: whether the session is locked
+func (s *Session) SetLockedHint(ctx context.Context, locked bool) error {
+	return s.obj.CallWithContext(ctx, sessionInterface+".SetLockedHint", s.c.flags, locked).Store()
+}
+
+// LockEvent is a Lock or Unlock signal of a session.
+type LockEvent struct {
+	Session string          // session ID
+	Path    dbus.ObjectPath // session object path
+	Lock    bool            // Lock if true, Unlock otherwise
+}
+
+// LockSubscription delivers LockEvent, see Conn.SubscribeLock.
+type LockSubscription struct {
+	// C receives the events, it is closed by Close.
+	C <-chan LockEvent
+
+	out    chan LockEvent
+	remove func()
+	mu     sync.Mutex
+	closed bool
+}
+
+// SubscribeLock returns a new subscription to the Lock and Unlock signals of the sessions,
+// only delivering those of the given session IDs if any.
+// Events are dropped when the subscriber lags behind and the channel is full.
+// Close must be called once done with it.
+// sessions: session IDs to watch, none meaning every session
+func (c *Conn) SubscribeLock(sessions ...string) (*LockSubscription, error) {
+	for _, member := range []string{"Lock", "Unlock"} {
+		if err := c.addMatch(sessionInterface, member); err != nil {
+			return nil, err
+		}
+	}
+	var filter map[string]struct{}
+	if len(sessions) > 0 {
+		filter = make(map[string]struct{}, len(sessions))
+		for _, id := range sessions {
+			filter[id] = struct{}{}
+		}
+	}
+	s := &LockSubscription{out: make(chan LockEvent, 16)}
+	s.C = s.out
+	s.remove = c.handle(func(sig *dbus.Signal) {
+		var e LockEvent
+		switch sig.Name {
+		case sessionInterface + ".Lock":
+			e.Lock = true
+		case sessionInterface + ".Unlock":
+		default:
+			return
+		}
+		e.Path = sig.Path
+		e.Session = sessionIDFromPath(sig.Path)
+		if filter != nil {
+			if _, ok := filter[e.Session]; !ok {
+				return
+			}
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.closed {
+			return
+		}
+		select {
+		case s.out <- e:
+		default:
+		}
+	})
+	return s, nil
+}
+
+// Close stops the subscription and closes its channel.
+func (s *LockSubscription) Close() {
+	s.remove()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.out)
+	}
+}
+
+// sessionIDFromPath returns the session ID of a session object path.
+// The "auto" and "self" paths are only aliases resolved per caller, they have no ID.
+func sessionIDFromPath(path dbus.ObjectPath) string {
+	escaped, ok := strings.CutPrefix(string(path), sessionPathPrefix)
+	if !ok {
+		return ""
+	}
+	return sysdbus.UnescapePathElement(escaped)
+}
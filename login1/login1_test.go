@@ -0,0 +1,49 @@
+package login1
+
+import (
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestParseEvent(t *testing.T) {
+	e, ok := parseEvent(&dbus.Signal{
+		Path: dbusPath,
+		Name: dbusInterface + ".SessionNew",
+		Body: []interface{}{"c1", dbus.ObjectPath(sessionPathPrefix + "c1")},
+	})
+	if !ok || e.Type != SessionNew || e.Session != "c1" {
+		t.Errorf("unexpected event: %+v", e)
+	}
+	e, ok = parseEvent(&dbus.Signal{
+		Path: dbusPath,
+		Name: dbusInterface + ".UserRemoved",
+		Body: []interface{}{uint32(1000), dbus.ObjectPath(userPathPrefix + "_1000")},
+	})
+	if !ok || e.Type != UserRemoved || e.UID != 1000 {
+		t.Errorf("unexpected event: %+v", e)
+	}
+	if _, ok = parseEvent(&dbus.Signal{Path: dbusPath, Name: dbusInterface + ".PrepareForSleep", Body: []interface{}{true}}); ok {
+		t.Error("PrepareForSleep is not a login event")
+	}
+}
+
+func TestSessionIDFromPath(t *testing.T) {
+	if id := sessionIDFromPath(sessionPathPrefix + "_31"); id != "1" {
+		t.Errorf("unexpected session ID %q", id)
+	}
+}
+
+func TestUIDFromPath(t *testing.T) {
+	for path, expected := range map[dbus.ObjectPath]int64{
+		"/org/freedesktop/login1/user/_1000":  1000,
+		"/org/freedesktop/login1/user/_0":     0,
+		"/org/freedesktop/login1/user/self":   -1,
+		"/org/freedesktop/login1/session/_31": -1,
+	} {
+		uid, ok := uidFromPath(path)
+		if expected < 0 && ok || expected >= 0 && (!ok || int64(uid) != expected) {
+			t.Errorf("uidFromPath(%q) = %d, %v", path, uid, ok)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package login1
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const userPathPrefix = dbusPath + "/user/"
+
+// GetSessionByPID returns the session object path of the session the process pid belongs to.
+// It fails with ErrNoSuchSession if the process is not part of any session (eg: a system service).
+// ctx: Context to use
+// pid: process ID, 0 being the caller
+func (c *Conn) GetSessionByPID(ctx context.Context, pid uint32) (path dbus.ObjectPath, err error) {
+	err = typedError(c.Call(ctx, "GetSessionByPID", pid).Store(&path))
+	return
+}
+
+// GetUserByPID returns the user object path of the owner of the process pid.
+// It fails with ErrNoSuchUser if the user is not logged in (nor lingering).
+// ctx: Context to use
+// pid: process ID, 0 being the caller
+func (c *Conn) GetUserByPID(ctx context.Context, pid uint32) (path dbus.ObjectPath, err error) {
+	err = typedError(c.Call(ctx, "GetUserByPID", pid).Store(&path))
+	return
+}
+
+// SessionByPID returns the session the process pid belongs to, see GetSessionByPID.
+// ctx: Context to use
+// pid: process ID, 0 being the caller
+func (c *Conn) SessionByPID(ctx context.Context, pid uint32) (*Session, error) {
+	path, err := c.GetSessionByPID(ctx, pid)
+	if err != nil {
+		return nil, err
+	}
+	return c.session(sessionIDFromPath(path), path), nil
+}
+
+// UserByPID returns the owner of the process pid, see GetUserByPID.
+// ctx: Context to use
+// pid: process ID, 0 being the caller
+func (c *Conn) UserByPID(ctx context.Context, pid uint32) (*User, error) {
+	path, err := c.GetUserByPID(ctx, pid)
+	if err != nil {
+		return nil, err
+	}
+	uid, ok := uidFromPath(path)
+	if !ok {
+		// not a /user/_<uid> path, read the UID from the object
+		u := c.user(0, path)
+		v, err := u.Property(ctx, "UID")
+		if err != nil {
+			return nil, err
+		}
+		u.UID, _ = v.Value().(uint32)
+		return u, nil
+	}
+	return c.user(uid, path), nil
+}
+
+// uidFromPath returns the UID of a user object path: logind names them "_" followed by the decimal UID
+// (eg: /org/freedesktop/login1/user/_1000), the underscore not being a bus path escape.
+func uidFromPath(path dbus.ObjectPath) (uint32, bool) {
+	escaped, ok := strings.CutPrefix(string(path), userPathPrefix)
+	if !ok {
+		return 0, false
+	}
+	uid, err := strconv.ParseUint(strings.TrimPrefix(escaped, "_"), 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return uint32(uid), true
+}
+
+// RuntimeDirByPID returns the XDG_RUNTIME_DIR of the owner of the process pid (eg: /run/user/1000).
+// ctx: Context to use
+// pid: process ID, 0 being the caller
+func (c *Conn) RuntimeDirByPID(ctx context.Context, pid uint32) (string, error) {
+	u, err := c.UserByPID(ctx, pid)
+	if err != nil {
+		return "", err
+	}
+	v, err := u.Property(ctx, "RuntimePath")
+	if err != nil {
+		return "", err
+	}
+	dir, _ := v.Value().(string)
+	return dir, nil
+}
+
+// SessionClassByPID returns the class (user, greeter, lock-screen or background) of the session
+// the process pid belongs to.
+// ctx: Context to use
+// pid: process ID, 0 being the caller
+func (c *Conn) SessionClassByPID(ctx context.Context, pid uint32) (string, error) {
+	s, err := c.SessionByPID(ctx, pid)
+	if err != nil {
+		return "", err
+	}
+	v, err := s.Property(ctx, "Class")
+	if err != nil {
+		return "", err
+	}
+	class, _ := v.Value().(string)
+	return class, nil
+}
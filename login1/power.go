@@ -0,0 +1,123 @@
+package login1
+
+import (
+	"context"
+)
+
+// PowerAction is a power management operation of logind.
+type PowerAction string
+
+const (
+	PowerOff             PowerAction = "PowerOff"
+	Reboot               PowerAction = "Reboot"
+	Halt                 PowerAction = "Halt"
+	Suspend              PowerAction = "Suspend"
+	Hibernate            PowerAction = "Hibernate"
+	HybridSleep          PowerAction = "HybridSleep"
+	SuspendThenHibernate PowerAction = "SuspendThenHibernate"
+)
+
+// CanResult tells whether a power action is possible for the caller.
+type CanResult string
+
+const (
+	CanYes       CanResult = "yes"       // allowed
+	CanNo        CanResult = "no"        // not allowed
+	CanChallenge CanResult = "challenge" // allowed after polkit authentication, see WithInteractiveAuthorization
+	CanNA        CanResult = "na"        // not supported by the hardware or the configuration
+)
+
+// Possible tells if the action may be performed, possibly after authentication.
+func (r CanResult) Possible() bool {
+	return r == CanYes || r == CanChallenge
+}
+
+// Do performs the power action. Interactive polkit authentication is allowed
+// if the connection has been created WithInteractiveAuthorization.
+// It fails with ErrAccessDenied if not allowed.
+// ctx: Context to use
+// action: power action to perform
+func (c *Conn) Do(ctx context.Context, action PowerAction) error {
+	return typedError(c.Call(ctx, string(action), c.interactive()).Store())
+}
+
+// Can tells whether the power action is possible for the caller.
+// ctx: Context to use
+// action: power action to check
+func (c *Conn) Can(ctx context.Context, action PowerAction) (CanResult, error) {
+	var res string
+	if err := c.Call(ctx, "Can"+string(action)).Store(&res); err != nil {
+		return "", err
+	}
+	return CanResult(res), nil
+}
+
+// PowerOff powers off the system, see Do.
+func (c *Conn) PowerOff(ctx context.Context) error {
+	return c.Do(ctx, PowerOff)
+}
+
+// Reboot reboots the system, see Do.
+func (c *Conn) Reboot(ctx context.Context) error {
+	return c.Do(ctx, Reboot)
+}
+
+// Halt halts the system, see Do.
+func (c *Conn) Halt(ctx context.Context) error {
+	return c.Do(ctx, Halt)
+}
+
+// Suspend suspends the system, see Do.
+func (c *Conn) Suspend(ctx context.Context) error {
+	return c.Do(ctx, Suspend)
+}
+
+// Hibernate hibernates the system, see Do.
+func (c *Conn) Hibernate(ctx context.Context) error {
+	return c.Do(ctx, Hibernate)
+}
+
+// HybridSleep hibernates and suspends the system, see Do.
+func (c *Conn) HybridSleep(ctx context.Context) error {
+	return c.Do(ctx, HybridSleep)
+}
+
+// SuspendThenHibernate suspends the system then hibernates it after HibernateDelaySec, see Do.
+func (c *Conn) SuspendThenHibernate(ctx context.Context) error {
+	return c.Do(ctx, SuspendThenHibernate)
+}
+
+// CanPowerOff tells whether PowerOff is possible, see Can.
+func (c *Conn) CanPowerOff(ctx context.Context) (CanResult, error) {
+	return c.Can(ctx, PowerOff)
+}
+
+// CanReboot tells whether Reboot is possible, see Can.
+func (c *Conn) CanReboot(ctx context.Context) (CanResult, error) {
+	return c.Can(ctx, Reboot)
+}
+
+// CanHalt tells whether Halt is possible, see Can.
+func (c *Conn) CanHalt(ctx context.Context) (CanResult, error) {
+	return c.Can(ctx, Halt)
+}
+
+// CanSuspend tells whether Suspend is possible, see Can.
+func (c *Conn) CanSuspend(ctx context.Context) (CanResult, error) {
+	return c.Can(ctx, Suspend)
+}
+
+// CanHibernate tells whether Hibernate is possible, see Can.
+func (c *Conn) CanHibernate(ctx context.Context) (CanResult, error) {
+	return c.Can(ctx, Hibernate)
+}
+
+// CanHybridSleep tells whether HybridSleep is possible, see Can.
+func (c *Conn) CanHybridSleep(ctx context.Context) (CanResult, error) {
+	return c.Can(ctx, HybridSleep)
+}
+
+// CanSuspendThenHibernate tells whether SuspendThenHibernate is possible, see Can.
+func (c *Conn) CanSuspendThenHibernate(ctx context.Context) (CanResult, error) {
+	return c.Can(ctx, SuspendThenHibernate)
+}
@@ -0,0 +1,146 @@
+package login1
+
+import (
+	"context"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// PrepareEvent is a PrepareForShutdown or PrepareForSleep signal.
+type PrepareEvent struct {
+	Shutdown bool // PrepareForShutdown if true, PrepareForSleep otherwise
+	Start    bool // true before the operation, false once resumed from sleep (or the shutdown has been canceled)
+}
+
+// PrepareSubscription delivers PrepareEvent, see Conn.SubscribePrepare.
+type PrepareSubscription struct {
+	// C receives the events, it is closed by Close.
+	C <-chan PrepareEvent
+
+	out    chan PrepareEvent
+	remove func()
+	mu     sync.Mutex
+	closed bool
+}
+
+// SubscribePrepare returns a new subscription to the PrepareForShutdown and PrepareForSleep signals.
+// Those are only useful along with a delay inhibitor lock (see Inhibit): logind waits for it to be
+// released before proceeding, see also HandleSleep and HandleShutdown.
+// Close must be called once done with it.
+func (c *Conn) SubscribePrepare() (*PrepareSubscription, error) {
+	for _, member := range []string{"PrepareForShutdown", "PrepareForSleep"} {
+		if err := c.addMatch(dbusInterface, member); err != nil {
+			return nil, err
+		}
+	}
+	s := &PrepareSubscription{out: make(chan PrepareEvent, 16)}
+	s.C = s.out
+	s.remove = c.handle(func(sig *dbus.Signal) {
+		var e PrepareEvent
+		switch sig.Name {
+		case dbusInterface + ".PrepareForShutdown":
+			e.Shutdown = true
+		case dbusInterface + ".PrepareForSleep":
+		default:
+			return
+		}
+		if dbus.Store(sig.Body, &e.Start) != nil {
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.closed {
+			return
+		}
+		select {
+		case s.out <- e:
+		default:
+			// those signals are rare, a full channel means the subscriber is not reading
+		}
+	})
+	return s, nil
+}
+
+// Close stops the subscription and closes its channel.
+func (s *PrepareSubscription) Close() {
+	s.remove()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.out)
+	}
+}
+
+// HandleSleep calls before each time the system is about to sleep, delaying it until before returns
+// (within InhibitDelayMaxSec of logind.conf), and after each time it resumed. It blocks until ctx is done.
+// ctx: Context to use
+// who: human readable name of the program, for the delay lock
+// why: human readable reason, for the delay lock
+// before: function to call before sleep (eg: checkpoint state), can be nil
+// after: function to call after resume (eg: resync), can be nil
+func (c *Conn) HandleSleep(ctx context.Context, who, why string, before, after func()) error {
+	return c.handlePrepare(ctx, false, who, why, before, after)
+}
+
+// HandleShutdown calls before when the system is about to power off or reboot, delaying it until
+// before returns (within InhibitDelayMaxSec of logind.conf). It blocks until ctx is done.
+// ctx: Context to use
+// who: human readable name of the program, for the delay lock
+// why: human readable reason, for the delay lock
+// before: function to call before shutdown (eg: flush state)
+func (c *Conn) HandleShutdown(ctx context.Context, who, why string, before func()) error {
+	return c.handlePrepare(ctx, true, who, why, before, nil)
+}
+
+func (c *Conn) handlePrepare(ctx context.Context, shutdown bool, who, why string, before, after func()) error {
+	what := []InhibitWhat{InhibitSleep}
+	if shutdown {
+		what = []InhibitWhat{InhibitShutdown}
+	}
+	sub, err := c.SubscribePrepare()
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+	// take the lock after subscribing so no signal is missed while holding it
+	lock, err := c.Inhibit(ctx, what, who, why, InhibitDelay)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if lock != nil {
+			lock.Release()
+		}
+	}()
+	for {
+		select {
+		case e := <-sub.C:
+			if e.Shutdown != shutdown {
+				continue
+			}
+			if e.Start {
+				if before != nil {
+					before()
+				}
+				if lock != nil {
+					lock.Release()
+					lock = nil
+				}
+				continue
+			}
+			if after != nil {
+				after()
+			}
+			// take the lock again for the next time
+			if lock == nil {
+				if lock, err = c.Inhibit(ctx, what, who, why, InhibitDelay); err != nil {
+					return err
+				}
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
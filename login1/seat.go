@@ -0,0 +1,149 @@
+package login1
+
+import (
+	"context"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+const seatInterface = "org.freedesktop.login1.Seat"
+
+// Seat represents a seat object of logind.
+type Seat struct {
+	ID   string          // seat ID (eg: seat0)
+	Path dbus.ObjectPath // seat object path
+	c    *Conn
+	obj  dbus.BusObject
+}
+
+// GetSeat returns the seat object path of the seat id.
+// ctx: Context to use
+// id: seat ID
+func (c *Conn) GetSeat(ctx context.Context, id string) (path dbus.ObjectPath, err error) {
+	err = c.Call(ctx, "GetSeat", id).Store(&path)
+	return
+}
+
+// Seat returns the seat id.
+// ctx: Context to use
+// id: seat ID
+func (c *Conn) Seat(ctx context.Context, id string) (*Seat, error) {
+	path, err := c.GetSeat(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return c.seat(id, path), nil
+}
+
+func (c *Conn) seat(id string, path dbus.ObjectPath) *Seat {
+	return &Seat{
+		ID:   id,
+		Path: path,
+		c:    c,
+		obj:  c.conn.Object(dbusDest, path),
+	}
+}
+
+// Property returns the raw value of a seat property.
+func (s *Seat) Property(ctx context.Context, name string) (dbus.Variant, error) {
+	return sysdbus.GetProperty(ctx, s.obj, seatInterface, name)
+}
+
+// SeatProperties holds the properties of a seat.
+type SeatProperties struct {
+	ID              string
+	ActiveSession   string // ID of the active session, empty if none
+	CanMultiSession bool
+	CanTTY          bool
+	CanGraphical    bool
+	Sessions        []string // IDs of the sessions of the seat
+	IdleHint        bool
+	IdleSinceHint   time.Time
+}
+
+// Properties returns the typed seat properties.
+// ctx: Context to use
+func (s *Seat) Properties(ctx context.Context) (*SeatProperties, error) {
+	props, err := sysdbus.GetAllProperties(ctx, s.obj, seatInterface)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		active struct {
+			ID   string
+			Path dbus.ObjectPath
+		}
+		sessions []struct {
+			ID   string
+			Path dbus.ObjectPath
+		}
+	)
+	if err = sysdbus.StoreProp(props, "ActiveSession", &active); err != nil {
+		return nil, err
+	}
+	if err = sysdbus.StoreProp(props, "Sessions", &sessions); err != nil {
+		return nil, err
+	}
+	sp := &SeatProperties{
+		ID:              sysdbus.Prop[string](props, "Id"),
+		ActiveSession:   active.ID,
+		CanMultiSession: sysdbus.Prop[bool](props, "CanMultiSession"),
+		CanTTY:          sysdbus.Prop[bool](props, "CanTTY"),
+		CanGraphical:    sysdbus.Prop[bool](props, "CanGraphical"),
+		Sessions:        make([]string, len(sessions)),
+		IdleHint:        sysdbus.Prop[bool](props, "IdleHint"),
+		IdleSinceHint:   sysdbus.Timestamp(props, "IdleSinceHint"),
+	}
+	for i, session := range sessions {
+		sp.Sessions[i] = session.ID
+	}
+	return sp, nil
+}
+
+// ActivateSession brings the session id to the foreground of the seat.
+// ctx: Context to use
+// id: session ID, the session must be on the seat
+func (s *Seat) ActivateSession(ctx context.Context, id string) error {
+	return typedError(s.obj.CallWithContext(ctx, seatInterface+".ActivateSession", s.c.flags, id).Store())
+}
+
+// SwitchTo switches the seat to the virtual terminal vtnr, the seat must support TTYs.
+// ctx: Context to use
+// vtnr: virtual terminal number
+func (s *Seat) SwitchTo(ctx context.Context, vtnr uint32) error {
+	return typedError(s.obj.CallWithContext(ctx, seatInterface+".SwitchTo", s.c.flags, vtnr).Store())
+}
+
+// ActivateSession brings the session id to the foreground of its seat.
+// ctx: Context to use
+// id: session ID
+func (c *Conn) ActivateSession(ctx context.Context, id string) error {
+	return typedError(c.Call(ctx, "ActivateSession", id).Store())
+}
+
+// ActivateSessionOnSeat brings the session id to the foreground of the seat seat.
+// ctx: Context to use
+// id: session ID
+// seat: seat ID
+func (c *Conn) ActivateSessionOnSeat(ctx context.Context, id, seat string) error {
+	return typedError(c.Call(ctx, "ActivateSessionOnSeat", id, seat).Store())
+}
+
+// AttachDevice assigns a device to a seat, the assignment is persistent (udev rule).
+// Attaching a device to a seat other than seat0 creates that seat if needed.
+// It fails with ErrAccessDenied if not allowed, see WithInteractiveAuthorization.
+// ctx: Context to use
+// seat: seat ID (eg: seat1)
+// sysfsPath: device path in /sys (eg: /sys/devices/pci0000:00/0000:00:02.0/drm/card1)
+func (c *Conn) AttachDevice(ctx context.Context, seat, sysfsPath string) error {
+	return typedError(c.Call(ctx, "AttachDevice", seat, sysfsPath, c.interactive()).Store())
+}
+
+// FlushDevices removes every device assignment made by AttachDevice.
+// It fails with ErrAccessDenied if not allowed, see WithInteractiveAuthorization.
+// ctx: Context to use
+func (c *Conn) FlushDevices(ctx context.Context) error {
+	return typedError(c.Call(ctx, "FlushDevices", c.interactive()).Store())
+}
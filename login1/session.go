@@ -0,0 +1,138 @@
+package login1
+
+import (
+	"context"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+const sessionInterface = "org.freedesktop.login1.Session"
+
+// Session represents a session object of logind.
+type Session struct {
+	ID   string          // session ID
+	Path dbus.ObjectPath // session object path
+	c    *Conn
+	obj  dbus.BusObject
+}
+
+// GetSession returns the session object path of the session id.
+// ctx: Context to use
+// id: session ID
+func (c *Conn) GetSession(ctx context.Context, id string) (path dbus.ObjectPath, err error) {
+	err = c.Call(ctx, "GetSession", id).Store(&path)
+	return
+}
+
+// Session returns the session id.
+// ctx: Context to use
+// id: session ID
+func (c *Conn) Session(ctx context.Context, id string) (*Session, error) {
+	path, err := c.GetSession(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return c.session(id, path), nil
+}
+
+func (c *Conn) session(id string, path dbus.ObjectPath) *Session {
+	return &Session{
+		ID:   id,
+		Path: path,
+		c:    c,
+		obj:  c.conn.Object(dbusDest, path),
+	}
+}
+
+// Property returns the raw value of a session property.
+func (s *Session) Property(ctx context.Context, name string) (dbus.Variant, error) {
+	return sysdbus.GetProperty(ctx, s.obj, sessionInterface, name)
+}
+
+// SessionProperties holds the most used properties of a session.
+type SessionProperties struct {
+	ID            string
+	UID           uint32
+	User          string
+	Seat          string
+	Timestamp     time.Time // session creation time
+	VTNr          uint32    // virtual terminal number, 0 if none
+	TTY           string
+	Display       string // X11 display, empty if none
+	Remote        bool
+	RemoteHost    string
+	RemoteUser    string
+	Service       string // PAM service (eg: sshd, gdm-password)
+	Desktop       string
+	Scope         string // scope unit of the session
+	Leader        uint32 // PID of the session leader
+	Type          string // unspecified, tty, x11, wayland, mir or web
+	Class         string // user, greeter, lock-screen or background
+	Active        bool
+	State         string // online, active or closing
+	IdleHint      bool
+	IdleSinceHint time.Time
+	LockedHint    bool
+}
+
+// Properties returns the typed session properties.
+// ctx: Context to use
+func (s *Session) Properties(ctx context.Context) (*SessionProperties, error) {
+	props, err := sysdbus.GetAllProperties(ctx, s.obj, sessionInterface)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		user struct {
+			UID  uint32
+			Path dbus.ObjectPath
+		}
+		seat struct {
+			ID   string
+			Path dbus.ObjectPath
+		}
+	)
+	if err = sysdbus.StoreProp(props, "User", &user); err != nil {
+		return nil, err
+	}
+	if err = sysdbus.StoreProp(props, "Seat", &seat); err != nil {
+		return nil, err
+	}
+	return &SessionProperties{
+		ID:            sysdbus.Prop[string](props, "Id"),
+		UID:           user.UID,
+		User:          sysdbus.Prop[string](props, "Name"),
+		Seat:          seat.ID,
+		Timestamp:     sysdbus.Timestamp(props, "Timestamp"),
+		VTNr:          sysdbus.Prop[uint32](props, "VTNr"),
+		TTY:           sysdbus.Prop[string](props, "TTY"),
+		Display:       sysdbus.Prop[string](props, "Display"),
+		Remote:        sysdbus.Prop[bool](props, "Remote"),
+		RemoteHost:    sysdbus.Prop[string](props, "RemoteHost"),
+		RemoteUser:    sysdbus.Prop[string](props, "RemoteUser"),
+		Service:       sysdbus.Prop[string](props, "Service"),
+		Desktop:       sysdbus.Prop[string](props, "Desktop"),
+		Scope:         sysdbus.Prop[string](props, "Scope"),
+		Leader:        sysdbus.Prop[uint32](props, "Leader"),
+		Type:          sysdbus.Prop[string](props, "Type"),
+		Class:         sysdbus.Prop[string](props, "Class"),
+		Active:        sysdbus.Prop[bool](props, "Active"),
+		State:         sysdbus.Prop[string](props, "State"),
+		IdleHint:      sysdbus.Prop[bool](props, "IdleHint"),
+		IdleSinceHint: sysdbus.Timestamp(props, "IdleSinceHint"),
+		LockedHint:    sysdbus.Prop[bool](props, "LockedHint"),
+	}, nil
+}
+
+// GetSessionProperties returns the typed properties of the session id.
+// ctx: Context to use
+// id: session ID
+func (c *Conn) GetSessionProperties(ctx context.Context, id string) (*SessionProperties, error) {
+	s, err := c.Session(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.Properties(ctx)
+}
@@ -0,0 +1,55 @@
+package login1
+
+import (
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// addMatch adds, once per member, the match for the signal member of iface.
+func (c *Conn) addMatch(iface, member string) error {
+	key := iface + "." + member
+	c.sigMu.Lock()
+	defer c.sigMu.Unlock()
+	if _, ok := c.matches[key]; ok {
+		return nil
+	}
+	err := c.conn.AddMatchSignal(
+		dbus.WithMatchSender(dbusDest),
+		dbus.WithMatchInterface(iface),
+		dbus.WithMatchMember(member),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add %s signal match: %w", key, err)
+	}
+	if len(c.matches) == 0 {
+		ch := make(chan *dbus.Signal, 64)
+		c.conn.Signal(ch)
+		go c.dispatch(ch)
+	}
+	c.matches[key] = struct{}{}
+	return nil
+}
+
+// handle registers fn to be called for every signal received, until remove is called.
+// fn must not block as it is called from the dispatch goroutine.
+func (c *Conn) handle(fn func(*dbus.Signal)) (remove func()) {
+	c.sigMu.Lock()
+	c.handlers[&fn] = struct{}{}
+	c.sigMu.Unlock()
+	return func() {
+		c.sigMu.Lock()
+		delete(c.handlers, &fn)
+		c.sigMu.Unlock()
+	}
+}
+
+func (c *Conn) dispatch(ch chan *dbus.Signal) {
+	for sig := range ch {
+		c.sigMu.Lock()
+		for fn := range c.handlers {
+			(*fn)(sig)
+		}
+		c.sigMu.Unlock()
+	}
+}
@@ -0,0 +1,49 @@
+package login1
+
+import (
+	"context"
+	"syscall"
+)
+
+// KillWho selects which processes of a session KillSession signals.
+type KillWho string
+
+const (
+	KillLeader KillWho = "leader" // session leader only
+	KillAll    KillWho = "all"    // every process of the session
+)
+
+// TerminateSession terminates the session id by killing all its processes.
+// It fails with ErrNoSuchSession or ErrAccessDenied accordingly.
+// ctx: Context to use
+// id: session ID
+func (c *Conn) TerminateSession(ctx context.Context, id string) error {
+	return typedError(c.Call(ctx, "TerminateSession", id).Store())
+}
+
+// KillSession sends signal to the processes of the session id selected by who.
+// It fails with ErrNoSuchSession or ErrAccessDenied accordingly.
+// ctx: Context to use
+// id: session ID
+// who: processes to signal
+// signal: signal to send
+func (c *Conn) KillSession(ctx context.Context, id string, who KillWho, signal syscall.Signal) error {
+	return typedError(c.Call(ctx, "KillSession", id, string(who), int32(signal)).Store())
+}
+
+// TerminateUser terminates every session of the user uid by killing all its processes.
+// It fails with ErrNoSuchUser or ErrAccessDenied accordingly.
+// ctx: Context to use
+// uid: user ID
+func (c *Conn) TerminateUser(ctx context.Context, uid uint32) error {
+	return typedError(c.Call(ctx, "TerminateUser", uid).Store())
+}
+
+// KillUser sends signal to every process of the user uid.
+// It fails with ErrNoSuchUser or ErrAccessDenied accordingly.
+// ctx: Context to use
+// uid: user ID
+// signal: signal to send
+func (c *Conn) KillUser(ctx context.Context, uid uint32, signal syscall.Signal) error {
+	return typedError(c.Call(ctx, "KillUser", uid, int32(signal)).Store())
+}
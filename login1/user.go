@@ -0,0 +1,134 @@
+package login1
+
+import (
+	"context"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+const userInterface = "org.freedesktop.login1.User"
+
+// User represents a user object of logind.
+type User struct {
+	UID  uint32          // user ID
+	Path dbus.ObjectPath // user object path
+	c    *Conn
+	obj  dbus.BusObject
+}
+
+// GetUser returns the user object path of the user uid, it must be logged in (or lingering).
+// ctx: Context to use
+// uid: user ID
+func (c *Conn) GetUser(ctx context.Context, uid uint32) (path dbus.ObjectPath, err error) {
+	err = c.Call(ctx, "GetUser", uid).Store(&path)
+	return
+}
+
+// User returns the user uid, it must be logged in (or lingering).
+// ctx: Context to use
+// uid: user ID
+func (c *Conn) User(ctx context.Context, uid uint32) (*User, error) {
+	path, err := c.GetUser(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return c.user(uid, path), nil
+}
+
+func (c *Conn) user(uid uint32, path dbus.ObjectPath) *User {
+	return &User{
+		UID:  uid,
+		Path: path,
+		c:    c,
+		obj:  c.conn.Object(dbusDest, path),
+	}
+}
+
+// Property returns the raw value of a user property.
+func (u *User) Property(ctx context.Context, name string) (dbus.Variant, error) {
+	return sysdbus.GetProperty(ctx, u.obj, userInterface, name)
+}
+
+// UserProperties holds the most used properties of a user.
+type UserProperties struct {
+	UID           uint32
+	GID           uint32
+	Name          string
+	Timestamp     time.Time // first login time
+	RuntimePath   string    // XDG_RUNTIME_DIR of the user
+	Service       string    // user manager unit (eg: user@1000.service)
+	Slice         string    // user slice unit (eg: user-1000.slice)
+	Display       string    // ID of the graphical session of the user, if any
+	State         string    // offline, lingering, online, active or closing
+	Sessions      []string  // IDs of the sessions of the user
+	IdleHint      bool
+	IdleSinceHint time.Time
+	Linger        bool
+}
+
+// Properties returns the typed user properties.
+// ctx: Context to use
+func (u *User) Properties(ctx context.Context) (*UserProperties, error) {
+	props, err := sysdbus.GetAllProperties(ctx, u.obj, userInterface)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		display struct {
+			ID   string
+			Path dbus.ObjectPath
+		}
+		sessions []struct {
+			ID   string
+			Path dbus.ObjectPath
+		}
+	)
+	if err = sysdbus.StoreProp(props, "Display", &display); err != nil {
+		return nil, err
+	}
+	if err = sysdbus.StoreProp(props, "Sessions", &sessions); err != nil {
+		return nil, err
+	}
+	up := &UserProperties{
+		UID:           sysdbus.Prop[uint32](props, "UID"),
+		GID:           sysdbus.Prop[uint32](props, "GID"),
+		Name:          sysdbus.Prop[string](props, "Name"),
+		Timestamp:     sysdbus.Timestamp(props, "Timestamp"),
+		RuntimePath:   sysdbus.Prop[string](props, "RuntimePath"),
+		Service:       sysdbus.Prop[string](props, "Service"),
+		Slice:         sysdbus.Prop[string](props, "Slice"),
+		Display:       display.ID,
+		State:         sysdbus.Prop[string](props, "State"),
+		Sessions:      make([]string, len(sessions)),
+		IdleHint:      sysdbus.Prop[bool](props, "IdleHint"),
+		IdleSinceHint: sysdbus.Timestamp(props, "IdleSinceHint"),
+		Linger:        sysdbus.Prop[bool](props, "Linger"),
+	}
+	for i, s := range sessions {
+		up.Sessions[i] = s.ID
+	}
+	return up, nil
+}
+
+// GetUserProperties returns the typed properties of the user uid.
+// ctx: Context to use
+// uid: user ID
+func (c *Conn) GetUserProperties(ctx context.Context, uid uint32) (*UserProperties, error) {
+	u, err := c.User(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return u.Properties(ctx)
+}
+
+// SetUserLinger enables or disables lingering for the user uid: its user manager is started at boot
+// and kept running without any session, so its user units can run unattended (eg: service accounts).
+// It fails with ErrAccessDenied if not allowed, see WithInteractiveAuthorization.
+// ctx: Context to use
+// uid: user ID
+// enable: whether to enable lingering
+func (c *Conn) SetUserLinger(ctx context.Context, uid uint32, enable bool) error {
+	return typedError(c.Call(ctx, "SetUserLinger", uid, enable, c.interactive()).Store())
+}
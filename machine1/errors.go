@@ -0,0 +1,30 @@
+package machine1
+
+import (
+	"errors"
+
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+var (
+	// ErrNoSuchMachine is returned when the machine is not registered.
+	ErrNoSuchMachine = errors.New("no such machine")
+	// ErrNoSuchImage is returned when the image does not exist.
+	ErrNoSuchImage = errors.New("no such image")
+	// ErrNoMachineForPID is returned when the process is not part of any machine.
+	ErrNoMachineForPID = errors.New("no machine for PID")
+	// ErrAccessDenied is returned when polkit (or the caller privileges) denied the operation,
+	// see WithInteractiveAuthorization.
+	ErrAccessDenied = sysdbus.ErrAccessDenied
+)
+
+var dbusErrors = map[string]error{
+	dbusDest + ".NoSuchMachine":   ErrNoSuchMachine,
+	dbusDest + ".NoSuchImage":     ErrNoSuchImage,
+	dbusDest + ".NoMachineForPID": ErrNoMachineForPID,
+}
+
+// typedError wraps the known machined dbus errors into the Err* errors, so they can be tested with errors.Is.
+func typedError(err error) error {
+	return sysdbus.TypedError(err, dbusErrors)
+}
@@ -0,0 +1,92 @@
+package machine1
+
+import (
+	"context"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// ImageStatus represents a machine image as returned by ListImages.
+type ImageStatus struct {
+	Name      string
+	Type      string // directory, subvolume, raw or block
+	ReadOnly  bool
+	Created   time.Time // zero if unknown
+	Modified  time.Time // zero if unknown
+	DiskUsage uint64    // bytes, ^uint64(0) if unknown
+	Path      dbus.ObjectPath
+}
+
+// imageStatus is the dbus (ssbttto) representation of ImageStatus.
+type imageStatus struct {
+	Name      string
+	Type      string
+	ReadOnly  bool
+	Created   uint64
+	Modified  uint64
+	DiskUsage uint64
+	Path      dbus.ObjectPath
+}
+
+// ListImages returns the machine images found in the image directories (eg: /var/lib/machines).
+// ctx: Context to use
+func (c *Conn) ListImages(ctx context.Context) ([]ImageStatus, error) {
+	var raw []imageStatus
+	if err := c.Call(ctx, "ListImages").Store(&raw); err != nil {
+		return nil, err
+	}
+	images := make([]ImageStatus, len(raw))
+	for i, img := range raw {
+		images[i] = ImageStatus{
+			Name:      img.Name,
+			Type:      img.Type,
+			ReadOnly:  img.ReadOnly,
+			Created:   usecTime(img.Created),
+			Modified:  usecTime(img.Modified),
+			DiskUsage: img.DiskUsage,
+			Path:      img.Path,
+		}
+	}
+	return images, nil
+}
+
+// CloneImage copies the image name to newName, cheaply on btrfs subvolumes.
+// ctx: Context to use
+// name: image name
+// newName: name of the copy
+// readOnly: whether the copy is read only
+func (c *Conn) CloneImage(ctx context.Context, name, newName string, readOnly bool) error {
+	return typedError(c.Call(ctx, "CloneImage", name, newName, readOnly).Store())
+}
+
+// RemoveImage removes the image name, it fails if the image is in use by a running machine.
+// ctx: Context to use
+// name: image name
+func (c *Conn) RemoveImage(ctx context.Context, name string) error {
+	return typedError(c.Call(ctx, "RemoveImage", name).Store())
+}
+
+// RenameImage renames the image name to newName.
+// ctx: Context to use
+// name: image name
+// newName: new image name
+func (c *Conn) RenameImage(ctx context.Context, name, newName string) error {
+	return typedError(c.Call(ctx, "RenameImage", name, newName).Store())
+}
+
+// MarkImageReadOnly toggles the read only flag of the image name.
+// ctx: Context to use
+// name: image name
+// readOnly: whether the image is read only
+func (c *Conn) MarkImageReadOnly(ctx context.Context, name string, readOnly bool) error {
+	return typedError(c.Call(ctx, "MarkImageReadOnly", name, readOnly).Store())
+}
+
+// usecTime returns the µs since epoch usec as a time.Time, zero if unset.
+func usecTime(usec uint64) time.Time {
+	if usec == 0 || usec == ^uint64(0) {
+		return time.Time{}
+	}
+	return time.UnixMicro(int64(usec))
+}
@@ -0,0 +1,171 @@
+package machine1
+
+import (
+	"context"
+	"encoding/hex"
+	"net/netip"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+const machineInterface = "org.freedesktop.machine1.Machine"
+
+// MachineStatus represents a machine as returned by ListMachines.
+type MachineStatus struct {
+	Name    string          // machine name
+	Class   string          // container or vm
+	Service string          // registering service (eg: systemd-nspawn, libvirt-lxc)
+	Path    dbus.ObjectPath // machine object path
+}
+
+// ListMachines returns the registered machines.
+// ctx: Context to use
+func (c *Conn) ListMachines(ctx context.Context) (machines []MachineStatus, err error) {
+	err = c.Call(ctx, "ListMachines").Store(&machines)
+	return
+}
+
+// GetMachine returns the machine object path of the machine name.
+// ctx: Context to use
+// name: machine name
+func (c *Conn) GetMachine(ctx context.Context, name string) (path dbus.ObjectPath, err error) {
+	err = typedError(c.Call(ctx, "GetMachine", name).Store(&path))
+	return
+}
+
+// Machine represents a machine object of machined.
+type Machine struct {
+	Name string          // machine name
+	Path dbus.ObjectPath // machine object path
+	c    *Conn
+	obj  dbus.BusObject
+}
+
+// Machine returns the machine name.
+// ctx: Context to use
+// name: machine name
+func (c *Conn) Machine(ctx context.Context, name string) (*Machine, error) {
+	path, err := c.GetMachine(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return c.machine(name, path), nil
+}
+
+func (c *Conn) machine(name string, path dbus.ObjectPath) *Machine {
+	return &Machine{
+		Name: name,
+		Path: path,
+		c:    c,
+		obj:  c.conn.Object(dbusDest, path),
+	}
+}
+
+// Property returns the raw value of a machine property.
+func (m *Machine) Property(ctx context.Context, name string) (dbus.Variant, error) {
+	return sysdbus.GetProperty(ctx, m.obj, machineInterface, name)
+}
+
+// MachineProperties holds the properties of a machine.
+type MachineProperties struct {
+	Name              string
+	ID                string // machine ID (32 hex digits), empty if unknown
+	Timestamp         time.Time
+	Service           string
+	Unit              string // scope or service unit of the machine
+	Leader            uint32 // PID of the machine leader process (eg: the container init)
+	Class             string // container or vm
+	RootDirectory     string // containers only, empty if unknown
+	NetworkInterfaces []int  // host side interface indexes of the machine
+	State             string // opening, running or closing
+}
+
+// Properties returns the typed machine properties.
+// ctx: Context to use
+func (m *Machine) Properties(ctx context.Context) (*MachineProperties, error) {
+	props, err := sysdbus.GetAllProperties(ctx, m.obj, machineInterface)
+	if err != nil {
+		return nil, typedError(err)
+	}
+	p := &MachineProperties{
+		Name:          sysdbus.Prop[string](props, "Name"),
+		Timestamp:     sysdbus.Timestamp(props, "Timestamp"),
+		Service:       sysdbus.Prop[string](props, "Service"),
+		Unit:          sysdbus.Prop[string](props, "Unit"),
+		Leader:        sysdbus.Prop[uint32](props, "Leader"),
+		Class:         sysdbus.Prop[string](props, "Class"),
+		RootDirectory: sysdbus.Prop[string](props, "RootDirectory"),
+		State:         sysdbus.Prop[string](props, "State"),
+	}
+	if id := sysdbus.Prop[[]byte](props, "Id"); len(id) == 16 && !zero(id) {
+		p.ID = hex.EncodeToString(id)
+	}
+	for _, index := range sysdbus.Prop[[]int32](props, "NetworkInterfaces") {
+		p.NetworkInterfaces = append(p.NetworkInterfaces, int(index))
+	}
+	return p, nil
+}
+
+func zero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// machineAddress is the dbus (iay) representation of an address.
+type machineAddress struct {
+	Family  int32
+	Address []byte
+}
+
+// GetMachineAddresses returns the IP addresses of the machine name, as seen from inside the machine
+// (containers with their own network namespace only).
+// ctx: Context to use
+// name: machine name
+func (c *Conn) GetMachineAddresses(ctx context.Context, name string) ([]netip.Addr, error) {
+	var raw []machineAddress
+	if err := c.Call(ctx, "GetMachineAddresses", name).Store(&raw); err != nil {
+		return nil, typedError(err)
+	}
+	return addresses(raw), nil
+}
+
+// Addresses returns the IP addresses of the machine, see Conn.GetMachineAddresses.
+// ctx: Context to use
+func (m *Machine) Addresses(ctx context.Context) ([]netip.Addr, error) {
+	var raw []machineAddress
+	if err := m.obj.CallWithContext(ctx, machineInterface+".GetAddresses", m.c.flags).Store(&raw); err != nil {
+		return nil, typedError(err)
+	}
+	return addresses(raw), nil
+}
+
+func addresses(raw []machineAddress) []netip.Addr {
+	list := make([]netip.Addr, 0, len(raw))
+	for _, a := range raw {
+		if addr, ok := netip.AddrFromSlice(a.Address); ok {
+			list = append(list, addr)
+		}
+	}
+	return list
+}
+
+// GetMachineOSRelease returns the os-release(5) fields of the machine name (eg: ID, VERSION_ID).
+// ctx: Context to use
+// name: machine name
+func (c *Conn) GetMachineOSRelease(ctx context.Context, name string) (fields map[string]string, err error) {
+	err = typedError(c.Call(ctx, "GetMachineOSRelease", name).Store(&fields))
+	return
+}
+
+// OSRelease returns the os-release(5) fields of the machine, see Conn.GetMachineOSRelease.
+// ctx: Context to use
+func (m *Machine) OSRelease(ctx context.Context) (fields map[string]string, err error) {
+	err = typedError(m.obj.CallWithContext(ctx, machineInterface+".GetOSRelease", m.c.flags).Store(&fields))
+	return
+}
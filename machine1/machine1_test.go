@@ -0,0 +1,35 @@
+package machine1
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestAddresses(t *testing.T) {
+	list := addresses([]machineAddress{
+		{Family: 2, Address: []byte{10, 0, 0, 2}},
+		{Family: 10, Address: netip.MustParseAddr("fe80::1").AsSlice()},
+		{Family: 2, Address: []byte{1, 2}},
+	})
+	if len(list) != 2 || list[0].String() != "10.0.0.2" || list[1].String() != "fe80::1" {
+		t.Errorf("unexpected addresses: %v", list)
+	}
+}
+
+func TestRegistrationID(t *testing.T) {
+	if id, err := (Registration{}).id(); err != nil || len(id) != 0 {
+		t.Errorf("unexpected empty ID %v, %v", id, err)
+	}
+	if id, err := (Registration{ID: "0123456789abcdef0123456789abcdef"}).id(); err != nil || len(id) != 16 {
+		t.Errorf("unexpected ID %v, %v", id, err)
+	}
+	if _, err := (Registration{ID: "0123"}).id(); err == nil {
+		t.Error("short ID accepted")
+	}
+}
+
+func TestMachineNameFromPath(t *testing.T) {
+	if name := machineNameFromPath(machinePathPrefix + "my_2dcontainer"); name != "my-container" {
+		t.Errorf("unexpected machine name %q", name)
+	}
+}
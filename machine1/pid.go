@@ -0,0 +1,75 @@
+package machine1
+
+import (
+	"context"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+const machinePathPrefix = dbusPath + "/machine/"
+
+// GetMachineByPID returns the machine object path of the machine the process pid belongs to.
+// It fails with ErrNoMachineForPID if the process is not part of any machine (eg: a host process).
+// ctx: Context to use
+// pid: process ID, 0 being the caller
+func (c *Conn) GetMachineByPID(ctx context.Context, pid uint32) (path dbus.ObjectPath, err error) {
+	err = typedError(c.Call(ctx, "GetMachineByPID", pid).Store(&path))
+	return
+}
+
+// MachineByPID returns the machine the process pid belongs to, see GetMachineByPID.
+// ctx: Context to use
+// pid: process ID, 0 being the caller
+func (c *Conn) MachineByPID(ctx context.Context, pid uint32) (*Machine, error) {
+	path, err := c.GetMachineByPID(ctx, pid)
+	if err != nil {
+		return nil, err
+	}
+	return c.machine(machineNameFromPath(path), path), nil
+}
+
+// machineNameFromPath unescapes a machine object path back to the machine name.
+func machineNameFromPath(path dbus.ObjectPath) string {
+	escaped, _ := strings.CutPrefix(string(path), machinePathPrefix)
+	return sysdbus.UnescapePathElement(escaped)
+}
+
+// Leader returns the PID of the machine leader process (eg: the container init).
+// ctx: Context to use
+func (m *Machine) Leader(ctx context.Context) (uint32, error) {
+	v, err := m.Property(ctx, "Leader")
+	if err != nil {
+		return 0, typedError(err)
+	}
+	pid, _ := v.Value().(uint32)
+	return pid, nil
+}
+
+// Class returns the class of the machine, container or vm.
+// ctx: Context to use
+func (m *Machine) Class(ctx context.Context) (string, error) {
+	return m.stringProperty(ctx, "Class")
+}
+
+// RootDirectory returns the root directory of the container, empty if unknown or for virtual machines.
+// ctx: Context to use
+func (m *Machine) RootDirectory(ctx context.Context) (string, error) {
+	return m.stringProperty(ctx, "RootDirectory")
+}
+
+// Unit returns the scope or service unit of the machine.
+// ctx: Context to use
+func (m *Machine) Unit(ctx context.Context) (string, error) {
+	return m.stringProperty(ctx, "Unit")
+}
+
+func (m *Machine) stringProperty(ctx context.Context, name string) (string, error) {
+	v, err := m.Property(ctx, name)
+	if err != nil {
+		return "", typedError(err)
+	}
+	s, _ := v.Value().(string)
+	return s, nil
+}
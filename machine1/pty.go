@@ -0,0 +1,96 @@
+package machine1
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"syscall"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// PTY is the master side of a pseudo terminal allocated inside a machine.
+// Reads and writes go to the process attached to it, deadlines are supported.
+// Close it once done, which hangs up the terminal.
+type PTY struct {
+	*os.File
+	Path string // path of the terminal inside the machine (eg: /dev/pts/0)
+}
+
+func newPTY(fd dbus.UnixFD, path string) (*PTY, error) {
+	// non blocking, so the file is handled by the runtime poller and supports deadlines
+	if err := syscall.SetNonblock(int(fd), true); err != nil {
+		syscall.Close(int(fd))
+		return nil, fmt.Errorf("failed to set pty non blocking: %w", err)
+	}
+	return &PTY{
+		File: os.NewFile(uintptr(fd), path),
+		Path: path,
+	}, nil
+}
+
+func (c *Conn) openPTY(ctx context.Context, method string, args ...interface{}) (*PTY, error) {
+	var (
+		fd   dbus.UnixFD
+		path string
+	)
+	if err := c.Call(ctx, method, args...).Store(&fd, &path); err != nil {
+		return nil, typedError(err)
+	}
+	return newPTY(fd, path)
+}
+
+// OpenMachinePTY allocates a pseudo terminal inside the machine name, without attaching any process to it.
+// ctx: Context to use
+// name: machine name
+func (c *Conn) OpenMachinePTY(ctx context.Context, name string) (*PTY, error) {
+	return c.openPTY(ctx, "OpenMachinePTY", name)
+}
+
+// OpenMachineLogin allocates a pseudo terminal inside the machine name and starts a getty login prompt on it,
+// like "machinectl login". The machine must run systemd.
+// ctx: Context to use
+// name: machine name
+func (c *Conn) OpenMachineLogin(ctx context.Context, name string) (*PTY, error) {
+	return c.openPTY(ctx, "OpenMachineLogin", name)
+}
+
+// OpenMachineShell runs a command inside the machine name, attached to a new pseudo terminal,
+// like "machinectl shell". The machine must run systemd.
+// ctx: Context to use
+// name: machine name
+// user: user to run the command as, empty for root
+// path: absolute path of the binary, empty for the user shell (args must then be empty too)
+// args: argv, including argv[0]
+// env: additional environment, KEY=value entries
+func (c *Conn) OpenMachineShell(ctx context.Context, name, user, path string, args, env []string) (*PTY, error) {
+	if args == nil {
+		args = []string{}
+	}
+	if env == nil {
+		env = []string{}
+	}
+	return c.openPTY(ctx, "OpenMachineShell", name, user, path, args, env)
+}
+
+// OpenPTY allocates a pseudo terminal inside the machine, see Conn.OpenMachinePTY.
+// ctx: Context to use
+func (m *Machine) OpenPTY(ctx context.Context) (*PTY, error) {
+	return m.c.OpenMachinePTY(ctx, m.Name)
+}
+
+// OpenLogin starts a login prompt inside the machine, see Conn.OpenMachineLogin.
+// ctx: Context to use
+func (m *Machine) OpenLogin(ctx context.Context) (*PTY, error) {
+	return m.c.OpenMachineLogin(ctx, m.Name)
+}
+
+// OpenShell runs a command inside the machine, see Conn.OpenMachineShell.
+// ctx: Context to use
+// user: user to run the command as, empty for root
+// path: absolute path of the binary, empty for the user shell
+// args: argv, including argv[0]
+// env: additional environment, KEY=value entries
+func (m *Machine) OpenShell(ctx context.Context, user, path string, args, env []string) (*PTY, error) {
+	return m.c.OpenMachineShell(ctx, m.Name, user, path, args, env)
+}
@@ -0,0 +1,100 @@
+package machine1
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/iguanesolutions/go-systemd/v6/systemd1"
+)
+
+// Registration describes a machine to register with RegisterMachine or CreateMachine.
+type Registration struct {
+	Name              string // machine name, a valid hostname
+	ID                string // machine ID (32 hex digits), empty if unknown
+	Service           string // name of the registering runtime (eg: my-runtime)
+	Class             string // container or vm
+	Leader            uint32 // PID of the machine leader process (eg: the container init)
+	RootDirectory     string // containers only, empty if unknown
+	NetworkInterfaces []int  // host side interface indexes of the machine, if any
+}
+
+func (r Registration) id() ([]byte, error) {
+	if r.ID == "" {
+		return []byte{}, nil
+	}
+	id, err := hex.DecodeString(r.ID)
+	if err != nil || len(id) != 16 {
+		return nil, fmt.Errorf("invalid machine ID %q", r.ID)
+	}
+	return id, nil
+}
+
+func (r Registration) ifindexes() []int32 {
+	list := make([]int32, len(r.NetworkInterfaces))
+	for i, index := range r.NetworkInterfaces {
+		list[i] = int32(index)
+	}
+	return list
+}
+
+// RegisterMachine registers a machine whose processes already live in their own unit (eg: the service
+// of the container runtime), which makes it visible to machinectl and attributes its logs in the journal.
+// ctx: Context to use
+// r: machine to register
+func (c *Conn) RegisterMachine(ctx context.Context, r Registration) (*Machine, error) {
+	id, err := r.id()
+	if err != nil {
+		return nil, err
+	}
+	method, args := "RegisterMachine", []interface{}{r.Name, id, r.Service, r.Class, r.Leader, r.RootDirectory}
+	if len(r.NetworkInterfaces) > 0 {
+		method, args = "RegisterMachineWithNetwork", append(args, r.ifindexes())
+	}
+	return c.register(ctx, r.Name, method, args...)
+}
+
+// CreateMachine registers a machine like RegisterMachine, but first moves the leader process into a new
+// scope unit (machine-<name>.scope) created with the given properties (eg: systemd1.PropMemoryMax).
+// ctx: Context to use
+// r: machine to register
+// properties: scope unit properties
+func (c *Conn) CreateMachine(ctx context.Context, r Registration, properties ...systemd1.Property) (*Machine, error) {
+	id, err := r.id()
+	if err != nil {
+		return nil, err
+	}
+	if properties == nil {
+		properties = []systemd1.Property{}
+	}
+	method, args := "CreateMachine", []interface{}{r.Name, id, r.Service, r.Class, r.Leader, r.RootDirectory}
+	if len(r.NetworkInterfaces) > 0 {
+		method, args = "CreateMachineWithNetwork", append(args, r.ifindexes())
+	}
+	return c.register(ctx, r.Name, method, append(args, properties)...)
+}
+
+func (c *Conn) register(ctx context.Context, name, method string, args ...interface{}) (*Machine, error) {
+	m := &Machine{
+		Name: name,
+		c:    c,
+	}
+	if err := c.Call(ctx, method, args...).Store(&m.Path); err != nil {
+		return nil, typedError(err)
+	}
+	m.obj = c.conn.Object(dbusDest, m.Path)
+	return m, nil
+}
+
+// TerminateMachine kills every process of the machine name and unregisters it.
+// ctx: Context to use
+// name: machine name
+func (c *Conn) TerminateMachine(ctx context.Context, name string) error {
+	return typedError(c.Call(ctx, "TerminateMachine", name).Store())
+}
+
+// Terminate kills every process of the machine and unregisters it.
+// ctx: Context to use
+func (m *Machine) Terminate(ctx context.Context) error {
+	return m.c.TerminateMachine(ctx, m.Name)
+}
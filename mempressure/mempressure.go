@@ -0,0 +1,115 @@
+// Package sysdmempressure implements systemd's memory pressure protocol, so services can release
+// memory (eg: shed caches) when their unit is under memory pressure. The manager passes the file to
+// watch thru $MEMORY_PRESSURE_WATCH (MemoryPressureWatch= of the unit) and the trigger to write to it
+// thru $MEMORY_PRESSURE_WRITE, see https://systemd.io/MEMORY_PRESSURE/
+package sysdmempressure
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/iguanesolutions/go-systemd/v6/internal/cgroupfs"
+)
+
+const (
+	// DefaultThreshold is the stall time within DefaultWindow triggering an event when the
+	// manager did not pass a watch, as sd-event does.
+	DefaultThreshold = 200 * time.Millisecond
+	// DefaultWindow is the PSI window used when the manager did not pass a watch.
+	DefaultWindow = 2 * time.Second
+)
+
+// ErrDisabled is returned when memory pressure monitoring has been turned off for the unit
+// (MemoryPressureWatch=off) or is not available.
+var ErrDisabled = errors.New("memory pressure monitoring is disabled")
+
+// Watch describes what to watch for memory pressure events.
+type Watch struct {
+	// Path is a PSI file (eg: the memory.pressure file of the unit cgroup), a FIFO or an AF_UNIX stream socket.
+	Path string
+	// Write is written to Path once opened, the PSI trigger (eg: "some 200000 2000000") for PSI files.
+	Write []byte
+}
+
+// WatchFromEnv returns the watch passed by the manager thru $MEMORY_PRESSURE_WATCH and $MEMORY_PRESSURE_WRITE.
+// If none has been passed, it defaults on the memory.pressure file of the cgroup of the calling process
+// with DefaultThreshold and DefaultWindow, ErrDisabled is returned if it does not exist.
+func WatchFromEnv() (w Watch, err error) {
+	path, ok := os.LookupEnv("MEMORY_PRESSURE_WATCH")
+	if !ok {
+		return DefaultWatch()
+	}
+	if path == "" || path == "/dev/null" {
+		return w, ErrDisabled
+	}
+	w.Path = path
+	if write := os.Getenv("MEMORY_PRESSURE_WRITE"); write != "" {
+		if w.Write, err = base64.StdEncoding.DecodeString(write); err != nil {
+			return w, fmt.Errorf("can't decode MEMORY_PRESSURE_WRITE: %w", err)
+		}
+	}
+	return
+}
+
+// DefaultWatch returns a watch on the memory.pressure file of the cgroup of the calling process
+// (cgroup v2 only) with DefaultThreshold and DefaultWindow.
+func DefaultWatch() (w Watch, err error) {
+	if !cgroupfs.Unified() {
+		return w, fmt.Errorf("%w: no cgroup v2 hierarchy", ErrDisabled)
+	}
+	cgroup, err := cgroupfs.Read(0)
+	if err != nil {
+		return w, fmt.Errorf("%w: %w", ErrDisabled, err)
+	}
+	w.Path = filepath.Join(cgroupfs.Dir(cgroup), "memory.pressure")
+	if _, err = os.Stat(w.Path); err != nil {
+		return w, fmt.Errorf("%w: %w", ErrDisabled, err)
+	}
+	w.Write = []byte(Trigger(DefaultThreshold, DefaultWindow))
+	return
+}
+
+// Trigger returns the PSI trigger for a "some" stall of threshold within window.
+func Trigger(threshold, window time.Duration) string {
+	return fmt.Sprintf("some %d %d", threshold.Microseconds(), window.Microseconds())
+}
+
+// Event is a memory pressure event.
+type Event struct {
+	Time time.Time // when the event has been received
+}
+
+// Handler is called for each memory pressure event.
+type Handler func(Event)
+
+// Monitor delivers the memory pressure events of a watch, see Start.
+type Monitor struct {
+	// C receives the events, it is closed by Close or when the watch ends (eg: the cgroup has been removed).
+	// Events are dropped while C is full: one pending event is enough to know memory must be released.
+	C <-chan Event
+
+	out      chan Event
+	handlers []Handler
+	file     *os.File
+	epfd     int
+	wake     [2]int // pipe waking up the loop on Close
+	events   uint32
+	once     sync.Once
+	done     chan struct{}
+}
+
+// Start starts monitoring the watch passed by the manager, see WatchFromEnv.
+// handlers are called synchronously from the monitor goroutine for each event, before it is sent
+// on the channel, they must not block. Close must be called once done with it.
+func Start(handlers ...Handler) (*Monitor, error) {
+	w, err := WatchFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return StartWatch(w, handlers...)
+}
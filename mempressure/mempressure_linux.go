@@ -0,0 +1,162 @@
+package sysdmempressure
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+)
+
+// StartWatch starts monitoring w, see Start.
+func StartWatch(w Watch, handlers ...Handler) (m *Monitor, err error) {
+	m = &Monitor{
+		out:      make(chan Event, 1),
+		handlers: handlers,
+		epfd:     -1,
+		wake:     [2]int{-1, -1},
+		done:     make(chan struct{}),
+	}
+	m.C = m.out
+	defer func() {
+		if err != nil {
+			m.release()
+		}
+	}()
+	if err = m.open(w); err != nil {
+		return nil, fmt.Errorf("can't open memory pressure watch %s: %w", w.Path, err)
+	}
+	if len(w.Write) > 0 {
+		if _, err = m.file.Write(w.Write); err != nil {
+			return nil, fmt.Errorf("can't write memory pressure trigger to %s: %w", w.Path, err)
+		}
+	}
+	if err = m.setupEpoll(); err != nil {
+		return nil, err
+	}
+	go m.run()
+	return m, nil
+}
+
+func (m *Monitor) open(w Watch) error {
+	info, err := os.Stat(w.Path)
+	if err != nil {
+		return err
+	}
+	switch mode := info.Mode(); {
+	case mode&os.ModeSocket != 0:
+		fd, err := syscall.Socket(syscall.AF_UNIX, syscall.SOCK_STREAM|syscall.SOCK_CLOEXEC, 0)
+		if err != nil {
+			return err
+		}
+		if err = syscall.Connect(fd, &syscall.SockaddrUnix{Name: w.Path}); err != nil {
+			syscall.Close(fd)
+			return err
+		}
+		m.file = os.NewFile(uintptr(fd), w.Path)
+		m.events = syscall.EPOLLIN
+	case mode&os.ModeNamedPipe != 0:
+		// opened read-write so the FIFO never reports a hang up when there is no writer
+		if m.file, err = os.OpenFile(w.Path, os.O_RDWR, 0); err != nil {
+			return err
+		}
+		m.events = syscall.EPOLLIN
+	default:
+		// PSI files signal the trigger with POLLPRI
+		if m.file, err = os.OpenFile(w.Path, os.O_RDWR, 0); err != nil {
+			return err
+		}
+		m.events = syscall.EPOLLPRI
+	}
+	return nil
+}
+
+func (m *Monitor) setupEpoll() (err error) {
+	if err = syscall.Pipe2(m.wake[:], syscall.O_CLOEXEC|syscall.O_NONBLOCK); err != nil {
+		return err
+	}
+	if m.epfd, err = syscall.EpollCreate1(syscall.EPOLL_CLOEXEC); err != nil {
+		return err
+	}
+	if err = syscall.EpollCtl(m.epfd, syscall.EPOLL_CTL_ADD, int(m.file.Fd()), &syscall.EpollEvent{
+		Events: m.events,
+		Fd:     int32(m.file.Fd()),
+	}); err != nil {
+		return err
+	}
+	return syscall.EpollCtl(m.epfd, syscall.EPOLL_CTL_ADD, m.wake[0], &syscall.EpollEvent{
+		Events: syscall.EPOLLIN,
+		Fd:     int32(m.wake[0]),
+	})
+}
+
+func (m *Monitor) run() {
+	defer close(m.out)
+	fd := int32(m.file.Fd())
+	events := make([]syscall.EpollEvent, 2)
+	buf := make([]byte, 256)
+	for {
+		n, err := syscall.EpollWait(m.epfd, events, -1)
+		if errors.Is(err, syscall.EINTR) {
+			continue
+		}
+		if err != nil {
+			return
+		}
+		triggered := false
+		for _, e := range events[:n] {
+			if e.Fd != fd {
+				return // woken up by Close
+			}
+			if e.Events&(syscall.EPOLLERR|syscall.EPOLLHUP) != 0 && e.Events&m.events == 0 {
+				return // the watch ended
+			}
+			if m.events == syscall.EPOLLIN {
+				// FIFOs and sockets carry data that must be drained
+				if read, err := syscall.Read(int(fd), buf); read <= 0 && !errors.Is(err, syscall.EAGAIN) {
+					return
+				}
+			}
+			triggered = true
+		}
+		if !triggered {
+			continue
+		}
+		event := Event{Time: time.Now()}
+		for _, handler := range m.handlers {
+			handler(event)
+		}
+		select {
+		case m.out <- event:
+		case <-m.done:
+			return
+		default:
+		}
+	}
+}
+
+// Close stops the monitor and closes its channel.
+func (m *Monitor) Close() {
+	m.once.Do(func() {
+		close(m.done)
+		syscall.Write(m.wake[1], []byte{0})
+		for range m.out {
+			// wait for the loop to stop
+		}
+		m.release()
+	})
+}
+
+func (m *Monitor) release() {
+	if m.epfd >= 0 {
+		syscall.Close(m.epfd)
+	}
+	for _, fd := range m.wake {
+		if fd >= 0 {
+			syscall.Close(fd)
+		}
+	}
+	if m.file != nil {
+		m.file.Close()
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !linux
+
+package sysdmempressure
+
+import (
+	"errors"
+	"fmt"
+)
+
+// StartWatch starts monitoring w, see Start.
+// PSI only exists on Linux: it fails with errors.ErrUnsupported on other platforms.
+func StartWatch(w Watch, handlers ...Handler) (*Monitor, error) {
+	return nil, fmt.Errorf("can't watch memory pressure on %s: %w", w.Path, errors.ErrUnsupported)
+}
+
+// Close stops the monitor and closes its channel.
+func (m *Monitor) Close() {}
@@ -0,0 +1,64 @@
+package sysdmempressure
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestWatchFromEnv(t *testing.T) {
+	t.Setenv("MEMORY_PRESSURE_WATCH", "/sys/fs/cgroup/system.slice/foo.service/memory.pressure")
+	t.Setenv("MEMORY_PRESSURE_WRITE", base64.StdEncoding.EncodeToString([]byte("some 200000 2000000")))
+	w, err := WatchFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Path != "/sys/fs/cgroup/system.slice/foo.service/memory.pressure" || string(w.Write) != Trigger(DefaultThreshold, DefaultWindow) {
+		t.Errorf("unexpected watch %+v", w)
+	}
+	t.Setenv("MEMORY_PRESSURE_WATCH", "/dev/null")
+	if _, err = WatchFromEnv(); !errors.Is(err, ErrDisabled) {
+		t.Errorf("expected ErrDisabled, got %v", err)
+	}
+}
+
+func TestMonitorFIFO(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pressure")
+	if err := syscall.Mkfifo(path, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	handled := make(chan Event, 1)
+	m, err := StartWatch(Watch{Path: path}, func(e Event) {
+		handled <- e
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	fifo, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fifo.Close()
+	if _, err = fifo.Write([]byte("pressure")); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-m.C:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event received")
+	}
+	select {
+	case <-handled:
+	default:
+		t.Error("handler not called")
+	}
+	m.Close()
+	if _, ok := <-m.C; ok {
+		t.Error("channel not closed")
+	}
+}
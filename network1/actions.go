@@ -0,0 +1,51 @@
+package network1
+
+import "context"
+
+// RenewLink renews the DHCP leases of the link of index ifindex.
+// ctx: Context to use
+// ifindex: interface index
+func (c *Conn) RenewLink(ctx context.Context, ifindex int) error {
+	return typedError(c.Call(ctx, "RenewLink", int32(ifindex)).Store())
+}
+
+// ForceRenewLink sends a FORCERENEW to the DHCP clients of the link of index ifindex
+// when networkd runs a DHCP server on it, so they renew their leases right away.
+// ctx: Context to use
+// ifindex: interface index
+func (c *Conn) ForceRenewLink(ctx context.Context, ifindex int) error {
+	return typedError(c.Call(ctx, "ForceRenewLink", int32(ifindex)).Store())
+}
+
+// Renew renews the DHCP leases of the link.
+// ctx: Context to use
+func (l *Link) Renew(ctx context.Context) error {
+	return l.c.RenewLink(ctx, l.Index)
+}
+
+// ForceRenew sends a FORCERENEW to the DHCP clients of the link, see Conn.ForceRenewLink.
+// ctx: Context to use
+func (l *Link) ForceRenew(ctx context.Context) error {
+	return l.c.ForceRenewLink(ctx, l.Index)
+}
+
+// ReconfigureLink reapplies the configuration files matching the link of index ifindex,
+// call Reload first when they changed on disk.
+// ctx: Context to use
+// ifindex: interface index
+func (c *Conn) ReconfigureLink(ctx context.Context, ifindex int) error {
+	return typedError(c.Call(ctx, "ReconfigureLink", int32(ifindex)).Store())
+}
+
+// Reconfigure reapplies the configuration files matching the link, see Conn.ReconfigureLink.
+// ctx: Context to use
+func (l *Link) Reconfigure(ctx context.Context) error {
+	return l.c.ReconfigureLink(ctx, l.Index)
+}
+
+// Reload reloads the .network and .netdev files (networkctl reload): new netdevs are created
+// and the links whose configuration changed are reconfigured.
+// ctx: Context to use
+func (c *Conn) Reload(ctx context.Context) error {
+	return typedError(c.Call(ctx, "Reload").Store())
+}
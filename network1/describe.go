@@ -0,0 +1,281 @@
+package network1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/netip"
+	"time"
+)
+
+// LinkDescription holds the link facts returned by Describe, fields unknown to the running
+// systemd version are left empty.
+type LinkDescription struct {
+	Index                    int
+	Name                     string
+	AlternativeNames         []string
+	Type                     string // eg: ether, loopback, wlan
+	Kind                     string // netdev kind, eg: bridge, vlan, wireguard
+	Driver                   string
+	Vendor                   string
+	Model                    string
+	Path                     string           // udev ID_PATH
+	HardwareAddress          net.HardwareAddr `json:"-"`
+	PermanentHardwareAddress net.HardwareAddr `json:"-"`
+	MTU                      uint32
+	NetworkFile              string // .network file applied to the link, empty if unmanaged
+	RequiredForOnline        bool
+	ActivationPolicy         string
+	State                    `json:"-"`
+	Addresses                []Address
+	Routes                   []Route
+	DNS                      []DNSServer
+	NTP                      []NTPServer
+	SearchDomains            []Domain
+	RouteDomains             []Domain
+	DHCPv4Client             *DHCPv4Client // nil if the DHCPv4 client is disabled
+}
+
+// UnmarshalJSON parses the JSON description of a link.
+func (d *LinkDescription) UnmarshalJSON(data []byte) error {
+	type plain LinkDescription
+	aux := struct {
+		*plain
+		HardwareAddress          []int
+		PermanentHardwareAddress []int
+		SetupState               AdministrativeState
+		OperationalState         OperationalState
+		CarrierState             CarrierState
+		AddressState             AddressState
+		IPv4AddressState         AddressState
+		IPv6AddressState         AddressState
+		OnlineState              OnlineState
+	}{plain: (*plain)(d)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	d.HardwareAddress = hardwareAddr(aux.HardwareAddress)
+	d.PermanentHardwareAddress = hardwareAddr(aux.PermanentHardwareAddress)
+	d.State = State{
+		OperationalState:    aux.OperationalState,
+		CarrierState:        aux.CarrierState,
+		AddressState:        aux.AddressState,
+		IPv4AddressState:    aux.IPv4AddressState,
+		IPv6AddressState:    aux.IPv6AddressState,
+		OnlineState:         aux.OnlineState,
+		AdministrativeState: aux.SetupState,
+	}
+	return nil
+}
+
+// Address is an address configured on a link.
+type Address struct {
+	Family         int          // AF_INET or AF_INET6
+	Address        netip.Prefix `json:"-"`
+	Peer           netip.Addr   `json:"-"` // point to point peer address, if any
+	Broadcast      netip.Addr   `json:"-"`
+	Scope          string       `json:"-"` // eg: global, link, host
+	Flags          string       `json:"-"` // eg: "permanent", "dynamic noprefixroute"
+	ConfigSource   string       // eg: static, DHCPv4, NDisc, foreign
+	ConfigState    string       // eg: configured, configuring, removing
+	ConfigProvider netip.Addr   `json:"-"` // server which provided the address (DHCP, router...)
+}
+
+// UnmarshalJSON parses the JSON description of an address.
+func (a *Address) UnmarshalJSON(data []byte) error {
+	type plain Address
+	aux := struct {
+		*plain
+		Address        []int
+		PrefixLength   int
+		Peer           []int
+		Broadcast      []int
+		ScopeString    string
+		FlagsString    string
+		ConfigProvider []int
+	}{plain: (*plain)(a)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	a.Address = netip.PrefixFrom(ipAddr(aux.Address), aux.PrefixLength)
+	a.Peer = ipAddr(aux.Peer)
+	a.Broadcast = ipAddr(aux.Broadcast)
+	a.Scope = aux.ScopeString
+	a.Flags = aux.FlagsString
+	a.ConfigProvider = ipAddr(aux.ConfigProvider)
+	return nil
+}
+
+// Route is a route configured by or known to networkd for a link.
+type Route struct {
+	Family          int          // AF_INET or AF_INET6
+	Destination     netip.Prefix `json:"-"`
+	Gateway         netip.Addr   `json:"-"` // invalid for on-link routes
+	PreferredSource netip.Addr   `json:"-"`
+	Scope           string       `json:"-"` // eg: global, link
+	Protocol        string       `json:"-"` // eg: kernel, static, dhcp, ra
+	Type            string       `json:"-"` // eg: unicast, local, blackhole
+	Priority        uint32       // route metric
+	Table           uint32
+	ConfigSource    string
+	ConfigState     string
+	ConfigProvider  netip.Addr `json:"-"`
+}
+
+// UnmarshalJSON parses the JSON description of a route.
+func (r *Route) UnmarshalJSON(data []byte) error {
+	type plain Route
+	aux := struct {
+		*plain
+		Destination             []int
+		DestinationPrefixLength int
+		Gateway                 []int
+		PreferredSource         []int
+		ScopeString             string
+		ProtocolString          string
+		TypeString              string
+		ConfigProvider          []int
+	}{plain: (*plain)(r)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	r.Destination = netip.PrefixFrom(ipAddr(aux.Destination), aux.DestinationPrefixLength)
+	r.Gateway = ipAddr(aux.Gateway)
+	r.PreferredSource = ipAddr(aux.PreferredSource)
+	r.Scope = aux.ScopeString
+	r.Protocol = aux.ProtocolString
+	r.Type = aux.TypeString
+	r.ConfigProvider = ipAddr(aux.ConfigProvider)
+	return nil
+}
+
+// DNSServer is a DNS server of a link.
+type DNSServer struct {
+	Family         int
+	Address        netip.Addr `json:"-"`
+	Port           uint16     // 0 for the default port
+	InterfaceIndex int        // for link local addresses
+	ServerName     string     // for DNS over TLS
+	ConfigSource   string
+	ConfigProvider netip.Addr `json:"-"`
+}
+
+// UnmarshalJSON parses the JSON description of a DNS server.
+func (s *DNSServer) UnmarshalJSON(data []byte) error {
+	type plain DNSServer
+	aux := struct {
+		*plain
+		Address        []int
+		ConfigProvider []int
+	}{plain: (*plain)(s)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	s.Address = ipAddr(aux.Address)
+	s.ConfigProvider = ipAddr(aux.ConfigProvider)
+	return nil
+}
+
+// NTPServer is a NTP server of a link, given either by address or by name.
+type NTPServer struct {
+	Family         int
+	Address        netip.Addr `json:"-"`
+	Server         string     // server name, empty if given by address
+	ConfigSource   string
+	ConfigProvider netip.Addr `json:"-"`
+}
+
+// UnmarshalJSON parses the JSON description of a NTP server.
+func (s *NTPServer) UnmarshalJSON(data []byte) error {
+	type plain NTPServer
+	aux := struct {
+		*plain
+		Address        []int
+		ConfigProvider []int
+	}{plain: (*plain)(s)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	s.Address = ipAddr(aux.Address)
+	s.ConfigProvider = ipAddr(aux.ConfigProvider)
+	return nil
+}
+
+// Domain is a search or route only domain of a link.
+type Domain struct {
+	Domain         string
+	ConfigSource   string
+	ConfigProvider netip.Addr `json:"-"`
+}
+
+// UnmarshalJSON parses the JSON description of a domain.
+func (d *Domain) UnmarshalJSON(data []byte) error {
+	type plain Domain
+	aux := struct {
+		*plain
+		ConfigProvider []int
+	}{plain: (*plain)(d)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	d.ConfigProvider = ipAddr(aux.ConfigProvider)
+	return nil
+}
+
+// Describe returns every fact known by networkd about the link (systemd v250 or later).
+// ctx: Context to use
+func (l *Link) Describe(ctx context.Context) (*LinkDescription, error) {
+	var payload string
+	if err := l.obj.CallWithContext(ctx, linkInterface+".Describe", l.c.flags).Store(&payload); err != nil {
+		return nil, typedError(err)
+	}
+	d := &LinkDescription{}
+	if err := json.Unmarshal([]byte(payload), d); err != nil {
+		return nil, fmt.Errorf("failed to parse link description: %w", err)
+	}
+	return d, nil
+}
+
+// DescribeLinks returns every fact known by networkd about every link.
+// ctx: Context to use
+func (v *Varlink) DescribeLinks(ctx context.Context) ([]LinkDescription, error) {
+	var reply struct {
+		Interfaces []LinkDescription
+	}
+	if err := v.Call(ctx, "Describe", nil, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Interfaces, nil
+}
+
+// ipAddr converts the JSON byte array representation of an IP address, invalid if empty.
+func ipAddr(b []int) netip.Addr {
+	addr, _ := netip.AddrFromSlice(byteSlice(b))
+	return addr
+}
+
+// hardwareAddr converts the JSON byte array representation of a hardware address, nil if empty.
+func hardwareAddr(b []int) net.HardwareAddr {
+	return net.HardwareAddr(byteSlice(b))
+}
+
+// byteSlice converts a JSON byte array, nil if empty.
+func byteSlice(b []int) []byte {
+	if len(b) == 0 {
+		return nil
+	}
+	s := make([]byte, len(b))
+	for i, v := range b {
+		s[i] = byte(v)
+	}
+	return s
+}
+
+// usecTime returns the µs since epoch usec as a time.Time, zero if unset.
+func usecTime(usec uint64) time.Time {
+	if usec == 0 || usec == ^uint64(0) {
+		return time.Time{}
+	}
+	return time.UnixMicro(int64(usec))
+}
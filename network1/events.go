@@ -0,0 +1,321 @@
+package network1
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+const propertiesInterface = "org.freedesktop.DBus.Properties"
+
+// LinkEventType is the type of a LinkEvent.
+type LinkEventType int
+
+const (
+	// LinkStateChanged is sent for every state change of a link, before the more specific events.
+	LinkStateChanged LinkEventType = iota
+	CarrierGained
+	CarrierLost
+	BecameRoutable
+	LostRoutable
+	BecameOnline
+	BecameOffline
+	AddressAdded
+	AddressRemoved
+)
+
+func (t LinkEventType) String() string {
+	switch t {
+	case LinkStateChanged:
+		return "LinkStateChanged"
+	case CarrierGained:
+		return "CarrierGained"
+	case CarrierLost:
+		return "CarrierLost"
+	case BecameRoutable:
+		return "BecameRoutable"
+	case LostRoutable:
+		return "LostRoutable"
+	case BecameOnline:
+		return "BecameOnline"
+	case BecameOffline:
+		return "BecameOffline"
+	case AddressAdded:
+		return "AddressAdded"
+	case AddressRemoved:
+		return "AddressRemoved"
+	default:
+		return "LinkEventType(" + strconv.Itoa(int(t)) + ")"
+	}
+}
+
+// LinkEvent is a change of a link received thru a LinkSubscription.
+type LinkEvent struct {
+	Type     LinkEventType
+	Index    int          // interface index
+	Name     string       // interface name
+	State    State        // states after the change
+	Previous State        // states before the change
+	Address  netip.Prefix // AddressAdded and AddressRemoved only
+}
+
+// LinkSubscription delivers link events, see Conn.SubscribeLinks.
+type LinkSubscription struct {
+	// C receives the events, it is closed by Close.
+	C <-chan LinkEvent
+
+	c     *Conn
+	out   chan LinkEvent
+	sigs  chan *dbus.Signal
+	match []dbus.MatchOption
+	links map[dbus.ObjectPath]*trackedLink
+	done  chan struct{}
+	once  sync.Once
+	wg    sync.WaitGroup
+}
+
+type trackedLink struct {
+	index     int
+	name      string
+	props     map[string]dbus.Variant
+	addresses map[netip.Prefix]struct{} // nil if Describe is not supported
+}
+
+// describeTimeout bounds the Describe calls made to detect address changes.
+const describeTimeout = 5 * time.Second
+
+// SubscribeLinks returns a new subscription to the links state changes, so services can react to
+// network changes (eg: re-resolve endpoints or re-bind sockets) without polling. Address changes are
+// detected when networkd signals a state change of the link and Describe is supported (systemd v250 or later).
+// Close must be called once done with it.
+// ctx: Context to use for the initial snapshot of the links
+func (c *Conn) SubscribeLinks(ctx context.Context) (*LinkSubscription, error) {
+	s := &LinkSubscription{
+		c:    c,
+		out:  make(chan LinkEvent, 16),
+		sigs: make(chan *dbus.Signal, 16),
+		match: []dbus.MatchOption{
+			dbus.WithMatchPathNamespace(dbusPath + "/link"),
+			dbus.WithMatchInterface(propertiesInterface),
+			dbus.WithMatchMember("PropertiesChanged"),
+			dbus.WithMatchArg(0, linkInterface),
+		},
+		links: make(map[dbus.ObjectPath]*trackedLink),
+		done:  make(chan struct{}),
+	}
+	s.C = s.out
+	if err := c.conn.AddMatchSignal(s.match...); err != nil {
+		return nil, fmt.Errorf("failed to add properties signals match: %w", err)
+	}
+	c.conn.Signal(s.sigs)
+	links, err := c.ListLinks(ctx)
+	if err != nil {
+		s.remove()
+		return nil, err
+	}
+	for _, l := range links {
+		if _, err = s.track(ctx, l.Path, l.Index, l.Name); err != nil {
+			s.remove()
+			return nil, err
+		}
+	}
+	s.wg.Add(1)
+	go s.run()
+	return s, nil
+}
+
+// Close stops the subscription and closes its channel.
+func (s *LinkSubscription) Close() {
+	s.once.Do(func() {
+		close(s.done)
+		s.remove()
+		s.wg.Wait()
+		close(s.out)
+	})
+}
+
+func (s *LinkSubscription) remove() {
+	s.c.conn.RemoveSignal(s.sigs)
+	s.c.conn.RemoveMatchSignal(s.match...)
+}
+
+// track reads and records the current state of the link at path.
+func (s *LinkSubscription) track(ctx context.Context, path dbus.ObjectPath, index int, name string) (*trackedLink, error) {
+	l := s.c.link(index, name, path)
+	props, err := sysdbus.GetAllProperties(ctx, l.obj, linkInterface)
+	if err != nil {
+		return nil, typedError(err)
+	}
+	t := &trackedLink{
+		index: index,
+		name:  name,
+		props: props,
+	}
+	if d, err := l.Describe(ctx); err == nil {
+		t.addresses = addressSet(d.Addresses)
+	}
+	s.links[path] = t
+	return t, nil
+}
+
+func (s *LinkSubscription) run() {
+	defer s.wg.Done()
+	for {
+		select {
+		case sig := <-s.sigs:
+			if sig.Name != propertiesInterface+".PropertiesChanged" || !strings.HasPrefix(string(sig.Path), dbusPath+"/link/") {
+				continue
+			}
+			var (
+				iface       string
+				changed     map[string]dbus.Variant
+				invalidated []string
+			)
+			if dbus.Store(sig.Body, &iface, &changed, &invalidated) != nil || iface != linkInterface {
+				continue
+			}
+			for _, e := range s.changed(sig.Path, changed) {
+				select {
+				case s.out <- e:
+				case <-s.done:
+					return
+				}
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// changed updates the link at path with the changed properties and returns the resulting events.
+func (s *LinkSubscription) changed(path dbus.ObjectPath, changed map[string]dbus.Variant) []LinkEvent {
+	ctx, cancel := context.WithTimeout(context.Background(), describeTimeout)
+	defer cancel()
+	t, ok := s.links[path]
+	if !ok {
+		// new link: look it up, its whole state is reported as changed
+		links, err := s.c.ListLinks(ctx)
+		if err != nil {
+			return nil
+		}
+		listed := make(map[dbus.ObjectPath]struct{}, len(links))
+		for _, l := range links {
+			listed[l.Path] = struct{}{}
+			if l.Path == path {
+				t = &trackedLink{index: l.Index, name: l.Name, props: map[string]dbus.Variant{}}
+				s.links[path] = t
+			}
+		}
+		// forget the links removed without the linger state being seen
+		for p := range s.links {
+			if _, ok := listed[p]; !ok {
+				delete(s.links, p)
+			}
+		}
+		if t == nil {
+			return nil
+		}
+	}
+	previous := *state(t.props)
+	for k, v := range changed {
+		t.props[k] = v
+	}
+	current := *state(t.props)
+	events := linkEvents(t.index, t.name, previous, current)
+	if len(events) == 0 {
+		return nil
+	}
+	if current.AdministrativeState == AdminLinger {
+		// the link has been removed, its object goes away next
+		delete(s.links, path)
+		return events
+	}
+	if d, err := s.c.link(t.index, t.name, path).Describe(ctx); err == nil {
+		addresses := addressSet(d.Addresses)
+		if t.addresses != nil {
+			events = append(events, addressEvents(events[0], t.addresses, addresses)...)
+		}
+		t.addresses = addresses
+	}
+	return events
+}
+
+// linkEvents returns the events of a link going from the previous to the current states.
+func linkEvents(index int, name string, previous, current State) []LinkEvent {
+	if previous == current {
+		return nil
+	}
+	base := LinkEvent{
+		Index:    index,
+		Name:     name,
+		State:    current,
+		Previous: previous,
+	}
+	events := []LinkEvent{base}
+	add := func(t LinkEventType) {
+		e := base
+		e.Type = t
+		events = append(events, e)
+	}
+	hadCarrier, hasCarrier := carrier(previous.CarrierState), carrier(current.CarrierState)
+	switch {
+	case !hadCarrier && hasCarrier:
+		add(CarrierGained)
+	case hadCarrier && !hasCarrier:
+		add(CarrierLost)
+	}
+	wasRoutable, isRoutable := previous.OperationalState == OperationalRoutable, current.OperationalState == OperationalRoutable
+	switch {
+	case !wasRoutable && isRoutable:
+		add(BecameRoutable)
+	case wasRoutable && !isRoutable:
+		add(LostRoutable)
+	}
+	wasOnline, isOnline := previous.OnlineState == OnlineOnline, current.OnlineState == OnlineOnline
+	switch {
+	case !wasOnline && isOnline:
+		add(BecameOnline)
+	case wasOnline && !isOnline:
+		add(BecameOffline)
+	}
+	return events
+}
+
+func carrier(s CarrierState) bool {
+	return s == CarrierDegradedCarrier || s == CarrierCarrier || s == CarrierEnslaved
+}
+
+func addressSet(addresses []Address) map[netip.Prefix]struct{} {
+	set := make(map[netip.Prefix]struct{}, len(addresses))
+	for _, a := range addresses {
+		set[a.Address] = struct{}{}
+	}
+	return set
+}
+
+// addressEvents returns the AddressAdded and AddressRemoved events between the previous and current addresses.
+func addressEvents(base LinkEvent, previous, current map[netip.Prefix]struct{}) []LinkEvent {
+	var events []LinkEvent
+	for a := range current {
+		if _, ok := previous[a]; !ok {
+			e := base
+			e.Type, e.Address = AddressAdded, a
+			events = append(events, e)
+		}
+	}
+	for a := range previous {
+		if _, ok := current[a]; !ok {
+			e := base
+			e.Type, e.Address = AddressRemoved, a
+			events = append(events, e)
+		}
+	}
+	return events
+}
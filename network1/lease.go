@@ -0,0 +1,215 @@
+package network1
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// leasesDir holds the DHCPv4 leases serialized by networkd, one file per interface index.
+const leasesDir = "/run/systemd/netif/leases"
+
+// DHCPLease is the DHCPv4 lease acquired by networkd for a link.
+type DHCPLease struct {
+	Address        netip.Prefix // assigned address and netmask
+	Broadcast      netip.Addr
+	Routers        []netip.Addr // gateways
+	ServerAddress  netip.Addr   // server identifier (option 54)
+	NextServer     netip.Addr
+	DNS            []netip.Addr
+	NTP            []netip.Addr
+	SIP            []netip.Addr
+	DomainName     string
+	SearchDomains  []string
+	Hostname       string
+	Timezone       string
+	CaptivePortal  string // RFC 8910 captive portal API URI
+	MTU            int
+	Lifetime       time.Duration
+	T1             time.Duration // renewal time
+	T2             time.Duration // rebinding time
+	ClientID       []byte
+	VendorSpecific []byte            // option 43
+	PrivateOptions map[int][]byte    // options 224 to 254
+	Timestamp      time.Time         // when the lease was acquired, only known from Describe
+	Extra          map[string]string // lease fields not parsed above
+}
+
+// LeaseTimes are the DHCPv4 lease timestamps reported by Describe.
+type LeaseTimes struct {
+	Acquired time.Time
+	Renew    time.Time // T1 expiry
+	Rebind   time.Time // T2 expiry
+}
+
+// DHCPv4Client holds the DHCPv4 client facts of a link description.
+type DHCPv4Client struct {
+	Lease            *LeaseTimes `json:"-"` // nil without lease
+	ClientIdentifier []byte      `json:"-"`
+}
+
+// UnmarshalJSON parses the JSON description of a DHCPv4 client.
+func (c *DHCPv4Client) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Lease *struct {
+			LeaseTimestampUSec uint64
+			Timeout1USec       uint64
+			Timeout2USec       uint64
+		}
+		ClientIdentifier []int
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if aux.Lease != nil {
+		c.Lease = &LeaseTimes{
+			Acquired: usecTime(aux.Lease.LeaseTimestampUSec),
+			Renew:    usecTime(aux.Lease.Timeout1USec),
+			Rebind:   usecTime(aux.Lease.Timeout2USec),
+		}
+	}
+	if len(aux.ClientIdentifier) > 0 {
+		c.ClientIdentifier = byteSlice(aux.ClientIdentifier)
+	}
+	return nil
+}
+
+// ReadDHCPLease returns the DHCPv4 lease of the link of index ifindex, or an error satisfying
+// errors.Is(err, fs.ErrNotExist) if it has none. The lease is read from the state networkd shares
+// with sd-network: no connection is needed but, its format being private, fields may be missing
+// on some systemd versions (unknown ones are kept in Extra).
+// ifindex: interface index
+func ReadDHCPLease(ifindex int) (*DHCPLease, error) {
+	f, err := os.Open(fmt.Sprintf("%s/%d", leasesDir, ifindex))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	fields := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		if k, v, ok := strings.Cut(line, "="); ok {
+			fields[k] = v
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read lease: %w", err)
+	}
+	return parseLease(fields), nil
+}
+
+// DHCPLease returns the DHCPv4 lease of the link, with its acquisition time when networkd
+// describes it, see ReadDHCPLease.
+// ctx: Context to use
+func (l *Link) DHCPLease(ctx context.Context) (*DHCPLease, error) {
+	lease, err := ReadDHCPLease(l.Index)
+	if err != nil {
+		return nil, err
+	}
+	if d, err := l.Describe(ctx); err == nil && d.DHCPv4Client != nil && d.DHCPv4Client.Lease != nil {
+		lease.Timestamp = d.DHCPv4Client.Lease.Acquired
+	}
+	return lease, nil
+}
+
+func parseLease(fields map[string]string) *DHCPLease {
+	lease := &DHCPLease{
+		Extra: make(map[string]string),
+	}
+	seconds := func(v string) time.Duration {
+		s, _ := strconv.ParseUint(v, 10, 32)
+		return time.Duration(s) * time.Second
+	}
+	var address, netmask netip.Addr
+	for k, v := range fields {
+		switch k {
+		case "ADDRESS":
+			address, _ = netip.ParseAddr(v)
+		case "NETMASK":
+			netmask, _ = netip.ParseAddr(v)
+		case "BROADCAST":
+			lease.Broadcast, _ = netip.ParseAddr(v)
+		case "ROUTER":
+			lease.Routers = addrList(v)
+		case "SERVER_ADDRESS":
+			lease.ServerAddress, _ = netip.ParseAddr(v)
+		case "NEXT_SERVER":
+			lease.NextServer, _ = netip.ParseAddr(v)
+		case "DNS":
+			lease.DNS = addrList(v)
+		case "NTP":
+			lease.NTP = addrList(v)
+		case "SIP":
+			lease.SIP = addrList(v)
+		case "DOMAINNAME":
+			lease.DomainName = v
+		case "DOMAIN_SEARCH_LIST":
+			lease.SearchDomains = strings.Fields(v)
+		case "HOSTNAME":
+			lease.Hostname = v
+		case "TIMEZONE":
+			lease.Timezone = v
+		case "CAPTIVE_PORTAL":
+			lease.CaptivePortal = v
+		case "MTU":
+			lease.MTU, _ = strconv.Atoi(v)
+		case "LIFETIME":
+			lease.Lifetime = seconds(v)
+		case "T1":
+			lease.T1 = seconds(v)
+		case "T2":
+			lease.T2 = seconds(v)
+		case "CLIENTID":
+			lease.ClientID, _ = hex.DecodeString(v)
+		case "VENDOR_SPECIFIC":
+			lease.VendorSpecific, _ = hex.DecodeString(v)
+		default:
+			if code, ok := strings.CutPrefix(k, "OPTION_"); ok {
+				if n, err := strconv.Atoi(code); err == nil {
+					if lease.PrivateOptions == nil {
+						lease.PrivateOptions = make(map[int][]byte)
+					}
+					lease.PrivateOptions[n], _ = hex.DecodeString(v)
+					continue
+				}
+			}
+			lease.Extra[k] = v
+		}
+	}
+	if address.IsValid() {
+		bits := 32
+		if netmask.Is4() {
+			mask := netmask.As4()
+			bits = 0
+			for _, b := range mask {
+				for ; b&0x80 != 0; b <<= 1 {
+					bits++
+				}
+			}
+		}
+		lease.Address = netip.PrefixFrom(address, bits)
+	}
+	return lease
+}
+
+// addrList parses a space separated list of addresses, skipping the invalid ones.
+func addrList(v string) []netip.Addr {
+	var list []netip.Addr
+	for _, s := range strings.Fields(v) {
+		if addr, err := netip.ParseAddr(s); err == nil {
+			list = append(list, addr)
+		}
+	}
+	return list
+}
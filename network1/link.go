@@ -0,0 +1,170 @@
+package network1
+
+import (
+	"context"
+	"errors"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+const linkInterface = "org.freedesktop.network1.Link"
+
+// LinkStatus represents a link as returned by ListLinks.
+type LinkStatus struct {
+	Index int             // interface index
+	Name  string          // interface name
+	Path  dbus.ObjectPath // link object path
+}
+
+// linkStatus is the dbus (iso) representation of LinkStatus.
+type linkStatus struct {
+	Index int32
+	Name  string
+	Path  dbus.ObjectPath
+}
+
+// ListLinks returns the links known by networkd.
+// ctx: Context to use
+func (c *Conn) ListLinks(ctx context.Context) ([]LinkStatus, error) {
+	var raw []linkStatus
+	if err := c.Call(ctx, "ListLinks").Store(&raw); err != nil {
+		return nil, err
+	}
+	links := make([]LinkStatus, len(raw))
+	for i, l := range raw {
+		links[i] = LinkStatus{
+			Index: int(l.Index),
+			Name:  l.Name,
+			Path:  l.Path,
+		}
+	}
+	return links, nil
+}
+
+// State returns the overall states of the system, aggregated from the links.
+// ctx: Context to use
+func (c *Conn) State(ctx context.Context) (*State, error) {
+	props, err := sysdbus.GetAllProperties(ctx, c.obj, dbusInterface)
+	if err != nil {
+		return nil, err
+	}
+	return state(props), nil
+}
+
+// Link represents a link object of networkd.
+type Link struct {
+	Index int             // interface index
+	Name  string          // interface name
+	Path  dbus.ObjectPath // link object path
+	c     *Conn
+	obj   dbus.BusObject
+}
+
+// Link returns the link named name (eg: eth0).
+// ctx: Context to use
+// name: interface name
+func (c *Conn) Link(ctx context.Context, name string) (*Link, error) {
+	var (
+		index int32
+		path  dbus.ObjectPath
+	)
+	if err := c.Call(ctx, "GetLinkByName", name).Store(&index, &path); err != nil {
+		return nil, typedError(err)
+	}
+	return c.link(int(index), name, path), nil
+}
+
+// LinkByIndex returns the link of index ifindex.
+// ctx: Context to use
+// ifindex: interface index
+func (c *Conn) LinkByIndex(ctx context.Context, ifindex int) (*Link, error) {
+	var (
+		name string
+		path dbus.ObjectPath
+	)
+	if err := c.Call(ctx, "GetLinkByIndex", int32(ifindex)).Store(&name, &path); err != nil {
+		return nil, typedError(err)
+	}
+	return c.link(ifindex, name, path), nil
+}
+
+func (c *Conn) link(index int, name string, path dbus.ObjectPath) *Link {
+	return &Link{
+		Index: index,
+		Name:  name,
+		Path:  path,
+		c:     c,
+		obj:   c.conn.Object(dbusDest, path),
+	}
+}
+
+// Property returns the raw value of a link property.
+func (l *Link) Property(ctx context.Context, name string) (dbus.Variant, error) {
+	return sysdbus.GetProperty(ctx, l.obj, linkInterface, name)
+}
+
+// State returns the states of the link.
+// ctx: Context to use
+func (l *Link) State(ctx context.Context) (*State, error) {
+	props, err := sysdbus.GetAllProperties(ctx, l.obj, linkInterface)
+	if err != nil {
+		return nil, typedError(err)
+	}
+	return state(props), nil
+}
+
+// LinkState is a link along with its states, see Links.
+type LinkState struct {
+	LinkStatus
+	State
+}
+
+// Links returns every link with its states, like "networkctl list" does.
+// Links removed while their states are read are skipped.
+// ctx: Context to use
+func (c *Conn) Links(ctx context.Context) ([]LinkState, error) {
+	links, err := c.ListLinks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	states := make([]LinkState, 0, len(links))
+	for _, l := range links {
+		props, err := sysdbus.GetAllProperties(ctx, c.conn.Object(dbusDest, l.Path), linkInterface)
+		if linkVanished(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, LinkState{
+			LinkStatus: l,
+			State:      *state(props),
+		})
+	}
+	return states, nil
+}
+
+// linkVanished tells if err has been returned for a link removed since it was listed.
+func linkVanished(err error) bool {
+	var dbusErr dbus.Error
+	if errors.As(err, &dbusErr) {
+		switch dbusErr.Name {
+		case "org.freedesktop.DBus.Error.UnknownObject", "org.freedesktop.DBus.Error.UnknownInterface":
+			return true
+		}
+	}
+	return errors.Is(typedError(err), ErrNoSuchLink)
+}
+
+func state(props map[string]dbus.Variant) *State {
+	return &State{
+		OperationalState:    OperationalState(sysdbus.Prop[string](props, "OperationalState")),
+		CarrierState:        CarrierState(sysdbus.Prop[string](props, "CarrierState")),
+		AddressState:        AddressState(sysdbus.Prop[string](props, "AddressState")),
+		IPv4AddressState:    AddressState(sysdbus.Prop[string](props, "IPv4AddressState")),
+		IPv6AddressState:    AddressState(sysdbus.Prop[string](props, "IPv6AddressState")),
+		OnlineState:         OnlineState(sysdbus.Prop[string](props, "OnlineState")),
+		AdministrativeState: AdministrativeState(sysdbus.Prop[string](props, "AdministrativeState")),
+	}
+}
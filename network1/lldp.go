@@ -0,0 +1,98 @@
+package network1
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// LLDPCapabilities is a bitmask of LLDP system capabilities.
+type LLDPCapabilities uint16
+
+const (
+	LLDPCapOther LLDPCapabilities = 1 << iota
+	LLDPCapRepeater
+	LLDPCapBridge
+	LLDPCapWLANAccessPoint
+	LLDPCapRouter
+	LLDPCapTelephone
+	LLDPCapDOCSIS
+	LLDPCapStation
+	LLDPCapCVLAN
+	LLDPCapSVLAN
+	LLDPCapTPMR
+)
+
+// Has tells if every capability of caps is set.
+func (c LLDPCapabilities) Has(caps LLDPCapabilities) bool {
+	return c&caps == caps
+}
+
+// String formats the capabilities like networkctl lldp does (eg: "..b.r......" for a bridge and router).
+func (c LLDPCapabilities) String() string {
+	const letters = "opbwrtdacsm"
+	var b strings.Builder
+	for i := range letters {
+		if c&(1<<i) != 0 {
+			b.WriteByte(letters[i])
+		} else {
+			b.WriteByte('.')
+		}
+	}
+	return b.String()
+}
+
+// LLDPNeighbor is a neighbor seen on a link thru LLDP, typically the switch port it is plugged on.
+type LLDPNeighbor struct {
+	ChassisID           string
+	RawChassisID        []byte `json:"-"`
+	PortID              string
+	RawPortID           []byte `json:"-"`
+	PortDescription     string
+	SystemName          string
+	SystemDescription   string
+	EnabledCapabilities LLDPCapabilities
+	MUDURL              string
+	VLANID              uint16 `json:"VlanID"`
+}
+
+// UnmarshalJSON parses the JSON description of a LLDP neighbor.
+func (n *LLDPNeighbor) UnmarshalJSON(data []byte) error {
+	type plain LLDPNeighbor
+	aux := struct {
+		*plain
+		RawChassisID []int
+		RawPortID    []int
+	}{plain: (*plain)(n)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	n.RawChassisID = byteSlice(aux.RawChassisID)
+	n.RawPortID = byteSlice(aux.RawPortID)
+	return nil
+}
+
+// LinkNeighbors are the LLDP neighbors of a link.
+type LinkNeighbors struct {
+	Index     int    `json:"InterfaceIndex"`
+	Name      string `json:"InterfaceName"`
+	Neighbors []LLDPNeighbor
+}
+
+// LLDPNeighbors returns the LLDP neighbors of the link named ifname, or of every link if empty
+// (systemd v256 or later). LLDP must be enabled on the links (LLDP= of .network files, on by default).
+// ctx: Context to use
+// ifname: interface name, empty for every link
+func (v *Varlink) LLDPNeighbors(ctx context.Context, ifname string) ([]LinkNeighbors, error) {
+	var params interface{}
+	if ifname != "" {
+		params = map[string]string{"InterfaceName": ifname}
+	}
+	var reply struct {
+		Neighbors []LinkNeighbors
+	}
+	if err := v.Call(ctx, "GetLLDPNeighbors", params, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Neighbors, nil
+}
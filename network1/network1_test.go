@@ -0,0 +1,220 @@
+package network1
+
+import (
+	"context"
+	"encoding/json"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestState(t *testing.T) {
+	s := state(map[string]dbus.Variant{
+		"OperationalState":    dbus.MakeVariant("routable"),
+		"CarrierState":        dbus.MakeVariant("carrier"),
+		"AddressState":        dbus.MakeVariant("routable"),
+		"IPv4AddressState":    dbus.MakeVariant("routable"),
+		"IPv6AddressState":    dbus.MakeVariant("degraded"),
+		"OnlineState":         dbus.MakeVariant("online"),
+		"AdministrativeState": dbus.MakeVariant("configured"),
+	})
+	want := State{
+		OperationalState:    OperationalRoutable,
+		CarrierState:        CarrierCarrier,
+		AddressState:        AddressRoutable,
+		IPv4AddressState:    AddressRoutable,
+		IPv6AddressState:    AddressDegraded,
+		OnlineState:         OnlineOnline,
+		AdministrativeState: AdminConfigured,
+	}
+	if *s != want {
+		t.Errorf("unexpected state: %+v", *s)
+	}
+}
+
+func TestLinkDescription(t *testing.T) {
+	payload := `{
+		"Index": 2, "Name": "eth0", "Type": "ether", "HardwareAddress": [82, 84, 0, 18, 52, 86], "MTU": 1500,
+		"SetupState": "configured", "OperationalState": "routable", "CarrierState": "carrier", "OnlineState": "online",
+		"Addresses": [{"Family": 2, "Address": [192, 168, 1, 10], "PrefixLength": 24, "ScopeString": "global",
+			"ConfigSource": "DHCPv4", "ConfigState": "configured", "ConfigProvider": [192, 168, 1, 1]}],
+		"Routes": [{"Family": 2, "Destination": [0, 0, 0, 0], "DestinationPrefixLength": 0, "Gateway": [192, 168, 1, 1],
+			"ProtocolString": "dhcp", "Priority": 1024, "Table": 254}],
+		"DNS": [{"Family": 2, "Address": [192, 168, 1, 1], "ConfigSource": "DHCPv4"}],
+		"NTP": [{"Server": "pool.ntp.org", "ConfigSource": "static"}],
+		"SearchDomains": [{"Domain": "lan", "ConfigSource": "DHCPv4"}]
+	}`
+	var d LinkDescription
+	if err := json.Unmarshal([]byte(payload), &d); err != nil {
+		t.Fatal(err)
+	}
+	if d.Name != "eth0" || d.HardwareAddress.String() != "52:54:00:12:34:56" || d.AdministrativeState != AdminConfigured {
+		t.Errorf("unexpected description: %+v", d)
+	}
+	if len(d.Addresses) != 1 || d.Addresses[0].Address.String() != "192.168.1.10/24" || d.Addresses[0].Scope != "global" {
+		t.Errorf("unexpected addresses: %+v", d.Addresses)
+	}
+	if len(d.Routes) != 1 || d.Routes[0].Destination.String() != "0.0.0.0/0" || d.Routes[0].Gateway.String() != "192.168.1.1" {
+		t.Errorf("unexpected routes: %+v", d.Routes)
+	}
+	if len(d.DNS) != 1 || d.DNS[0].Address.String() != "192.168.1.1" || d.NTP[0].Server != "pool.ntp.org" || d.NTP[0].Address.IsValid() {
+		t.Errorf("unexpected DNS %+v or NTP %+v", d.DNS, d.NTP)
+	}
+}
+
+func TestWaitOnline(t *testing.T) {
+	links := []LinkState{
+		{LinkStatus{Index: 1, Name: "lo"}, State{OperationalState: OperationalCarrier, AdministrativeState: AdminUnmanaged}},
+		{LinkStatus{Index: 2, Name: "eth0"}, State{OperationalState: OperationalRoutable, IPv4AddressState: AddressRoutable,
+			IPv6AddressState: AddressDegraded, OnlineState: OnlineOnline, AdministrativeState: AdminConfigured}},
+		{LinkStatus{Index: 3, Name: "eth1"}, State{OperationalState: OperationalNoCarrier, OnlineState: OnlineOffline,
+			AdministrativeState: AdminConfiguring}},
+		{LinkStatus{Index: 4, Name: "wg0"}, State{OperationalState: OperationalDegraded, IPv4AddressState: AddressDegraded,
+			AdministrativeState: AdminConfigured}},
+	}
+	isLoopback := func(index int) bool {
+		return index == 1
+	}
+	for _, tt := range []struct {
+		opts    []WaitOption
+		online  bool
+		pending string
+	}{
+		{nil, false, "eth1"},
+		{[]WaitOption{WithAny()}, true, "eth1"},
+		{[]WaitOption{WithIgnore("eth1")}, true, ""},
+		{[]WaitOption{WithInterfaces("eth0", "wg0")}, true, ""},
+		{[]WaitOption{WithInterfaces("eth0", "eth2")}, false, "eth2"},
+		{[]WaitOption{WithInterfaces("eth0"), WithIPv6()}, true, ""},
+		{[]WaitOption{WithInterfaces("eth0"), WithIPv6(), WithOperationalState(OperationalRoutable, "")}, false, "eth0"},
+		{[]WaitOption{WithInterfaces("wg0"), WithOperationalState(OperationalRoutable, "")}, false, "wg0"},
+		{[]WaitOption{WithIgnore("eth1"), WithOperationalState(OperationalDegraded, "")}, true, ""},
+	} {
+		w := &waitConfig{}
+		for _, opt := range tt.opts {
+			if err := opt(w); err != nil {
+				t.Fatal(err)
+			}
+		}
+		online, pending := w.online(links, isLoopback)
+		if online != tt.online || strings.Join(pending, ",") != tt.pending {
+			t.Errorf("%+v: unexpected online %v, pending %v", *w, online, pending)
+		}
+	}
+	for _, tt := range []struct {
+		err      error
+		vanished bool
+	}{
+		{dbus.Error{Name: "org.freedesktop.DBus.Error.UnknownObject"}, true},
+		{dbus.Error{Name: dbusDest + ".NoSuchLink"}, true},
+		{dbus.Error{Name: "org.freedesktop.DBus.Error.AccessDenied"}, false},
+		{context.Canceled, false},
+		{nil, false},
+	} {
+		if linkVanished(tt.err) != tt.vanished {
+			t.Errorf("linkVanished(%v) should be %v", tt.err, tt.vanished)
+		}
+	}
+}
+
+func TestParseLease(t *testing.T) {
+	lease := parseLease(map[string]string{
+		"ADDRESS":         "192.168.1.10",
+		"NETMASK":         "255.255.255.0",
+		"ROUTER":          "192.168.1.1 192.168.1.2",
+		"SERVER_ADDRESS":  "192.168.1.1",
+		"DNS":             "192.168.1.1 9.9.9.9",
+		"LIFETIME":        "86400",
+		"T1":              "43200",
+		"VENDOR_SPECIFIC": "0102ff",
+		"OPTION_224":      "6869",
+		"ROOT_PATH":       "/srv/root",
+	})
+	if lease.Address.String() != "192.168.1.10/24" || len(lease.Routers) != 2 || lease.ServerAddress.String() != "192.168.1.1" {
+		t.Errorf("unexpected lease: %+v", lease)
+	}
+	if len(lease.DNS) != 2 || lease.Lifetime != 24*time.Hour || lease.T1 != 12*time.Hour {
+		t.Errorf("unexpected DNS %v or lifetimes %s %s", lease.DNS, lease.Lifetime, lease.T1)
+	}
+	if string(lease.VendorSpecific) != "\x01\x02\xff" || string(lease.PrivateOptions[224]) != "hi" || lease.Extra["ROOT_PATH"] != "/srv/root" {
+		t.Errorf("unexpected options: %+v", lease)
+	}
+	var d LinkDescription
+	payload := `{"DHCPv4Client": {"Lease": {"LeaseTimestampUSec": 1700000000000000}, "ClientIdentifier": [1, 2]}}`
+	if err := json.Unmarshal([]byte(payload), &d); err != nil {
+		t.Fatal(err)
+	}
+	if d.DHCPv4Client == nil || d.DHCPv4Client.Lease == nil || d.DHCPv4Client.Lease.Acquired.Unix() != 1700000000 {
+		t.Errorf("unexpected DHCPv4 client: %+v", d.DHCPv4Client)
+	}
+}
+
+func TestLLDPNeighbors(t *testing.T) {
+	payload := `{"Neighbors": [{"InterfaceIndex": 2, "InterfaceName": "eth0", "Neighbors": [{
+		"ChassisID": "00:11:22:33:44:55", "RawChassisID": [4, 0, 17, 34, 51, 68, 85], "PortID": "Gi1/0/12",
+		"SystemName": "sw-rack2", "EnabledCapabilities": 20, "VlanID": 42}]}]}`
+	var reply struct {
+		Neighbors []LinkNeighbors
+	}
+	if err := json.Unmarshal([]byte(payload), &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.Neighbors) != 1 || reply.Neighbors[0].Name != "eth0" || len(reply.Neighbors[0].Neighbors) != 1 {
+		t.Fatalf("unexpected neighbors: %+v", reply.Neighbors)
+	}
+	n := reply.Neighbors[0].Neighbors[0]
+	if n.SystemName != "sw-rack2" || n.VLANID != 42 || len(n.RawChassisID) != 7 || !n.EnabledCapabilities.Has(LLDPCapBridge|LLDPCapRouter) {
+		t.Errorf("unexpected neighbor: %+v", n)
+	}
+	if s := n.EnabledCapabilities.String(); s != "..b.r......" {
+		t.Errorf("unexpected capabilities %q", s)
+	}
+}
+
+func TestLinkEvents(t *testing.T) {
+	previous := State{OperationalState: OperationalNoCarrier, CarrierState: CarrierNoCarrier, OnlineState: OnlineOffline}
+	current := State{OperationalState: OperationalRoutable, CarrierState: CarrierCarrier, OnlineState: OnlineOnline}
+	var types []string
+	for _, e := range linkEvents(2, "eth0", previous, current) {
+		if e.Name != "eth0" || e.State != current || e.Previous != previous {
+			t.Errorf("unexpected event: %+v", e)
+		}
+		types = append(types, e.Type.String())
+	}
+	if s := strings.Join(types, ","); s != "LinkStateChanged,CarrierGained,BecameRoutable,BecameOnline" {
+		t.Errorf("unexpected events %s", s)
+	}
+	if events := linkEvents(2, "eth0", current, current); events != nil {
+		t.Errorf("unexpected events without change: %+v", events)
+	}
+	a, b := netip.MustParsePrefix("192.0.2.10/24"), netip.MustParsePrefix("2001:db8::10/64")
+	events := addressEvents(LinkEvent{}, addressSet([]Address{{Address: a}}), addressSet([]Address{{Address: b}}))
+	if len(events) != 2 || events[0].Type != AddressAdded || events[0].Address != b || events[1].Type != AddressRemoved || events[1].Address != a {
+		t.Errorf("unexpected address events: %+v", events)
+	}
+}
+
+func TestLinkRemoved(t *testing.T) {
+	path := dbus.ObjectPath(dbusPath + "/link/_32")
+	s := &LinkSubscription{
+		links: map[dbus.ObjectPath]*trackedLink{
+			path: {index: 2, name: "eth0", props: map[string]dbus.Variant{
+				"AdministrativeState": dbus.MakeVariant("configured"),
+				"OperationalState":    dbus.MakeVariant("routable"),
+			}},
+		},
+	}
+	events := s.changed(path, map[string]dbus.Variant{
+		"AdministrativeState": dbus.MakeVariant("linger"),
+		"OperationalState":    dbus.MakeVariant("off"),
+	})
+	if len(events) == 0 || events[len(events)-1].Type != LostRoutable {
+		t.Errorf("unexpected events: %+v", events)
+	}
+	if len(s.links) != 0 {
+		t.Errorf("removed link still tracked: %+v", s.links)
+	}
+}
@@ -0,0 +1,202 @@
+package network1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+)
+
+// OnlinePollInterval is the interval at which WaitOnline checks the links states.
+const OnlinePollInterval = 500 * time.Millisecond
+
+type waitConfig struct {
+	interfaces map[string]struct{}
+	ignore     map[string]struct{}
+	any        bool
+	min, max   OperationalState
+	ipv4, ipv6 bool
+}
+
+// WaitOption configures WaitOnline.
+type WaitOption func(w *waitConfig) error
+
+// WithInterfaces only waits for the given interfaces (--interface), which are then awaited
+// even if not required for online or not managed by networkd.
+func WithInterfaces(names ...string) WaitOption {
+	return func(w *waitConfig) error {
+		if len(names) == 0 {
+			return errors.New("no interface names")
+		}
+		w.interfaces = make(map[string]struct{}, len(names))
+		for _, name := range names {
+			w.interfaces[name] = struct{}{}
+		}
+		return nil
+	}
+}
+
+// WithIgnore never waits for the given interfaces (--ignore).
+func WithIgnore(names ...string) WaitOption {
+	return func(w *waitConfig) error {
+		w.ignore = make(map[string]struct{}, len(names))
+		for _, name := range names {
+			w.ignore[name] = struct{}{}
+		}
+		return nil
+	}
+}
+
+// WithAny returns as soon as one of the awaited links is online (--any).
+func WithAny() WaitOption {
+	return func(w *waitConfig) error {
+		w.any = true
+		return nil
+	}
+}
+
+// WithOperationalState requires the operational state of the awaited links to be between min and max
+// (--operational-state=min:max), max being routable if empty. By default the online state computed
+// by networkd from the RequiredForOnline= settings is used, and degraded for the links without it.
+func WithOperationalState(min, max OperationalState) WaitOption {
+	return func(w *waitConfig) error {
+		if max == "" {
+			max = OperationalRoutable
+		}
+		if operationalRank(min) < 0 || operationalRank(max) < 0 || !max.AtLeast(min) {
+			return fmt.Errorf("invalid operational state range %s:%s", min, max)
+		}
+		w.min, w.max = min, max
+		return nil
+	}
+}
+
+// WithIPv4 requires the awaited links to have an IPv4 address (--ipv4), a routable one
+// if the required operational state is routable.
+func WithIPv4() WaitOption {
+	return func(w *waitConfig) error {
+		w.ipv4 = true
+		return nil
+	}
+}
+
+// WithIPv6 requires the awaited links to have an IPv6 address (--ipv6), a routable one
+// if the required operational state is routable.
+func WithIPv6() WaitOption {
+	return func(w *waitConfig) error {
+		w.ipv6 = true
+		return nil
+	}
+}
+
+// WaitOnline blocks until the network is online, like systemd-networkd-wait-online does: by default
+// every link managed by networkd and required for online must be online, and at least one such link must exist.
+// On timeout the context error is returned, wrapped with the names of the links which are not online yet.
+// Services needing connectivity should rather be ordered after network-online.target when they can.
+// ctx: Context to use
+// opts: links and states to wait for
+func (c *Conn) WaitOnline(ctx context.Context, opts ...WaitOption) error {
+	w := &waitConfig{}
+	for _, opt := range opts {
+		if err := opt(w); err != nil {
+			return err
+		}
+	}
+	ticker := time.NewTicker(OnlinePollInterval)
+	defer ticker.Stop()
+	for {
+		links, err := c.Links(ctx)
+		if err != nil {
+			return err
+		}
+		online, pending := w.online(links, loopback)
+		if online {
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return fmt.Errorf("%w: waiting for %s", ctx.Err(), strings.Join(pending, ", "))
+		}
+	}
+}
+
+// online tells if the links match w, and returns the names of the awaited links which are not online.
+func (w *waitConfig) online(links []LinkState, isLoopback func(index int) bool) (bool, []string) {
+	var (
+		ready   int
+		pending []string
+		seen    = make(map[string]struct{}, len(links))
+	)
+	for _, l := range links {
+		seen[l.Name] = struct{}{}
+		if !w.awaited(l, isLoopback) {
+			continue
+		}
+		if w.ready(l) {
+			ready++
+		} else {
+			pending = append(pending, l.Name)
+		}
+	}
+	for name := range w.interfaces {
+		if _, ok := seen[name]; !ok {
+			pending = append(pending, name)
+		}
+	}
+	sort.Strings(pending)
+	if w.any {
+		return ready > 0, pending
+	}
+	if ready == 0 && len(pending) == 0 {
+		pending = append(pending, "any link")
+	}
+	return ready > 0 && len(pending) == 0, pending
+}
+
+func (w *waitConfig) awaited(l LinkState, isLoopback func(index int) bool) bool {
+	if _, ok := w.ignore[l.Name]; ok {
+		return false
+	}
+	if w.interfaces != nil {
+		_, ok := w.interfaces[l.Name]
+		return ok
+	}
+	if isLoopback(l.Index) || l.AdministrativeState == AdminUnmanaged {
+		return false
+	}
+	// links not required for online have no online state
+	return w.min != "" || l.OnlineState != OnlineUnknown
+}
+
+func (w *waitConfig) ready(l LinkState) bool {
+	if l.AdministrativeState != AdminConfigured && l.AdministrativeState != AdminUnmanaged {
+		return false
+	}
+	min, max := w.min, w.max
+	if min == "" {
+		if l.OnlineState != OnlineUnknown && l.OnlineState != OnlineOnline {
+			return false
+		}
+		min, max = OperationalDegraded, OperationalRoutable
+		if l.OnlineState == OnlineOnline {
+			min = OperationalMissing
+		}
+	}
+	if !l.OperationalState.AtLeast(min) || !max.AtLeast(l.OperationalState) {
+		return false
+	}
+	family := AddressDegraded
+	if min == OperationalRoutable {
+		family = AddressRoutable
+	}
+	return (!w.ipv4 || l.IPv4AddressState.AtLeast(family)) && (!w.ipv6 || l.IPv6AddressState.AtLeast(family))
+}
+
+func loopback(index int) bool {
+	iface, err := net.InterfaceByIndex(index)
+	return err == nil && iface.Flags&net.FlagLoopback != 0
+}
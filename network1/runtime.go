@@ -0,0 +1,150 @@
+package network1
+
+import (
+	"context"
+	"net/netip"
+	"syscall"
+)
+
+// linkDNS is the dbus (iay) representation of a DNS server address.
+type linkDNS struct {
+	Family  int32
+	Address []byte
+}
+
+// linkDNSEx is the dbus (iayqs) representation of a DNS server.
+type linkDNSEx struct {
+	Family  int32
+	Address []byte
+	Port    uint16
+	Name    string
+}
+
+// LinkDNSServer is a DNS server to use in SetLinkDNSEx.
+type LinkDNSServer struct {
+	Address netip.Addr
+	Port    uint16 // 0 for the default port
+	Name    string // server name, used for SNI with DNS over TLS
+}
+
+// LinkDomain is a domain to use in SetLinkDomains.
+type LinkDomain struct {
+	Domain      string
+	RoutingOnly bool // only route the queries for this domain to the link, do not use it as search domain
+}
+
+func family(addr netip.Addr) int32 {
+	if addr.Is4() {
+		return syscall.AF_INET
+	}
+	return syscall.AF_INET6
+}
+
+// SetLinkDNS sets the DNS servers of the link of index ifindex, which networkd passes on to resolved,
+// replacing the ones of its configuration files and DHCP until RevertLinkDNS or the link is reconfigured.
+// ctx: Context to use
+// ifindex: interface index
+// addrs: DNS server addresses
+func (c *Conn) SetLinkDNS(ctx context.Context, ifindex int, addrs ...netip.Addr) error {
+	servers := make([]linkDNS, len(addrs))
+	for i, addr := range addrs {
+		servers[i] = linkDNS{
+			Family:  family(addr),
+			Address: addr.AsSlice(),
+		}
+	}
+	return typedError(c.Call(ctx, "SetLinkDNS", int32(ifindex), servers).Store())
+}
+
+// SetLinkDNSEx is like SetLinkDNS but allows to set the port and server name of each server (systemd v246 or later).
+// ctx: Context to use
+// ifindex: interface index
+// servers: DNS servers
+func (c *Conn) SetLinkDNSEx(ctx context.Context, ifindex int, servers ...LinkDNSServer) error {
+	list := make([]linkDNSEx, len(servers))
+	for i, s := range servers {
+		list[i] = linkDNSEx{
+			Family:  family(s.Address),
+			Address: s.Address.AsSlice(),
+			Port:    s.Port,
+			Name:    s.Name,
+		}
+	}
+	return typedError(c.Call(ctx, "SetLinkDNSEx", int32(ifindex), list).Store())
+}
+
+// SetLinkDomains sets the search and routing domains of the link of index ifindex.
+// ctx: Context to use
+// ifindex: interface index
+// domains: search and routing domains
+func (c *Conn) SetLinkDomains(ctx context.Context, ifindex int, domains ...LinkDomain) error {
+	if domains == nil {
+		domains = []LinkDomain{}
+	}
+	return typedError(c.Call(ctx, "SetLinkDomains", int32(ifindex), domains).Store())
+}
+
+// RevertLinkDNS reverts the DNS servers and domains of the link of index ifindex to its configuration.
+// ctx: Context to use
+// ifindex: interface index
+func (c *Conn) RevertLinkDNS(ctx context.Context, ifindex int) error {
+	return typedError(c.Call(ctx, "RevertLinkDNS", int32(ifindex)).Store())
+}
+
+// SetLinkNTP sets the NTP servers of the link of index ifindex, which timesyncd picks up.
+// ctx: Context to use
+// ifindex: interface index
+// servers: NTP server names or addresses
+func (c *Conn) SetLinkNTP(ctx context.Context, ifindex int, servers ...string) error {
+	if servers == nil {
+		servers = []string{}
+	}
+	return typedError(c.Call(ctx, "SetLinkNTP", int32(ifindex), servers).Store())
+}
+
+// RevertLinkNTP reverts the NTP servers of the link of index ifindex to its configuration.
+// ctx: Context to use
+// ifindex: interface index
+func (c *Conn) RevertLinkNTP(ctx context.Context, ifindex int) error {
+	return typedError(c.Call(ctx, "RevertLinkNTP", int32(ifindex)).Store())
+}
+
+// SetDNS sets the DNS servers of the link, see Conn.SetLinkDNS.
+// ctx: Context to use
+// addrs: DNS server addresses
+func (l *Link) SetDNS(ctx context.Context, addrs ...netip.Addr) error {
+	return l.c.SetLinkDNS(ctx, l.Index, addrs...)
+}
+
+// SetDNSEx sets the DNS servers of the link, see Conn.SetLinkDNSEx.
+// ctx: Context to use
+// servers: DNS servers
+func (l *Link) SetDNSEx(ctx context.Context, servers ...LinkDNSServer) error {
+	return l.c.SetLinkDNSEx(ctx, l.Index, servers...)
+}
+
+// SetDomains sets the search and routing domains of the link, see Conn.SetLinkDomains.
+// ctx: Context to use
+// domains: search and routing domains
+func (l *Link) SetDomains(ctx context.Context, domains ...LinkDomain) error {
+	return l.c.SetLinkDomains(ctx, l.Index, domains...)
+}
+
+// RevertDNS reverts the DNS servers and domains of the link to its configuration.
+// ctx: Context to use
+func (l *Link) RevertDNS(ctx context.Context) error {
+	return l.c.RevertLinkDNS(ctx, l.Index)
+}
+
+// SetNTP sets the NTP servers of the link, see Conn.SetLinkNTP.
+// ctx: Context to use
+// servers: NTP server names or addresses
+func (l *Link) SetNTP(ctx context.Context, servers ...string) error {
+	return l.c.SetLinkNTP(ctx, l.Index, servers...)
+}
+
+// RevertNTP reverts the NTP servers of the link to its configuration.
+// ctx: Context to use
+func (l *Link) RevertNTP(ctx context.Context) error {
+	return l.c.RevertLinkNTP(ctx, l.Index)
+}
@@ -0,0 +1,108 @@
+package network1
+
+// OperationalState is the operational state of a link, or the overall one of the system.
+type OperationalState string
+
+const (
+	OperationalMissing         OperationalState = "missing"
+	OperationalOff             OperationalState = "off"
+	OperationalNoCarrier       OperationalState = "no-carrier"
+	OperationalDormant         OperationalState = "dormant"
+	OperationalDegradedCarrier OperationalState = "degraded-carrier"
+	OperationalCarrier         OperationalState = "carrier"
+	OperationalDegraded        OperationalState = "degraded"
+	OperationalEnslaved        OperationalState = "enslaved"
+	OperationalRoutable        OperationalState = "routable"
+)
+
+// CarrierState is the carrier state of a link.
+type CarrierState string
+
+const (
+	CarrierOff             CarrierState = "off"
+	CarrierNoCarrier       CarrierState = "no-carrier"
+	CarrierDormant         CarrierState = "dormant"
+	CarrierDegradedCarrier CarrierState = "degraded-carrier"
+	CarrierCarrier         CarrierState = "carrier"
+	CarrierEnslaved        CarrierState = "enslaved"
+)
+
+// AddressState is the address state of a link, globally or for one IP family.
+type AddressState string
+
+const (
+	AddressOff      AddressState = "off"
+	AddressDegraded AddressState = "degraded" // link local addresses only
+	AddressRoutable AddressState = "routable"
+)
+
+// OnlineState tells if the required links of the system (or the link itself) are online.
+type OnlineState string
+
+const (
+	OnlineUnknown OnlineState = "" // the link is not managed or not required for online
+	OnlineOffline OnlineState = "offline"
+	OnlinePartial OnlineState = "partial"
+	OnlineOnline  OnlineState = "online"
+)
+
+// AdministrativeState tells how far networkd got in configuring a link.
+type AdministrativeState string
+
+const (
+	AdminPending     AdministrativeState = "pending"
+	AdminInitialized AdministrativeState = "initialized"
+	AdminConfiguring AdministrativeState = "configuring"
+	AdminConfigured  AdministrativeState = "configured"
+	AdminUnmanaged   AdministrativeState = "unmanaged"
+	AdminFailed      AdministrativeState = "failed"
+	AdminLinger      AdministrativeState = "linger"
+)
+
+// State holds the states of a link, or the overall ones of the system (where AdministrativeState is empty).
+type State struct {
+	OperationalState    OperationalState
+	CarrierState        CarrierState
+	AddressState        AddressState
+	IPv4AddressState    AddressState
+	IPv6AddressState    AddressState
+	OnlineState         OnlineState
+	AdministrativeState AdministrativeState
+}
+
+var operationalOrder = []OperationalState{
+	OperationalMissing, OperationalOff, OperationalNoCarrier, OperationalDormant, OperationalDegradedCarrier,
+	OperationalCarrier, OperationalDegraded, OperationalEnslaved, OperationalRoutable,
+}
+
+// AtLeast tells if s is min or a more connected state (eg: routable is at least degraded).
+// Unknown states are never at least anything.
+func (s OperationalState) AtLeast(min OperationalState) bool {
+	return operationalRank(s) >= 0 && operationalRank(s) >= operationalRank(min)
+}
+
+func operationalRank(s OperationalState) int {
+	for i, o := range operationalOrder {
+		if s == o {
+			return i
+		}
+	}
+	return -1
+}
+
+// AtLeast tells if s is min or a more connected state (eg: routable is at least degraded).
+func (s AddressState) AtLeast(min AddressState) bool {
+	rank := func(s AddressState) int {
+		switch s {
+		case AddressOff:
+			return 0
+		case AddressDegraded:
+			return 1
+		case AddressRoutable:
+			return 2
+		default:
+			return -1
+		}
+	}
+	return rank(s) >= 0 && rank(s) >= rank(min)
+}
@@ -0,0 +1,147 @@
+package network1
+
+import (
+	"context"
+	"net/netip"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+const (
+	resolvedDest          = "org.freedesktop.resolve1"
+	resolvedPath          = "/org/freedesktop/resolve1"
+	resolvedLinkInterface = "org.freedesktop.resolve1.Link"
+	timesyncDest          = "org.freedesktop.timesync1"
+	timesyncPath          = "/org/freedesktop/timesync1"
+	timesyncInterface     = "org.freedesktop.timesync1.Manager"
+)
+
+// InterfaceStatus aggregates what networkd, resolved and timesyncd know about a link,
+// like "networkctl status" shows it. The resolved and timesyncd fields are left empty
+// when those services are not running.
+type InterfaceStatus struct {
+	LinkDescription
+	CurrentDNSServer netip.Addr   // DNS server resolved currently uses for the link
+	ResolvedDNS      []netip.Addr // DNS servers resolved uses for the link, whatever their source
+	ResolvedDomains  []LinkDomain
+	DefaultRoute     bool   // whether resolved routes the queries not matching any domain thru the link
+	DNSSEC           string // DNSSEC mode of the link
+	DNSOverTLS       string // DNS over TLS mode of the link
+	TimeServer       string // server timesyncd is synchronized with, if it is one of the link NTP servers
+}
+
+// Status returns the aggregated status of the link of index ifindex.
+// ctx: Context to use
+// ifindex: interface index
+func (c *Conn) Status(ctx context.Context, ifindex int) (*InterfaceStatus, error) {
+	l, err := c.LinkByIndex(ctx, ifindex)
+	if err != nil {
+		return nil, err
+	}
+	return l.Status(ctx)
+}
+
+// Statuses returns the aggregated status of every link.
+// ctx: Context to use
+func (c *Conn) Statuses(ctx context.Context) ([]InterfaceStatus, error) {
+	links, err := c.ListLinks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	timesync := c.timesyncServer(ctx)
+	statuses := make([]InterfaceStatus, 0, len(links))
+	for _, ls := range links {
+		s, err := c.link(ls.Index, ls.Name, ls.Path).status(ctx, timesync)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, *s)
+	}
+	return statuses, nil
+}
+
+// Status returns the aggregated status of the link.
+// ctx: Context to use
+func (l *Link) Status(ctx context.Context) (*InterfaceStatus, error) {
+	return l.status(ctx, l.c.timesyncServer(ctx))
+}
+
+// timeServer is the server timesyncd is synchronized with.
+type timeServer struct {
+	name    string
+	address netip.Addr
+}
+
+func (l *Link) status(ctx context.Context, timesync timeServer) (*InterfaceStatus, error) {
+	s := &InterfaceStatus{}
+	d, err := l.Describe(ctx)
+	if err == nil {
+		s.LinkDescription = *d
+	} else {
+		// Describe needs systemd v250, fall back on the states
+		state, err := l.State(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.Index, s.Name, s.State = l.Index, l.Name, *state
+	}
+	l.c.resolvedStatus(ctx, s)
+	for _, ntp := range s.NTP {
+		if timesync.name != "" && (ntp.Server == timesync.name || timesync.address.IsValid() && ntp.Address == timesync.address) {
+			s.TimeServer = timesync.name
+			break
+		}
+	}
+	return s, nil
+}
+
+// resolvedStatus fills the resolved fields of s, best effort.
+func (c *Conn) resolvedStatus(ctx context.Context, s *InterfaceStatus) {
+	var path dbus.ObjectPath
+	resolved := c.conn.Object(resolvedDest, resolvedPath)
+	if resolved.CallWithContext(ctx, "org.freedesktop.resolve1.Manager.GetLink", 0, int32(s.Index)).Store(&path) != nil {
+		return
+	}
+	props, err := sysdbus.GetAllProperties(ctx, c.conn.Object(resolvedDest, path), resolvedLinkInterface)
+	if err != nil {
+		return
+	}
+	var (
+		current linkDNS
+		servers []linkDNS
+	)
+	if sysdbus.StoreProp(props, "CurrentDNSServer", &current) == nil {
+		s.CurrentDNSServer, _ = netip.AddrFromSlice(current.Address)
+	}
+	if sysdbus.StoreProp(props, "DNS", &servers) == nil {
+		for _, server := range servers {
+			if addr, ok := netip.AddrFromSlice(server.Address); ok {
+				s.ResolvedDNS = append(s.ResolvedDNS, addr)
+			}
+		}
+	}
+	sysdbus.StoreProp(props, "Domains", &s.ResolvedDomains)
+	s.DefaultRoute = sysdbus.Prop[bool](props, "DefaultRoute")
+	s.DNSSEC = sysdbus.Prop[string](props, "DNSSEC")
+	s.DNSOverTLS = sysdbus.Prop[string](props, "DNSOverTLS")
+}
+
+// timesyncServer returns the server timesyncd is synchronized with, best effort.
+func (c *Conn) timesyncServer(ctx context.Context) timeServer {
+	props, err := sysdbus.GetAllProperties(ctx, c.conn.Object(timesyncDest, timesyncPath), timesyncInterface)
+	if err != nil {
+		return timeServer{}
+	}
+	var address struct {
+		Family  int32
+		Address []byte
+	}
+	ts := timeServer{
+		name: sysdbus.Prop[string](props, "ServerName"),
+	}
+	if sysdbus.StoreProp(props, "ServerAddress", &address) == nil {
+		ts.address, _ = netip.AddrFromSlice(address.Address)
+	}
+	return ts
+}
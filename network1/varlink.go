@@ -0,0 +1,72 @@
+package network1
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/iguanesolutions/go-systemd/v6/internal/varlink"
+)
+
+const (
+	varlinkSocket    = "/run/systemd/netif/io.systemd.Network"
+	varlinkInterface = "io.systemd.Network"
+)
+
+// VarlinkError is an error reply of networkd, eg: io.systemd.Network.NoSuchLink.
+type VarlinkError = varlink.Error
+
+// ErrVarlinkBroken is returned by the calls made on a Varlink connection after a call has been
+// interrupted (eg: context canceled), a new connection must be dialed.
+var ErrVarlinkBroken = varlink.ErrBroken
+
+// Varlink represents a connection to the io.systemd.Network varlink interface of networkd,
+// which exposes richer state than dbus (DHCP leases, DNS, routes...) on recent systemd versions.
+type Varlink struct {
+	conn *varlink.Conn
+}
+
+// DialVarlink returns a new and ready to use varlink connection to networkd.
+// You must close that connection when you have been done with it.
+// ctx: Context to use
+func DialVarlink(ctx context.Context) (*Varlink, error) {
+	conn, err := varlink.Dial(ctx, varlinkSocket)
+	if err != nil {
+		return nil, err
+	}
+	return &Varlink{
+		conn: conn,
+	}, nil
+}
+
+// Call calls the io.systemd.Network method with params (nil for none) and stores the reply
+// parameters into reply (nil to discard them). Error replies can be inspected with errors.As
+// and a *VarlinkError.
+func (v *Varlink) Call(ctx context.Context, method string, params, reply interface{}) error {
+	return v.conn.Call(ctx, varlinkInterface+"."+method, params, reply)
+}
+
+// Close closes the current varlink connection.
+func (v *Varlink) Close() error {
+	return v.conn.Close()
+}
+
+// States returns the overall states of the system, AdministrativeState is always empty.
+// ctx: Context to use
+func (v *Varlink) States(ctx context.Context) (*State, error) {
+	var s State
+	if err := v.Call(ctx, "GetStates", nil, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Describe returns the raw JSON description of networkd and every link, as used by
+// "networkctl status --json".
+// ctx: Context to use
+func (v *Varlink) Describe(ctx context.Context) (json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := v.Call(ctx, "Describe", nil, &raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
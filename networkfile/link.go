@@ -0,0 +1,51 @@
+package networkfile
+
+import (
+	"net"
+
+	"github.com/iguanesolutions/go-systemd/v6/unitfile"
+)
+
+// LinkFile is a typed .link file, see systemd.link(5). Those are applied by udev when
+// the device appears, not by networkd.
+type LinkFile struct {
+	Match *MatchSection
+	Link  LinkFileSection
+}
+
+// LinkFileSection is the [Link] section of a .link file.
+type LinkFileSection struct {
+	Description      string
+	Name             string
+	NamePolicy       []string // eg: kernel, database, onboard, slot, path
+	AlternativeNames []string
+	MACAddressPolicy string // persistent, random or none
+	MACAddress       net.HardwareAddr
+	MTUBytes         int
+	WakeOnLan        string
+	Extra            []unitfile.Option
+}
+
+// File converts l into a generic unitfile.File.
+func (l *LinkFile) File() *unitfile.File {
+	f := &unitfile.File{}
+	l.Match.write(f)
+	w := section(f, "Link")
+	w.Str("Description", l.Link.Description)
+	w.Str("Name", l.Link.Name)
+	w.List("NamePolicy", l.Link.NamePolicy)
+	w.List("AlternativeName", l.Link.AlternativeNames)
+	w.Str("MACAddressPolicy", l.Link.MACAddressPolicy)
+	if l.Link.MACAddress != nil {
+		w.Add("MACAddress", l.Link.MACAddress.String())
+	}
+	w.Int("MTUBytes", l.Link.MTUBytes)
+	w.Str("WakeOnLan", l.Link.WakeOnLan)
+	w.Extra(l.Link.Extra)
+	return f
+}
+
+// Marshal returns the content of the .link file.
+func (l *LinkFile) Marshal() ([]byte, error) {
+	return l.File().Marshal()
+}
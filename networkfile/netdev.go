@@ -0,0 +1,148 @@
+package networkfile
+
+import (
+	"errors"
+	"net"
+	"net/netip"
+	"strconv"
+	"time"
+
+	"github.com/iguanesolutions/go-systemd/v6/unitfile"
+)
+
+// NetDevFile is a typed .netdev file, see systemd.netdev(5). Nil sections are omitted,
+// the section of the kind set in NetDev should be filled when the kind needs one.
+type NetDevFile struct {
+	Match          *MatchSection
+	NetDev         NetDevSection
+	VLAN           *VLANSection
+	Bridge         *BridgeSection
+	Bond           *BondSection
+	WireGuard      *WireGuardSection
+	WireGuardPeers []WireGuardPeerSection
+}
+
+// NetDevSection is the [NetDev] section.
+type NetDevSection struct {
+	Name        string
+	Kind        string // eg: bridge, bond, vlan, wireguard, dummy, veth
+	Description string
+	MTUBytes    int
+	MACAddress  net.HardwareAddr
+	Extra       []unitfile.Option
+}
+
+// VLANSection is the [VLAN] section, the VLAN netdev must be referenced from the .network
+// file of its underlying link (see NetworkSection.VLAN).
+type VLANSection struct {
+	ID    int
+	Extra []unitfile.Option
+}
+
+// BridgeSection is the [Bridge] section.
+type BridgeSection struct {
+	STP             bool
+	VLANFiltering   bool
+	ForwardDelaySec time.Duration
+	Priority        int
+	Extra           []unitfile.Option
+}
+
+// BondSection is the [Bond] section.
+type BondSection struct {
+	Mode               string // eg: active-backup, 802.3ad, balance-rr
+	TransmitHashPolicy string // eg: layer2, layer3+4
+	LACPTransmitRate   string // slow or fast
+	MIIMonitorSec      time.Duration
+	MinLinks           int
+	Extra              []unitfile.Option
+}
+
+// WireGuardSection is the [WireGuard] section, one of PrivateKey and PrivateKeyFile is required.
+// Prefer PrivateKeyFile: .netdev files are usually world readable.
+type WireGuardSection struct {
+	PrivateKey     string
+	PrivateKeyFile string
+	ListenPort     int
+	FirewallMark   int
+	RouteTable     string
+	Extra          []unitfile.Option
+}
+
+// WireGuardPeerSection is a [WireGuardPeer] section.
+type WireGuardPeerSection struct {
+	PublicKey           string
+	PresharedKeyFile    string
+	AllowedIPs          []netip.Prefix
+	Endpoint            string        // host:port
+	PersistentKeepalive time.Duration // rounded down to the second
+	Extra               []unitfile.Option
+}
+
+// File converts n into a generic unitfile.File.
+func (n *NetDevFile) File() *unitfile.File {
+	f := &unitfile.File{}
+	n.Match.write(f)
+	w := section(f, "NetDev")
+	w.Str("Name", n.NetDev.Name)
+	w.Str("Kind", n.NetDev.Kind)
+	w.Str("Description", n.NetDev.Description)
+	w.Int("MTUBytes", n.NetDev.MTUBytes)
+	if n.NetDev.MACAddress != nil {
+		w.Add("MACAddress", n.NetDev.MACAddress.String())
+	}
+	w.Extra(n.NetDev.Extra)
+	if s := n.VLAN; s != nil {
+		w := section(f, "VLAN")
+		w.Add("Id", strconv.Itoa(s.ID))
+		w.Extra(s.Extra)
+	}
+	if s := n.Bridge; s != nil {
+		w := section(f, "Bridge")
+		w.Bool("STP", s.STP)
+		w.Bool("VLANFiltering", s.VLANFiltering)
+		w.Span("ForwardDelaySec", s.ForwardDelaySec)
+		w.Int("Priority", s.Priority)
+		w.Extra(s.Extra)
+	}
+	if s := n.Bond; s != nil {
+		w := section(f, "Bond")
+		w.Str("Mode", s.Mode)
+		w.Str("TransmitHashPolicy", s.TransmitHashPolicy)
+		w.Str("LACPTransmitRate", s.LACPTransmitRate)
+		w.Span("MIIMonitorSec", s.MIIMonitorSec)
+		w.Int("MinLinks", s.MinLinks)
+		w.Extra(s.Extra)
+	}
+	if s := n.WireGuard; s != nil {
+		w := section(f, "WireGuard")
+		w.Str("PrivateKey", s.PrivateKey)
+		w.Str("PrivateKeyFile", s.PrivateKeyFile)
+		w.Int("ListenPort", s.ListenPort)
+		w.Int("FirewallMark", s.FirewallMark)
+		w.Str("RouteTable", s.RouteTable)
+		w.Extra(s.Extra)
+	}
+	for _, s := range n.WireGuardPeers {
+		w := section(f, "WireGuardPeer")
+		w.Str("PublicKey", s.PublicKey)
+		w.Str("PresharedKeyFile", s.PresharedKeyFile)
+		for _, p := range s.AllowedIPs {
+			w.prefix("AllowedIPs", p)
+		}
+		w.Str("Endpoint", s.Endpoint)
+		// plain seconds only, no time span
+		w.Int("PersistentKeepalive", int(s.PersistentKeepalive/time.Second))
+		w.Extra(s.Extra)
+	}
+	return f
+}
+
+// Marshal returns the content of the .netdev file.
+// It fails if the Name= or Kind= required by networkd is missing.
+func (n *NetDevFile) Marshal() ([]byte, error) {
+	if n.NetDev.Name == "" || n.NetDev.Kind == "" {
+		return nil, errors.New("[NetDev] Name= and Kind= are required")
+	}
+	return n.File().Marshal()
+}
@@ -0,0 +1,166 @@
+package networkfile
+
+import (
+	"net"
+	"net/netip"
+
+	"github.com/iguanesolutions/go-systemd/v6/unitfile"
+)
+
+// NetworkFile is a typed .network file, see systemd.network(5). Nil sections are omitted.
+type NetworkFile struct {
+	Match     *MatchSection
+	Link      *LinkSection
+	Network   *NetworkSection
+	Addresses []AddressSection
+	Routes    []RouteSection
+	DHCPv4    *DHCPv4Section
+}
+
+// LinkSection is the [Link] section of a .network file.
+type LinkSection struct {
+	MACAddress        net.HardwareAddr
+	MTUBytes          int
+	Unmanaged         bool
+	RequiredForOnline string // eg: yes, no, degraded, routable:routable
+	ActivationPolicy  string // eg: up, always-up, manual
+	Extra             []unitfile.Option
+}
+
+// NetworkSection is the [Network] section of a .network file.
+type NetworkSection struct {
+	Description             string
+	DHCP                    string // yes, no, ipv4 or ipv6
+	Address                 []netip.Prefix
+	Gateway                 []netip.Addr
+	DNS                     []netip.Addr
+	Domains                 []string // search domains, "~" prefixed for routing only domains
+	NTP                     []string
+	IPv6AcceptRA            *bool
+	LinkLocalAddressing     string // yes, no, ipv4 or ipv6
+	LLDP                    string // yes, no or routers-only
+	EmitLLDP                string // yes, no, nearest-bridge, non-tpmr-bridge or customer-bridge
+	ConfigureWithoutCarrier bool
+	Bridge                  string
+	Bond                    string
+	VLAN                    []string
+	Extra                   []unitfile.Option
+}
+
+// AddressSection is an [Address] section, for addresses needing more than NetworkSection.Address.
+type AddressSection struct {
+	Address netip.Prefix
+	Peer    netip.Prefix
+	Label   string
+	Scope   string // global, link or host
+	Extra   []unitfile.Option
+}
+
+// RouteSection is a [Route] section.
+type RouteSection struct {
+	Destination   netip.Prefix // default route if invalid
+	Gateway       netip.Addr
+	GatewayOnLink bool
+	Source        netip.Prefix
+	Metric        int
+	Table         string // table name or number
+	Scope         string
+	Extra         []unitfile.Option
+}
+
+// DHCPv4Section is the [DHCPv4] section. Nil Use* fields keep the default of networkd:
+// yes, but for UseMTU= and UseDomains= which default to no.
+type DHCPv4Section struct {
+	UseDNS                *bool
+	UseNTP                *bool
+	UseDomains            *bool
+	UseHostname           *bool
+	UseRoutes             *bool
+	UseMTU                *bool
+	SendHostname          *bool
+	Hostname              string
+	ClientIdentifier      string // mac or duid
+	VendorClassIdentifier string
+	RouteMetric           int
+	Extra                 []unitfile.Option
+}
+
+// File converts n into a generic unitfile.File.
+func (n *NetworkFile) File() *unitfile.File {
+	f := &unitfile.File{}
+	n.Match.write(f)
+	if s := n.Link; s != nil {
+		w := section(f, "Link")
+		if s.MACAddress != nil {
+			w.Add("MACAddress", s.MACAddress.String())
+		}
+		w.Int("MTUBytes", s.MTUBytes)
+		w.Bool("Unmanaged", s.Unmanaged)
+		w.Str("RequiredForOnline", s.RequiredForOnline)
+		w.Str("ActivationPolicy", s.ActivationPolicy)
+		w.Extra(s.Extra)
+	}
+	if s := n.Network; s != nil {
+		w := section(f, "Network")
+		w.Str("Description", s.Description)
+		w.Str("DHCP", s.DHCP)
+		for _, p := range s.Address {
+			w.prefix("Address", p)
+		}
+		w.addrs("Gateway", s.Gateway)
+		w.addrs("DNS", s.DNS)
+		w.List("Domains", s.Domains)
+		w.List("NTP", s.NTP)
+		w.tristate("IPv6AcceptRA", s.IPv6AcceptRA)
+		w.Str("LinkLocalAddressing", s.LinkLocalAddressing)
+		w.Str("LLDP", s.LLDP)
+		w.Str("EmitLLDP", s.EmitLLDP)
+		w.Bool("ConfigureWithoutCarrier", s.ConfigureWithoutCarrier)
+		w.Str("Bridge", s.Bridge)
+		w.Str("Bond", s.Bond)
+		for _, vlan := range s.VLAN {
+			w.Add("VLAN", vlan)
+		}
+		w.Extra(s.Extra)
+	}
+	for _, s := range n.Addresses {
+		w := section(f, "Address")
+		w.prefix("Address", s.Address)
+		w.prefix("Peer", s.Peer)
+		w.Str("Label", s.Label)
+		w.Str("Scope", s.Scope)
+		w.Extra(s.Extra)
+	}
+	for _, s := range n.Routes {
+		w := section(f, "Route")
+		w.prefix("Destination", s.Destination)
+		w.addr("Gateway", s.Gateway)
+		w.Bool("GatewayOnLink", s.GatewayOnLink)
+		w.prefix("Source", s.Source)
+		w.Int("Metric", s.Metric)
+		w.Str("Table", s.Table)
+		w.Str("Scope", s.Scope)
+		w.Extra(s.Extra)
+	}
+	if s := n.DHCPv4; s != nil {
+		w := section(f, "DHCPv4")
+		w.tristate("UseDNS", s.UseDNS)
+		w.tristate("UseNTP", s.UseNTP)
+		w.tristate("UseDomains", s.UseDomains)
+		w.tristate("UseHostname", s.UseHostname)
+		w.tristate("UseRoutes", s.UseRoutes)
+		w.tristate("UseMTU", s.UseMTU)
+		w.tristate("SendHostname", s.SendHostname)
+		w.Str("Hostname", s.Hostname)
+		w.Str("ClientIdentifier", s.ClientIdentifier)
+		w.Str("VendorClassIdentifier", s.VendorClassIdentifier)
+		w.Int("RouteMetric", s.RouteMetric)
+		w.Extra(s.Extra)
+	}
+	return f
+}
+
+// Marshal returns the content of the .network file.
+func (n *NetworkFile) Marshal() ([]byte, error) {
+	return n.File().Marshal()
+}
@@ -0,0 +1,100 @@
+// Package networkfile writes systemd-networkd configuration files from typed section structs:
+// .network files (NetworkFile), .netdev files (NetDevFile) and .link files (LinkFile).
+// The files are built as unitfile.File, so they can be altered or parsed back with the unitfile package.
+package networkfile
+
+import (
+	"net"
+	"net/netip"
+
+	"github.com/iguanesolutions/go-systemd/v6/unitfile"
+)
+
+// MatchSection is the [Match] section, which selects the links a file applies to.
+// Every non empty field must match, a list matches if any of its entries does.
+type MatchSection struct {
+	Name                []string // interface names, globs allowed
+	MACAddress          []net.HardwareAddr
+	PermanentMACAddress []net.HardwareAddr
+	OriginalName        []string // kernel name, .link files only
+	Path                []string // udev ID_PATH, globs allowed
+	Driver              []string
+	Type                []string // eg: ether, wlan, loopback
+	Kind                []string // netdev kind, eg: bridge, vlan
+	Host                string
+	Virtualization      string
+	Extra               []unitfile.Option
+}
+
+// writer extends unitfile.Writer with the value types of networkd, whose files have no specifiers.
+type writer struct {
+	unitfile.Writer
+}
+
+// section appends a new section to f, sections of the same name are not merged.
+func section(f *unitfile.File, name string) writer {
+	s := &unitfile.Section{Name: name}
+	f.Sections = append(f.Sections, s)
+	return writer{unitfile.Writer{Section: s}}
+}
+
+// tristate writes value when set, so the defaults of networkd (often yes) can be overridden.
+func (w writer) tristate(name string, value *bool) {
+	if value == nil {
+		return
+	}
+	if *value {
+		w.Add(name, "yes")
+	} else {
+		w.Add(name, "no")
+	}
+}
+
+func (w writer) hwaddrs(name string, values []net.HardwareAddr) {
+	list := make([]string, len(values))
+	for i, v := range values {
+		list[i] = v.String()
+	}
+	w.List(name, list)
+}
+
+func (w writer) addr(name string, value netip.Addr) {
+	if value.IsValid() {
+		w.Add(name, value.String())
+	}
+}
+
+func (w writer) addrs(name string, values []netip.Addr) {
+	for _, v := range values {
+		w.Add(name, v.String())
+	}
+}
+
+func (w writer) prefix(name string, value netip.Prefix) {
+	if value.IsValid() {
+		w.Add(name, value.String())
+	}
+}
+
+func (m *MatchSection) write(f *unitfile.File) {
+	if m == nil {
+		return
+	}
+	w := section(f, "Match")
+	w.List("Name", m.Name)
+	w.hwaddrs("MACAddress", m.MACAddress)
+	w.hwaddrs("PermanentMACAddress", m.PermanentMACAddress)
+	w.List("OriginalName", m.OriginalName)
+	w.List("Path", m.Path)
+	w.List("Driver", m.Driver)
+	w.List("Type", m.Type)
+	w.List("Kind", m.Kind)
+	w.Str("Host", m.Host)
+	w.Str("Virtualization", m.Virtualization)
+	w.Extra(m.Extra)
+}
+
+// Bool returns a pointer to b, for the optional boolean fields.
+func Bool(b bool) *bool {
+	return &b
+}
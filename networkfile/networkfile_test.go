@@ -0,0 +1,111 @@
+package networkfile
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestNetworkFile(t *testing.T) {
+	n := &NetworkFile{
+		Match: &MatchSection{Name: []string{"eth0"}},
+		Network: &NetworkSection{
+			Address: []netip.Prefix{netip.MustParsePrefix("192.0.2.10/24")},
+			Gateway: []netip.Addr{netip.MustParseAddr("192.0.2.1")},
+			DNS:     []netip.Addr{netip.MustParseAddr("192.0.2.53"), netip.MustParseAddr("2001:db8::53")},
+			Domains: []string{"example.com", "~corp"},
+			VLAN:    []string{"vlan42"},
+		},
+		Routes: []RouteSection{
+			{Destination: netip.MustParsePrefix("10.0.0.0/8"), Gateway: netip.MustParseAddr("192.0.2.254")},
+			{Destination: netip.MustParsePrefix("172.16.0.0/12"), Gateway: netip.MustParseAddr("192.0.2.253"), Metric: 100},
+		},
+		DHCPv4: &DHCPv4Section{UseDNS: Bool(false)},
+	}
+	data, err := n.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[Match]
+Name=eth0
+
+[Network]
+Address=192.0.2.10/24
+Gateway=192.0.2.1
+DNS=192.0.2.53
+DNS=2001:db8::53
+Domains=example.com ~corp
+VLAN=vlan42
+
+[Route]
+Destination=10.0.0.0/8
+Gateway=192.0.2.254
+
+[Route]
+Destination=172.16.0.0/12
+Gateway=192.0.2.253
+Metric=100
+
+[DHCPv4]
+UseDNS=no
+`
+	if string(data) != want {
+		t.Errorf("unexpected .network file:\n%s", data)
+	}
+}
+
+func TestNetDevFile(t *testing.T) {
+	n := &NetDevFile{
+		NetDev: NetDevSection{Name: "wg0", Kind: "wireguard"},
+		WireGuard: &WireGuardSection{
+			PrivateKeyFile: "/etc/systemd/network/wg0.key",
+			ListenPort:     51820,
+		},
+		WireGuardPeers: []WireGuardPeerSection{{
+			PublicKey:           "xTIBA5rboUvnH4htodjb6e697QjLERt1NAB4mZqp8Dg=",
+			AllowedIPs:          []netip.Prefix{netip.MustParsePrefix("10.0.0.0/24"), netip.MustParsePrefix("fd00::/64")},
+			Endpoint:            "vpn.example.com:51820",
+			PersistentKeepalive: 25 * time.Second,
+		}},
+	}
+	data, err := n.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[NetDev]
+Name=wg0
+Kind=wireguard
+
+[WireGuard]
+PrivateKeyFile=/etc/systemd/network/wg0.key
+ListenPort=51820
+
+[WireGuardPeer]
+PublicKey=xTIBA5rboUvnH4htodjb6e697QjLERt1NAB4mZqp8Dg=
+AllowedIPs=10.0.0.0/24
+AllowedIPs=fd00::/64
+Endpoint=vpn.example.com:51820
+PersistentKeepalive=25
+`
+	if string(data) != want {
+		t.Errorf("unexpected .netdev file:\n%s", data)
+	}
+}
+
+func TestNetDevFileRequired(t *testing.T) {
+	n := &NetDevFile{
+		NetDev: NetDevSection{Name: "br0"},
+		Bridge: &BridgeSection{ForwardDelaySec: 1500 * time.Millisecond},
+	}
+	if _, err := n.Marshal(); err == nil {
+		t.Error("missing Kind= should fail")
+	}
+	n.NetDev.Kind = "bridge"
+	data, err := n.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "[NetDev]\nName=br0\nKind=bridge\n\n[Bridge]\nForwardDelaySec=1s 500ms\n"; string(data) != want {
+		t.Errorf("unexpected .netdev file:\n%s", data)
+	}
+}
@@ -0,0 +1,158 @@
+package sysdnotify
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrQueueFull is returned by AsyncNotifier when its queue can't take more states.
+	ErrQueueFull = errors.New("notify queue is full")
+	// ErrClosed is returned by AsyncNotifier once it has been closed.
+	ErrClosed = errors.New("notifier is closed")
+)
+
+// AsyncNotifier queues states and sends them thru the notify socket from a background goroutine,
+// so hot paths never block on the unix socket. Pending STATUS updates are coalesced: only the
+// latest one is sent. Use Flush() or Close() before exiting to make sure everything has been sent.
+type AsyncNotifier struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queue  []asyncItem
+	states int
+	size   int
+	err    error
+	closed bool
+	done   chan struct{}
+}
+
+type asyncItem struct {
+	state   string
+	flushed chan struct{} // set for flush markers only
+}
+
+// NewAsyncNotifier returns a started AsyncNotifier which can hold up to queueSize states.
+func NewAsyncNotifier(queueSize int) *AsyncNotifier {
+	if queueSize <= 0 {
+		queueSize = 1
+	}
+	n := &AsyncNotifier{
+		size: queueSize,
+		done: make(chan struct{}),
+	}
+	n.cond = sync.NewCond(&n.mu)
+	go n.run()
+	return n
+}
+
+// Send queues state to be sent by the background goroutine.
+// It never blocks, it returns ErrQueueFull if the queue is full.
+func (n *AsyncNotifier) Send(state string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.closed {
+		return ErrClosed
+	}
+	if isStatusOnly(state) {
+		// drop the previous STATUS update not sent yet
+		for i, item := range n.queue {
+			if item.flushed == nil && isStatusOnly(item.state) {
+				n.queue = append(n.queue[:i], n.queue[i+1:]...)
+				n.states--
+				break
+			}
+		}
+	}
+	if n.states >= n.size {
+		return ErrQueueFull
+	}
+	n.queue = append(n.queue, asyncItem{state: state})
+	n.states++
+	n.cond.Signal()
+	return nil
+}
+
+// Status queues a STATUS=%s{status} state, status is handled the same way as Status() does.
+func (n *AsyncNotifier) Status(status string) error {
+	state, err := statusState(status)
+	if err != nil {
+		return err
+	}
+	return n.Send(state)
+}
+
+// Flush waits until every state queued before the call has been sent, or ctx is done.
+// It returns the first send error encountered since the previous Flush, if any.
+func (n *AsyncNotifier) Flush(ctx context.Context) error {
+	n.mu.Lock()
+	if n.closed {
+		n.mu.Unlock()
+		return ErrClosed
+	}
+	flushed := make(chan struct{})
+	n.queue = append(n.queue, asyncItem{flushed: flushed})
+	n.cond.Signal()
+	n.mu.Unlock()
+	select {
+	case <-flushed:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	err := n.err
+	n.err = nil
+	return err
+}
+
+// Close stops accepting new states, drains the queue and stops the background goroutine.
+// It returns the first send error encountered since the last Flush, if any.
+func (n *AsyncNotifier) Close() error {
+	n.mu.Lock()
+	if n.closed {
+		n.mu.Unlock()
+		return ErrClosed
+	}
+	n.closed = true
+	n.cond.Signal()
+	n.mu.Unlock()
+	<-n.done
+	return n.err
+}
+
+func (n *AsyncNotifier) run() {
+	defer close(n.done)
+	for {
+		n.mu.Lock()
+		for len(n.queue) == 0 && !n.closed {
+			n.cond.Wait()
+		}
+		if len(n.queue) == 0 {
+			n.mu.Unlock()
+			return
+		}
+		item := n.queue[0]
+		n.queue = n.queue[1:]
+		if item.flushed == nil {
+			n.states--
+		}
+		n.mu.Unlock()
+		if item.flushed != nil {
+			close(item.flushed)
+			continue
+		}
+		if err := Send(item.state); err != nil {
+			n.mu.Lock()
+			if n.err == nil {
+				n.err = err
+			}
+			n.mu.Unlock()
+		}
+	}
+}
+
+func isStatusOnly(state string) bool {
+	return strings.HasPrefix(state, "STATUS=") && !strings.Contains(state, "\n")
+}
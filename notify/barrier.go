@@ -0,0 +1,49 @@
+package sysdnotify
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Barrier sends systemd notify BARRIER=1 and waits for systemd to have processed every
+// message sent before it, or for timeout to expire. It requires systemd v246 or later.
+// If the notify socket was not detected, it is a noop call.
+func Barrier(timeout time.Duration) error {
+	if !IsEnabled() {
+		return nil
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		return fmt.Errorf("can't create barrier pipe: %w", err)
+	}
+	defer r.Close()
+	err = SendWithFDs("BARRIER=1", int(w.Fd()))
+	// systemd closes its copy of the write end once every previous message has been processed
+	w.Close()
+	if err != nil {
+		return err
+	}
+	if err = r.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return fmt.Errorf("can't set barrier deadline: %w", err)
+	}
+	if _, err = r.Read(make([]byte, 1)); !errors.Is(err, io.EOF) {
+		if err == nil {
+			err = errors.New("unexpected data")
+		}
+		return fmt.Errorf("failed to wait for barrier: %w", err)
+	}
+	return nil
+}
+
+// HandOverMainPID sends systemd notify MAINPID=%d{pid} for a newly forked/execed worker and
+// waits for systemd to have processed it (see Barrier), so supervisor-style programs can
+// safely exit or move on knowing systemd now tracks pid as the main process.
+func HandOverMainPID(pid int, timeout time.Duration) error {
+	if err := MainPID(pid); err != nil {
+		return err
+	}
+	return Barrier(timeout)
+}
@@ -0,0 +1,49 @@
+package sysdnotify
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ChildEnv returns a copy of env (os.Environ() if env is nil) prepared for a child process.
+//
+// If allow is true, NOTIFY_SOCKET is set to the socket detected at startup so the child can
+// send notifications. Keep in mind systemd only accepts messages coming from the main PID by
+// default: the unit must set NotifyAccess=all for the child's messages to be taken into account
+// (NotifyAccess=exec also accepts processes of the Exec*= command lines, not their forks).
+//
+// If allow is false, NOTIFY_SOCKET is removed so the child can't interfere with our own state.
+// In both cases WATCHDOG_USEC and WATCHDOG_PID are removed as the child is not the watched process.
+func ChildEnv(env []string, allow bool) []string {
+	if env == nil {
+		env = os.Environ()
+	}
+	child := make([]string, 0, len(env)+1)
+	for _, kv := range env {
+		switch {
+		case strings.HasPrefix(kv, "NOTIFY_SOCKET="),
+			strings.HasPrefix(kv, "WATCHDOG_USEC="),
+			strings.HasPrefix(kv, "WATCHDOG_PID="):
+			continue
+		}
+		child = append(child, kv)
+	}
+	if addr := socket.Load(); allow && addr != nil {
+		child = append(child, "NOTIFY_SOCKET="+addr.Name)
+	}
+	return child
+}
+
+// PrepareCmd sets cmd.Env using ChildEnv(cmd.Env, allow).
+// It must be called before cmd is started.
+func PrepareCmd(cmd *exec.Cmd, allow bool) {
+	cmd.Env = ChildEnv(cmd.Env, allow)
+}
+
+// UnsetEnv removes NOTIFY_SOCKET from the current process environment, like sd_notify() does
+// with unset_environment, so children started without PrepareCmd() do not inherit it.
+// The socket detected at startup is still used by this package.
+func UnsetEnv() error {
+	return os.Unsetenv("NOTIFY_SOCKET")
+}
@@ -0,0 +1,26 @@
+package sysdnotify
+
+import "sync/atomic"
+
+// Hook is called after each state sent thru the notify socket with the send result.
+type Hook func(state string, err error)
+
+var hook atomic.Pointer[Hook]
+
+// SetHook registers fn to be called after every send (including the ones made by
+// AsyncNotifier and the watchdog), which is handy to trace what was sent and when it failed.
+// fn is called synchronously from the sending goroutine, it must not block.
+// Passing nil removes the hook.
+func SetHook(fn Hook) {
+	if fn == nil {
+		hook.Store(nil)
+		return
+	}
+	hook.Store(&fn)
+}
+
+func callHook(state string, err error) {
+	if fn := hook.Load(); fn != nil {
+		(*fn)(state, err)
+	}
+}
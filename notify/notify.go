@@ -1,25 +1,39 @@
+// Package sysdnotify implements the systemd notify protocol (sd_notify).
+//
+// Every function of this package is safe for concurrent use by multiple goroutines.
+// States are written one at a time thru a single shared socket: two states sent
+// concurrently are never interleaved, and states sent sequentially by a goroutine
+// reach systemd in the same order. There is no ordering between goroutines other
+// than the order in which they acquire the socket.
 package sysdnotify
 
 import (
 	"fmt"
 	"net"
 	"os"
+	"sync/atomic"
+	"syscall"
+	"time"
 )
 
-var socket *net.UnixAddr
+var (
+	socket atomic.Pointer[net.UnixAddr]
+	// conn is shared by every send and guarded by sendMu
+	conn *net.UnixConn
+)
 
 func init() {
 	if notifySocketName := os.Getenv("NOTIFY_SOCKET"); notifySocketName != "" {
-		socket = &net.UnixAddr{
+		socket.Store(&net.UnixAddr{
 			Name: notifySocketName,
 			Net:  "unixgram",
-		}
+		})
 	}
 }
 
 // IsEnabled tells if systemd notify socket has been detected or not.
 func IsEnabled() bool {
-	return socket != nil
+	return socket.Load() != nil
 }
 
 // Ready sends systemd notify READY=1
@@ -37,9 +51,27 @@ func Stopping() error {
 	return Send("STOPPING=1")
 }
 
+// StoppingWithExitStatus sends systemd notify STOPPING=1 along with EXIT_STATUS=%d{code}
+// in a single message, so the intended exit status is known as soon as the stop begins.
+func StoppingWithExitStatus(code int) error {
+	return Send(fmt.Sprintf("STOPPING=1\nEXIT_STATUS=%d", code))
+}
+
+// ExitStatus sends systemd notify EXIT_STATUS=%d{code}
+// It reports the exit status the service is about to terminate with, systemd records it
+// in the unit's ExecMainStatus for failure diagnostics.
+func ExitStatus(code int) error {
+	return Send(fmt.Sprintf("EXIT_STATUS=%d", code))
+}
+
 // Status sends systemd notify STATUS=%s{status}
+// status is escaped with EscapeValue, an ErrInvalidValue error is returned if it is not valid UTF-8.
 func Status(status string) error {
-	return Send(fmt.Sprintf("STATUS=%s", status))
+	state, err := statusState(status)
+	if err != nil {
+		return err
+	}
+	return Send(state)
 }
 
 // ErrNo sends systemd notify ERRNO=%d{errno}
@@ -48,8 +80,13 @@ func ErrNo(errno int) error {
 }
 
 // BusError sends systemd notify BUSERROR=%s{buserror}
+// An ErrInvalidValue error is returned if buserror is empty or contains spaces or control characters.
 func BusError(buserror string) error {
-	return Send(fmt.Sprintf("BUSERROR=%s", buserror))
+	state, err := busErrorState(buserror)
+	if err != nil {
+		return err
+	}
+	return Send(state)
 }
 
 // MainPID sends systemd notify MAINPID=%d{mainpid}
@@ -62,25 +99,80 @@ func WatchDog() error {
 	return Send("WATCHDOG=1")
 }
 
+// WatchDogTrigger sends systemd notify WATCHDOG=trigger
+// It makes systemd act as if the watchdog timeout expired right away (systemd v243 or later).
+func WatchDogTrigger() error {
+	return Send("WATCHDOG=trigger")
+}
+
 // WatchDogUSec sends systemd notify WATCHDOG_USEC=%d{µsec}
 func WatchDogUSec(usec int64) error {
 	return Send(fmt.Sprintf("WATCHDOG_USEC=%d", usec))
 }
 
+// ExtendTimeout sends systemd notify EXTEND_TIMEOUT_USEC=%d{µsec}
+// It asks systemd to extend the current start, runtime or stop timeout to now + timeout.
+// It must be sent again before that new timeout expires to keep extending it (systemd v236 or later).
+func ExtendTimeout(timeout time.Duration) error {
+	return Send(fmt.Sprintf("EXTEND_TIMEOUT_USEC=%d", timeout.Microseconds()))
+}
+
 // Send state thru the notify socket if any.
 // If the notify socket was not detected, it is a noop call.
 // Use IsEnabled() to determine if the notify socket has been detected.
+// Transient errors are retried according to the current RetryPolicy.
+// States containing a NUL byte are rejected with an ErrInvalidValue error.
 func Send(state string) error {
-	if socket == nil {
+	if !IsEnabled() {
 		return nil
 	}
-	conn, err := net.DialUnix(socket.Net, nil, socket)
-	if err != nil {
-		return fmt.Errorf("can't open unix socket: %v", err)
+	if err := validateState(state); err != nil {
+		return err
+	}
+	sendMu.Lock()
+	err := sendWithRetry(state, nil)
+	sendMu.Unlock()
+	callHook(state, err)
+	return err
+}
+
+// SendWithFDs sends state along with the given file descriptors (SCM_RIGHTS) thru the notify socket if any.
+// It is needed by states like FDSTORE=1 or BARRIER=1. The caller keeps ownership of the fds.
+// States carrying fds are retried but never queued for later delivery.
+func SendWithFDs(state string, fds ...int) error {
+	if !IsEnabled() {
+		return nil
+	}
+	if err := validateState(state); err != nil {
+		return err
+	}
+	sendMu.Lock()
+	err := sendWithRetry(state, fds)
+	sendMu.Unlock()
+	callHook(state, err)
+	return err
+}
+
+// write must be called with sendMu held.
+func write(state string, fds []int) (err error) {
+	addr := socket.Load()
+	if conn == nil {
+		// an unconnected (autobound) socket is needed to send ancillary data on a datagram socket,
+		// it also allows to follow the notify socket if systemd recreates it (daemon-reexec)
+		if conn, err = net.ListenUnixgram(addr.Net, &net.UnixAddr{Net: addr.Net}); err != nil {
+			conn = nil
+			return fmt.Errorf("can't open unix socket: %w", err)
+		}
+	}
+	var oob []byte
+	if len(fds) > 0 {
+		oob = syscall.UnixRights(fds...)
 	}
-	defer conn.Close()
-	if _, err = conn.Write([]byte(state)); err != nil {
-		return fmt.Errorf("can't write into the unix socket: %v", err)
+	if _, _, err = conn.WriteMsgUnix([]byte(state), oob, addr); err != nil {
+		// start over with a fresh socket next time
+		conn.Close()
+		conn = nil
+		return fmt.Errorf("can't write into the unix socket: %w", err)
 	}
 	return nil
 }
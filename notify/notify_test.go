@@ -0,0 +1,236 @@
+package sysdnotify
+
+import (
+	"errors"
+	"net"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func listen(t *testing.T, path string) *net.UnixConn {
+	t.Helper()
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func receive(t *testing.T, conn *net.UnixConn) string {
+	t.Helper()
+	buf := make([]byte, 4096)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(buf[:n])
+}
+
+func setSocket(t *testing.T, path string) {
+	t.Helper()
+	socket.Store(&net.UnixAddr{Name: path, Net: "unixgram"})
+	t.Cleanup(func() {
+		socket.Store(nil)
+		SetRetryPolicy(DefaultRetryPolicy)
+	})
+}
+
+func TestSend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notify")
+	conn := listen(t, path)
+	setSocket(t, path)
+	if err := Ready(); err != nil {
+		t.Fatal(err)
+	}
+	if state := receive(t, conn); state != "READY=1" {
+		t.Error("unexpected state", state)
+	}
+}
+
+func TestExitStatus(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notify")
+	conn := listen(t, path)
+	setSocket(t, path)
+	if err := ExitStatus(3); err != nil {
+		t.Fatal(err)
+	}
+	if state := receive(t, conn); state != "EXIT_STATUS=3" {
+		t.Error("unexpected state", state)
+	}
+	if err := StoppingWithExitStatus(1); err != nil {
+		t.Fatal(err)
+	}
+	if state := receive(t, conn); state != "STOPPING=1\nEXIT_STATUS=1" {
+		t.Error("unexpected state", state)
+	}
+}
+
+func TestSendQueuesCriticalStates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notify")
+	setSocket(t, path)
+	SetRetryPolicy(RetryPolicy{Attempts: 2, Backoff: time.Millisecond, QueueSize: 2})
+	if err := Ready(); err == nil || !IsTransient(err) {
+		t.Fatal("expected a transient error, got", err)
+	}
+	if err := Status("not critical"); err == nil {
+		t.Fatal("expected an error")
+	}
+	if pending := Pending(); len(pending) != 1 || pending[0] != "READY=1" {
+		t.Fatal("unexpected pending states", pending)
+	}
+	conn := listen(t, path)
+	if err := Status("up"); err != nil {
+		t.Fatal(err)
+	}
+	if state := receive(t, conn); state != "READY=1" {
+		t.Error("expected queued state first, got", state)
+	}
+	if state := receive(t, conn); state != "STATUS=up" {
+		t.Error("unexpected state", state)
+	}
+	if pending := Pending(); len(pending) != 0 {
+		t.Error("queue should be empty", pending)
+	}
+}
+
+func TestAsyncNotifierCoalescesStatus(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notify")
+	conn := listen(t, path)
+	setSocket(t, path)
+	n := NewAsyncNotifier(4)
+	// hold the send lock so nothing leaves the queue while we fill it
+	sendMu.Lock()
+	for _, state := range []string{"STATUS=a", "READY=1", "STATUS=b", "STATUS=c"} {
+		if err := n.Send(state); err != nil {
+			sendMu.Unlock()
+			t.Fatal(err)
+		}
+	}
+	sendMu.Unlock()
+	if err := n.Close(); err != nil {
+		t.Fatal(err)
+	}
+	var states []string
+	buf := make([]byte, 4096)
+	for {
+		conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+		n, err := conn.Read(buf)
+		if err != nil {
+			break
+		}
+		states = append(states, string(buf[:n]))
+	}
+	for _, state := range states {
+		if state == "STATUS=b" {
+			t.Error("STATUS=b should have been coalesced", states)
+		}
+	}
+	if len(states) == 0 || states[len(states)-1] != "STATUS=c" {
+		t.Error("expected the latest status to be sent last, got", states)
+	}
+	if err := n.Send("READY=1"); err != ErrClosed {
+		t.Error("expected ErrClosed, got", err)
+	}
+}
+
+func TestChildEnv(t *testing.T) {
+	setSocket(t, "/run/test/notify")
+	env := []string{"PATH=/bin", "NOTIFY_SOCKET=/old", "WATCHDOG_USEC=1000"}
+	if child := ChildEnv(env, false); len(child) != 1 || child[0] != "PATH=/bin" {
+		t.Error("unexpected env", child)
+	}
+	if child := ChildEnv(env, true); len(child) != 2 || child[1] != "NOTIFY_SOCKET=/run/test/notify" {
+		t.Error("unexpected env", child)
+	}
+}
+
+func TestHandOverMainPID(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notify")
+	conn := listen(t, path)
+	setSocket(t, path)
+	go func() {
+		buf := make([]byte, 4096)
+		oob := make([]byte, syscall.CmsgSpace(4))
+		for {
+			_, oobn, _, _, err := conn.ReadMsgUnix(buf, oob)
+			if err != nil {
+				return
+			}
+			msgs, _ := syscall.ParseSocketControlMessage(oob[:oobn])
+			for _, msg := range msgs {
+				fds, _ := syscall.ParseUnixRights(&msg)
+				for _, fd := range fds {
+					syscall.Close(fd)
+				}
+			}
+		}
+	}()
+	if err := HandOverMainPID(42, time.Second); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHook(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notify")
+	listen(t, path)
+	setSocket(t, path)
+	var got string
+	SetHook(func(state string, err error) {
+		if err != nil {
+			t.Error(err)
+		}
+		got = state
+	})
+	defer SetHook(nil)
+	if err := Stopping(); err != nil {
+		t.Fatal(err)
+	}
+	if got != "STOPPING=1" {
+		t.Error("hook not called with the sent state", got)
+	}
+}
+
+func TestConcurrentSend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notify")
+	conn := listen(t, path)
+	setSocket(t, path)
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := WatchDog(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	for range 10 {
+		if state := receive(t, conn); state != "WATCHDOG=1" {
+			t.Error("unexpected state", state)
+		}
+	}
+}
+
+func TestStatusSanitization(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notify")
+	conn := listen(t, path)
+	setSocket(t, path)
+	if err := Status("line1\r\nline2\tend"); err != nil {
+		t.Fatal(err)
+	}
+	if state := receive(t, conn); state != `STATUS=line1\nline2\x09end` {
+		t.Error("unexpected state", state)
+	}
+	if err := Status("bad \xff"); !errors.Is(err, ErrInvalidValue) {
+		t.Error("expected ErrInvalidValue, got", err)
+	}
+	if err := BusError("org.freedesktop.DBus.Error.Failed\nREADY=1"); !errors.Is(err, ErrInvalidValue) {
+		t.Error("expected ErrInvalidValue, got", err)
+	}
+}
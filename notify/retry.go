@@ -0,0 +1,126 @@
+package sysdnotify
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how Send deals with transient notify socket errors
+// (ENOBUFS, EAGAIN, or the socket being briefly unavailable during a daemon-reexec).
+type RetryPolicy struct {
+	// Attempts is the maximum number of tries for a single state, 1 (or less) disables retries.
+	Attempts int
+	// Backoff is the wait before the first retry, it is doubled after each failed attempt.
+	Backoff time.Duration
+	// MaxBackoff caps the wait between two attempts.
+	MaxBackoff time.Duration
+	// QueueSize is the number of critical states (READY=1, WATCHDOG=1, etc...) kept aside
+	// when every attempt failed. They are sent again, in order, before the next state.
+	// When the queue is full the oldest state is dropped. 0 disables the queue.
+	QueueSize int
+}
+
+// DefaultRetryPolicy is the policy used by Send unless SetRetryPolicy() is called.
+var DefaultRetryPolicy = RetryPolicy{
+	Attempts:   3,
+	Backoff:    5 * time.Millisecond,
+	MaxBackoff: 50 * time.Millisecond,
+	QueueSize:  8,
+}
+
+var (
+	sendMu  sync.Mutex
+	policy  = DefaultRetryPolicy
+	pending []string
+)
+
+// SetRetryPolicy replaces the retry policy used by Send.
+// Critical states already queued beyond the new QueueSize are dropped, oldest first.
+func SetRetryPolicy(p RetryPolicy) {
+	sendMu.Lock()
+	defer sendMu.Unlock()
+	policy = p
+	if p.QueueSize <= 0 {
+		pending = nil
+	} else if len(pending) > p.QueueSize {
+		pending = pending[len(pending)-p.QueueSize:]
+	}
+}
+
+// Pending returns the critical states which could not be delivered yet.
+func Pending() []string {
+	sendMu.Lock()
+	defer sendMu.Unlock()
+	return append([]string(nil), pending...)
+}
+
+// sendWithRetry must be called with sendMu held.
+func sendWithRetry(state string, fds []int) (err error) {
+	// flush what previous calls could not deliver first to keep ordering
+	for len(pending) > 0 {
+		if err = tryWrite(pending[0], nil); err != nil {
+			if fds == nil {
+				enqueue(state)
+			}
+			return
+		}
+		pending = pending[1:]
+	}
+	if err = tryWrite(state, fds); err != nil && fds == nil {
+		enqueue(state)
+	}
+	return
+}
+
+func tryWrite(state string, fds []int) (err error) {
+	backoff := policy.Backoff
+	for attempt := 1; ; attempt++ {
+		if err = write(state, fds); err == nil || !IsTransient(err) || attempt >= policy.Attempts {
+			return
+		}
+		time.Sleep(backoff)
+		if backoff *= 2; policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
+
+func enqueue(state string) {
+	if policy.QueueSize <= 0 || !isCritical(state) {
+		return
+	}
+	for _, p := range pending {
+		if p == state {
+			return
+		}
+	}
+	if len(pending) >= policy.QueueSize {
+		pending = pending[len(pending)-policy.QueueSize+1:]
+	}
+	pending = append(pending, state)
+}
+
+// IsTransient tells if err is a notify socket error which is worth retrying.
+func IsTransient(err error) bool {
+	return errors.Is(err, syscall.ENOBUFS) ||
+		errors.Is(err, syscall.EAGAIN) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ENOENT)
+}
+
+func isCritical(state string) bool {
+	for _, line := range strings.Split(state, "\n") {
+		switch {
+		case line == "READY=1",
+			line == "RELOADING=1",
+			line == "STOPPING=1",
+			line == "WATCHDOG=1",
+			strings.HasPrefix(line, "MAINPID="):
+			return true
+		}
+	}
+	return false
+}
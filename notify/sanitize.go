@@ -0,0 +1,65 @@
+package sysdnotify
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidValue is returned when a value can't be safely carried by a notify state.
+var ErrInvalidValue = errors.New("invalid notify value")
+
+// EscapeValue makes s safe to be used as a single line notify value:
+// newlines are turned into a literal \n, other control characters into \xHH.
+// It does not fix invalid UTF-8, use ValidateValue for that.
+func EscapeValue(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\r' && i+1 < len(s) && s[i+1] == '\n':
+			// \r\n is a single line break
+		case c == '\n', c == '\r':
+			b.WriteString(`\n`)
+		case c < 0x20 || c == 0x7f:
+			fmt.Fprintf(&b, `\x%02x`, c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// ValidateValue returns an ErrInvalidValue error if s can't be carried by a notify state
+// even after being escaped, which is the case of invalid UTF-8.
+func ValidateValue(s string) error {
+	if !utf8.ValidString(s) {
+		return fmt.Errorf("%w: %q is not valid UTF-8", ErrInvalidValue, s)
+	}
+	return nil
+}
+
+func statusState(status string) (string, error) {
+	if err := ValidateValue(status); err != nil {
+		return "", err
+	}
+	return "STATUS=" + EscapeValue(status), nil
+}
+
+func busErrorState(buserror string) (string, error) {
+	if buserror == "" || strings.ContainsFunc(buserror, func(r rune) bool {
+		return r <= ' ' || r == 0x7f || r == utf8.RuneError
+	}) {
+		return "", fmt.Errorf("%w: %q is not a D-Bus error name", ErrInvalidValue, buserror)
+	}
+	return "BUSERROR=" + buserror, nil
+}
+
+func validateState(state string) error {
+	if strings.IndexByte(state, 0) >= 0 {
+		return fmt.Errorf("%w: state contains a NUL byte", ErrInvalidValue)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package sysdwatchdog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CheckFunc is a health check: it must return a non nil error if the subsystem it covers is not healthy.
+// It must honor ctx as a check which does not return before the deadline is considered failed.
+type CheckFunc func(ctx context.Context) error
+
+// RegisterCheck registers (or replaces) the health check named name.
+// Once at least one check is registered, Run only sends heartbeats when every check passes
+// within the checks budget, so a deadlocked subsystem leads systemd to restart the service.
+func (c *WatchDog) RegisterCheck(name string, fn CheckFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.healthChecks == nil {
+		c.healthChecks = make(map[string]CheckFunc)
+	}
+	c.healthChecks[name] = fn
+}
+
+// UnregisterCheck removes the health check named name.
+func (c *WatchDog) UnregisterCheck(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.healthChecks, name)
+}
+
+// CheckError is returned when one or more health checks or liveness tokens failed.
+// Checks and tokens have their own name spaces, so a check and a token may share a name.
+type CheckError struct {
+	Checks map[string]error // errors of the failed health checks, by check name
+	Tokens map[string]error // errors of the stale liveness tokens, by token name
+}
+
+func (e CheckError) Error() string {
+	errs := make([]error, 0, len(e.Checks)+len(e.Tokens))
+	for name, err := range e.Checks {
+		errs = append(errs, fmt.Errorf("check %s: %w", name, err))
+	}
+	for name, err := range e.Tokens {
+		errs = append(errs, fmt.Errorf("token %s: %w", name, err))
+	}
+	return fmt.Sprintf("health checks failed: %v", errors.Join(errs...))
+}
+
+// Unwrap returns the errors of the failed checks and tokens.
+func (e CheckError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Checks)+len(e.Tokens))
+	for _, err := range e.Checks {
+		errs = append(errs, err)
+	}
+	for _, err := range e.Tokens {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+func (e CheckError) failed() bool {
+	return len(e.Checks) > 0 || len(e.Tokens) > 0
+}
+
+// RunChecks runs every registered health check concurrently, each one within the checks budget
+// (see checksBudget), and verifies every liveness token is fresh (see NewToken).
+// It returns a CheckError if any of them failed.
+func (c *WatchDog) RunChecks(ctx context.Context) error {
+	c.mu.RLock()
+	checks := make(map[string]CheckFunc, len(c.healthChecks))
+	for name, fn := range c.healthChecks {
+		checks[name] = fn
+	}
+	deadline := c.checksBudget()
+	failed := CheckError{
+		Checks: make(map[string]error),
+		Tokens: make(map[string]error),
+	}
+	for token := range c.tokens {
+		if err := token.check(); err != nil {
+			// several workers may share a token name
+			failed.Tokens[token.name] = errors.Join(failed.Tokens[token.name], err)
+		}
+	}
+	c.mu.RUnlock()
+	if len(checks) == 0 {
+		if failed.failed() {
+			return failed
+		}
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, deadline)
+	defer cancel()
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for name, fn := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			done := make(chan error, 1)
+			go func() { done <- fn(ctx) }()
+			var err error
+			select {
+			case err = <-done:
+			case <-ctx.Done():
+				// do not wait for a stuck check
+				err = ctx.Err()
+			}
+			if err != nil {
+				mu.Lock()
+				failed.Checks[name] = err
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if failed.failed() {
+		return failed
+	}
+	return nil
+}
+
+// checksBudget returns the time given to the health checks: the checks duration minus a margin
+// of a quarter of it, shortened so they also end that margin before the watchdog deadline.
+// The margin leaves time to send the heartbeat before the next tick and before systemd gives up.
+// It must be called with c.mu held.
+func (c *WatchDog) checksBudget() time.Duration {
+	margin := c.checks / 4
+	budget := c.checks - margin
+	if untilDeadline := c.interval - time.Since(c.lastBeat) - margin; untilDeadline < budget {
+		budget = untilDeadline
+	}
+	// past the deadline already, the checks still get a chance
+	return max(budget, margin)
+}
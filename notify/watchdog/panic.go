@@ -0,0 +1,75 @@
+package sysdwatchdog
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	sysdnotify "github.com/iguanesolutions/go-systemd/v6/notify"
+)
+
+// PanicError is the failure recorded when a goroutine started with Go panics.
+type PanicError struct {
+	Value any    // value passed to panic
+	Stack []byte // stack of the panicking goroutine
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Go runs fn in a new goroutine. If fn panics, the panic is recovered and reported to Fail,
+// which stops the heartbeats: the service is restarted by systemd instead of running half broken.
+func (c *WatchDog) Go(name string, fn func()) {
+	go func() {
+		defer func() {
+			if v := recover(); v != nil {
+				c.Fail(name, &PanicError{
+					Value: v,
+					Stack: debug.Stack(),
+				})
+			}
+		}()
+		fn()
+	}()
+}
+
+// Section opens a section named name which must be closed, by calling done, within deadline.
+// If it is not, the section is reported to Fail, which converts a silent hang into a clean restart.
+func (c *WatchDog) Section(name string, deadline time.Duration) (done func()) {
+	timer := time.AfterFunc(deadline, func() {
+		c.Fail(name, fmt.Errorf("section deadline of %s exceeded: %w", deadline, context.DeadlineExceeded))
+	})
+	return func() {
+		timer.Stop()
+	}
+}
+
+// Fail marks the WatchDog as failed: Run stops sending heartbeats for good, the failure hook is
+// called and, if configured with WithTriggerOnFailure, WATCHDOG=trigger is sent.
+// Only the first failure is recorded.
+func (c *WatchDog) Fail(name string, err error) {
+	c.mu.Lock()
+	first := c.failure == nil
+	if first {
+		c.failure = fmt.Errorf("%s: %w", name, err)
+	}
+	c.mu.Unlock()
+	if !first {
+		return
+	}
+	if c.onFailure != nil {
+		c.onFailure(name, err)
+	}
+	if c.trigger && !c.software {
+		_ = sysdnotify.WatchDogTrigger()
+	}
+}
+
+// Failure returns the failure recorded by Fail, if any.
+func (c *WatchDog) Failure() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.failure
+}
@@ -0,0 +1,82 @@
+package sysdwatchdog
+
+import (
+	"context"
+	"sync"
+)
+
+// Pause stops the heartbeats sent by Run, whatever the health checks and maintenance windows say.
+// If Resume is not called before the watchdog limit expires, systemd restarts the service:
+// use it to intentionally allow a restart (eg: during an operator driven maintenance).
+func (c *WatchDog) Pause() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.paused = true
+}
+
+// Resume resumes the heartbeats stopped by Pause.
+func (c *WatchDog) Resume() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.paused = false
+}
+
+// Paused tells if the heartbeats are currently paused.
+func (c *WatchDog) Paused() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.paused
+}
+
+// WithMaintenanceWindow opens a maintenance window which lasts as long as the returned context:
+// until cancel is called, ctx is done or Run returns. During the window Run keeps sending heartbeats
+// without running the health checks, so a known-long blocking operation does not trigger a restart.
+// Give ctx a deadline: once it expires the window closes and a still blocked service is restarted
+// as usual. Pause takes precedence over maintenance windows.
+func (c *WatchDog) WithMaintenanceWindow(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	c.mu.Lock()
+	c.maintenance++
+	stopped := c.runStopped()
+	c.mu.Unlock()
+	var once sync.Once
+	closeWindow := func() {
+		// the window is closed before ctx is cancelled, so it is over once ctx is done
+		once.Do(func() {
+			c.mu.Lock()
+			c.maintenance--
+			c.mu.Unlock()
+		})
+		cancel()
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-stopped:
+		}
+		closeWindow()
+	}()
+	return ctx, closeWindow
+}
+
+// runStopped returns the channel closed when Run returns, c.mu must be held.
+func (c *WatchDog) runStopped() chan struct{} {
+	if c.stopped == nil {
+		c.stopped = make(chan struct{})
+	}
+	return c.stopped
+}
+
+// stopRun closes the maintenance windows tied to the returning Run.
+func (c *WatchDog) stopRun() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	close(c.runStopped())
+	c.stopped = nil
+}
+
+func (c *WatchDog) state() (paused, maintenance bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.paused || c.failure != nil, c.maintenance > 0 || len(c.phases) > 0
+}
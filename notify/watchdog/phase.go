@@ -0,0 +1,65 @@
+package sysdwatchdog
+
+import (
+	"context"
+	"sync"
+
+	sysdnotify "github.com/iguanesolutions/go-systemd/v6/notify"
+)
+
+type phase struct {
+	name string
+}
+
+// BeginPhase declares a slow phase (eg: "starting", "stopping") which may legitimately exceed
+// TimeoutStartSec=, TimeoutStopSec= or WatchdogSec=. While the phase is active, Run extends the
+// current systemd timeout by the watchdog limit at each tick (EXTEND_TIMEOUT_USEC) and keeps
+// sending heartbeats without running the health checks, like a maintenance window does.
+// The phase ends when end is called or when ctx is done: give ctx a deadline to bound the phase.
+func (c *WatchDog) BeginPhase(ctx context.Context, name string) (end func()) {
+	p := &phase{name: name}
+	ctx, cancel := context.WithCancel(ctx)
+	c.mu.Lock()
+	if c.phases == nil {
+		c.phases = make(map[*phase]struct{})
+	}
+	c.phases[p] = struct{}{}
+	c.mu.Unlock()
+	// extend right away, the next tick may come too late
+	_ = sysdnotify.ExtendTimeout(c.GetLimitDuration())
+	var once sync.Once
+	end = func() {
+		once.Do(func() {
+			cancel()
+			c.mu.Lock()
+			delete(c.phases, p)
+			c.mu.Unlock()
+		})
+	}
+	go func() {
+		<-ctx.Done()
+		end()
+	}()
+	return end
+}
+
+// Phases returns the names of the active phases.
+func (c *WatchDog) Phases() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	names := make([]string, 0, len(c.phases))
+	for p := range c.phases {
+		names = append(names, p.name)
+	}
+	return names
+}
+
+func (c *WatchDog) extendTimeout() {
+	c.mu.RLock()
+	active := len(c.phases) > 0
+	limit := c.interval
+	c.mu.RUnlock()
+	if active {
+		_ = sysdnotify.ExtendTimeout(limit)
+	}
+}
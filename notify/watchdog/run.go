@@ -0,0 +1,166 @@
+package sysdwatchdog
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// HeartbeatEvent describes a heartbeat attempt made by Run, see WithHeartbeatHook.
+type HeartbeatEvent struct {
+	Time          time.Time     // when the attempt started
+	ChecksLatency time.Duration // time spent running the health checks (0 during maintenance)
+	SendLatency   time.Duration // time spent sending the heartbeat
+	Err           error         // send error, nil on success
+}
+
+// Run sends heartbeats at the checks duration pace until ctx is done.
+// Before each heartbeat every registered health check must pass (see RegisterCheck),
+// otherwise the heartbeat is skipped and systemd restarts the service once the limit is reached.
+// Send failures are reported to the heartbeat hook (see WithHeartbeatHook) and by LastHeartbeatError.
+// See Pause, WithMaintenanceWindow and BeginPhase to alter this behavior.
+func (c *WatchDog) Run(ctx context.Context) {
+	defer c.stopRun()
+	checks := c.GetChecksDuration()
+	ticker := time.NewTicker(checks)
+	defer ticker.Stop()
+	// armed at lastBeat+threshold and fired from its own goroutine, so a slow beat does not delay it
+	missed := time.AfterFunc(c.missedThreshold()-c.TimeSinceLastHeartbeat(), c.signalMissed)
+	defer missed.Stop()
+	// follow the durations changed by SetLimitDuration
+	follow := func() {
+		if current := c.GetChecksDuration(); current != checks {
+			checks = current
+			ticker.Reset(checks)
+			missed.Reset(c.missedThreshold() - c.TimeSinceLastHeartbeat())
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.limitChanged:
+			// a shortened limit can not wait for the next tick of the old period
+			follow()
+		case <-ticker.C:
+			follow()
+			c.extendTimeout()
+			if c.beat(ctx) {
+				missed.Reset(c.missedThreshold())
+			}
+			c.checkSoftware()
+		}
+	}
+}
+
+// missedThreshold returns the duration without heartbeat after which Missed() fires.
+func (c *WatchDog) missedThreshold() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.threshold > 0 {
+		return c.threshold
+	}
+	return c.checks + (c.interval-c.checks)/2
+}
+
+// signalMissed signals Missed(), the timer calling it is re-armed by the next heartbeat
+// so it fires once per missed heartbeat.
+func (c *WatchDog) signalMissed() {
+	select {
+	case c.missed <- c.TimeSinceLastHeartbeat():
+	default:
+	}
+}
+
+// checkSoftware calls onMissed, in software mode, once per limit elapsed without heartbeat.
+func (c *WatchDog) checkSoftware() {
+	if !c.software {
+		return
+	}
+	c.mu.Lock()
+	now := time.Now()
+	soft := now.Sub(c.lastBeat) > c.interval && now.Sub(c.missedAt) > c.interval
+	if soft {
+		c.missedAt = now
+	}
+	c.mu.Unlock()
+	if soft {
+		c.onMissed()
+	}
+}
+
+// beat sends a heartbeat if the health checks pass, it returns true if it has been sent.
+func (c *WatchDog) beat(ctx context.Context) bool {
+	paused, maintenance := c.state()
+	if paused {
+		return false
+	}
+	event := HeartbeatEvent{
+		Time: time.Now(),
+	}
+	if !maintenance {
+		err := c.RunChecks(ctx)
+		event.ChecksLatency = time.Since(event.Time)
+		if err != nil {
+			c.checksFailed(err)
+			return false
+		}
+	}
+	start := time.Now()
+	event.Err = c.SendHeartbeat()
+	event.SendLatency = time.Since(start)
+	c.mu.Lock()
+	if c.sendErr = event.Err; event.Err == nil {
+		c.lastBeat = time.Now()
+	}
+	c.mu.Unlock()
+	if c.onHeartbeat != nil {
+		c.onHeartbeat(event)
+	}
+	return event.Err == nil
+}
+
+func (c *WatchDog) checksFailed(err error) {
+	if c.onCheckFail == nil {
+		return
+	}
+	var checkErr CheckError
+	if !errors.As(err, &checkErr) {
+		c.onCheckFail("", err)
+		return
+	}
+	for name, err := range checkErr.Checks {
+		c.onCheckFail(name, err)
+	}
+	for name, err := range checkErr.Tokens {
+		c.onCheckFail(name, err)
+	}
+}
+
+// LastHeartbeat returns when the last heartbeat was successfully sent by Run,
+// or when the WatchDog was created if none has been sent yet.
+func (c *WatchDog) LastHeartbeat() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.lastBeat
+}
+
+// LastHeartbeatError returns the error of the last heartbeat sent by Run, nil if it succeeded
+// or if none has been sent yet.
+func (c *WatchDog) LastHeartbeatError() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.sendErr
+}
+
+// TimeSinceLastHeartbeat returns the time elapsed since LastHeartbeat.
+func (c *WatchDog) TimeSinceLastHeartbeat() time.Duration {
+	return time.Since(c.LastHeartbeat())
+}
+
+// Missed returns a channel receiving the time elapsed since the last heartbeat each time Run
+// did not manage to send one within the missed threshold (see WithMissedThreshold).
+// It fires once per missed heartbeat, giving an early warning before systemd's deadline expires.
+func (c *WatchDog) Missed() <-chan time.Duration {
+	return c.missed
+}
@@ -0,0 +1,66 @@
+package sysdwatchdog
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// ErrStaleToken is reported (thru CheckError) for liveness tokens which have not been pinged in time.
+var ErrStaleToken = errors.New("liveness token not pinged in time")
+
+// Token is a liveness token owned by a worker goroutine. The worker must Ping it at least once
+// per max age: as long as one token is stale, Run does not send heartbeats, so a single stuck
+// worker leads to a systemd driven restart.
+type Token struct {
+	wd     *WatchDog
+	name   string
+	maxAge time.Duration
+	last   atomic.Int64 // unix nano
+}
+
+// NewToken registers and returns a new liveness token named name, considered fresh at creation.
+// maxAge is the maximum duration between two pings, the watchdog limit is used if it is 0 or less.
+func (c *WatchDog) NewToken(name string, maxAge time.Duration) *Token {
+	if maxAge <= 0 {
+		maxAge = c.GetLimitDuration()
+	}
+	t := &Token{
+		wd:     c,
+		name:   name,
+		maxAge: maxAge,
+	}
+	t.Ping()
+	c.mu.Lock()
+	if c.tokens == nil {
+		c.tokens = make(map[*Token]struct{})
+	}
+	c.tokens[t] = struct{}{}
+	c.mu.Unlock()
+	return t
+}
+
+// Name returns the name of the token.
+func (t *Token) Name() string {
+	return t.name
+}
+
+// Ping marks the token as fresh, it is cheap enough to be called from hot loops.
+func (t *Token) Ping() {
+	t.last.Store(time.Now().UnixNano())
+}
+
+// Release unregisters the token, to be called when its worker exits normally.
+func (t *Token) Release() {
+	t.wd.mu.Lock()
+	defer t.wd.mu.Unlock()
+	delete(t.wd.tokens, t)
+}
+
+func (t *Token) check() error {
+	if age := time.Since(time.Unix(0, t.last.Load())); age > t.maxAge {
+		return fmt.Errorf("%w: last ping %s ago", ErrStaleToken, age.Truncate(time.Millisecond))
+	}
+	return nil
+}
@@ -3,8 +3,7 @@ package sysdwatchdog
 import (
 	"errors"
 	"fmt"
-	"os"
-	"strconv"
+	"sync"
 	"time"
 
 	sysdnotify "github.com/iguanesolutions/go-systemd/v6/notify"
@@ -12,61 +11,241 @@ import (
 
 // WatchDog is an interface to the systemd watchdog mechanism
 type WatchDog struct {
-	interval time.Duration
-	checks   time.Duration
+	mu           sync.RWMutex
+	interval     time.Duration
+	checks       time.Duration
+	fraction     float64
+	fixedChecks  time.Duration
+	limit        time.Duration
+	unsetEnv     bool
+	transport    func() error
+	healthChecks map[string]CheckFunc
+	paused       bool
+	maintenance  int
+	stopped      chan struct{}
+	phases       map[*phase]struct{}
+	tokens       map[*Token]struct{}
+	onHeartbeat  func(HeartbeatEvent)
+	onCheckFail  func(name string, err error)
+	softLimit    time.Duration
+	onMissed     func()
+	software     bool
+	lastBeat     time.Time
+	sendErr      error
+	missedAt     time.Time
+	threshold    time.Duration
+	missed       chan time.Duration
+	limitChanged chan struct{}
+	failure      error
+	onFailure    func(name string, err error)
+	trigger      bool
 }
 
-// New returns an initialized and ready to use WatchDog
-func New() (wd *WatchDog, err error) {
-	// Check WatchDog is supported and usable
-	interval, err := getWatchDogInterval()
-	if err != nil {
-		return
+// DefaultHeartbeatFraction is the fraction of the watchdog limit used as checks duration by default.
+const DefaultHeartbeatFraction = 0.5
+
+// Option configures a WatchDog, see New.
+type Option func(wd *WatchDog) error
+
+// WithHeartbeatFraction sets the checks duration (heartbeat cadence) as a fraction of the watchdog limit.
+// fraction must be greater than 0 and lower than 1, default is DefaultHeartbeatFraction.
+func WithHeartbeatFraction(fraction float64) Option {
+	return func(wd *WatchDog) error {
+		if fraction <= 0 || fraction >= 1 {
+			return fmt.Errorf("heartbeat fraction must be in ]0, 1[: %v", fraction)
+		}
+		wd.fraction = fraction
+		wd.fixedChecks = 0
+		return nil
 	}
-	// Return the initialized controller
-	wd = &WatchDog{
-		interval: interval,
-		checks:   interval / 2,
+}
+
+// WithHeartbeatInterval sets an absolute checks duration (heartbeat cadence).
+// It must be lower than the watchdog limit.
+func WithHeartbeatInterval(interval time.Duration) Option {
+	return func(wd *WatchDog) error {
+		if interval <= 0 {
+			return fmt.Errorf("heartbeat interval must be positive: %s", interval)
+		}
+		wd.fixedChecks = interval
+		return nil
 	}
-	return
 }
 
-// based on https://github.com/coreos/go-systemd/blob/master/daemon/watchdog.go
-func getWatchDogInterval() (interval time.Duration, err error) {
-	// WATCHDOG_USEC
-	wusec := os.Getenv("WATCHDOG_USEC")
-	if wusec == "" {
-		err = errors.New("watchdog does not seem to be enabled: WATCHDOG_USEC env unset")
-		return
+// WithLimit injects the watchdog limit instead of reading it from WATCHDOG_USEC and WATCHDOG_PID.
+// It is useful when the limit is known by other means (eg: configuration, tests).
+func WithLimit(limit time.Duration) Option {
+	return func(wd *WatchDog) error {
+		if limit <= 0 {
+			return fmt.Errorf("watchdog limit must be positive: %s", limit)
+		}
+		wd.limit = limit
+		return nil
+	}
+}
+
+// WithUnsetEnv removes WATCHDOG_USEC and WATCHDOG_PID from the environment once read,
+// so they do not leak to child processes (see sysdnotify.WatchDogEnabled).
+func WithUnsetEnv() Option {
+	return func(wd *WatchDog) error {
+		wd.unsetEnv = true
+		return nil
+	}
+}
+
+// WithHeartbeatTransport replaces the way heartbeats are sent, which is sysdnotify.WatchDog() by default.
+func WithHeartbeatTransport(send func() error) Option {
+	return func(wd *WatchDog) error {
+		if send == nil {
+			return errors.New("heartbeat transport is nil")
+		}
+		wd.transport = send
+		return nil
+	}
+}
+
+// WithHeartbeatHook registers fn to be called after each heartbeat attempt made by Run,
+// fn must not block as it is called from the Run goroutine.
+func WithHeartbeatHook(fn func(HeartbeatEvent)) Option {
+	return func(wd *WatchDog) error {
+		wd.onHeartbeat = fn
+		return nil
+	}
+}
+
+// WithCheckFailedHook registers fn to be called for each failed health check and stale liveness
+// token during Run (use errors.Is with ErrStaleToken to tell them apart), each call means a heartbeat has been skipped. fn must not block as it is called from the Run goroutine.
+func WithCheckFailedHook(fn func(name string, err error)) Option {
+	return func(wd *WatchDog) error {
+		wd.onCheckFail = fn
+		return nil
+	}
+}
+
+// WithSoftwareMode makes the WatchDog usable outside of systemd: when the systemd watchdog
+// is not available (WATCHDOG_USEC unset, notify socket missing...), limit is used instead and
+// heartbeats are not sent anywhere. Run still executes the health checks and calls onMissed
+// (log, panic, os.Exit...) each time no heartbeat could be sent for limit, so the liveness logic
+// also protects binaries running without systemd. It has no effect when the systemd watchdog is available.
+func WithSoftwareMode(limit time.Duration, onMissed func()) Option {
+	return func(wd *WatchDog) error {
+		if limit <= 0 {
+			return fmt.Errorf("software watchdog limit must be positive: %s", limit)
+		}
+		if onMissed == nil {
+			return errors.New("software watchdog onMissed callback is nil")
+		}
+		wd.softLimit = limit
+		wd.onMissed = onMissed
+		return nil
+	}
+}
+
+// WithFailureHook registers fn to be called (eg: to log) when a goroutine started with Go panics
+// or a section opened with Section exceeds its deadline, see Fail.
+func WithFailureHook(fn func(name string, err error)) Option {
+	return func(wd *WatchDog) error {
+		wd.onFailure = fn
+		return nil
+	}
+}
+
+// WithTriggerOnFailure makes Fail send WATCHDOG=trigger so systemd restarts the service
+// right away instead of waiting for the watchdog limit to expire.
+func WithTriggerOnFailure() Option {
+	return func(wd *WatchDog) error {
+		wd.trigger = true
+		return nil
+	}
+}
+
+// WithMissedThreshold sets the duration without heartbeat after which Missed() fires.
+// It must be greater than the checks duration and lower than the watchdog limit, by default it is
+// half way between the two so applications get an early warning before systemd's deadline.
+func WithMissedThreshold(threshold time.Duration) Option {
+	return func(wd *WatchDog) error {
+		if threshold <= 0 {
+			return fmt.Errorf("missed threshold must be positive: %s", threshold)
+		}
+		wd.threshold = threshold
+		return nil
+	}
+}
+
+// New returns an initialized and ready to use WatchDog
+func New(opts ...Option) (wd *WatchDog, err error) {
+	w := &WatchDog{
+		fraction: DefaultHeartbeatFraction,
+	}
+	for _, opt := range opts {
+		if err = opt(w); err != nil {
+			return
+		}
+	}
+	// Check WatchDog is supported and usable
+	interval := w.limit
+	if w.transport == nil && !sysdnotify.IsEnabled() {
+		err = errors.New("watchdog can't be used: systemd notify is disabled")
+	} else if interval == 0 {
+		interval, err = getWatchDogInterval(w.unsetEnv)
 	}
-	wusecTyped, err := strconv.Atoi(wusec)
 	if err != nil {
-		err = fmt.Errorf("can't convert WATCHDOG_USEC as int: %s", err)
+		if w.softLimit == 0 {
+			return
+		}
+		// fallback to software mode
+		interval, err = w.softLimit, nil
+		w.software = true
+		w.transport = func() error { return nil }
+	}
+	// Return the initialized controller
+	if w.checks, err = w.checksDuration(interval); err != nil {
 		return
 	}
-	if wusecTyped <= 0 {
-		err = fmt.Errorf("WATCHDOG_USEC must be a positive number")
+	if w.threshold > 0 && (w.threshold <= w.checks || w.threshold >= interval) {
+		err = fmt.Errorf("missed threshold %s must be between the checks duration %s and the watchdog limit %s",
+			w.threshold, w.checks, interval)
 		return
 	}
-	interval = time.Duration(wusecTyped) * time.Microsecond
-	// WATCHDOG_PID
-	wpid := os.Getenv("WATCHDOG_PID")
-	if wpid == "" {
-		return // No WATCHDOG_PID: can't check if we are the one, let's go with it
+	w.interval = interval
+	w.lastBeat = time.Now()
+	w.missed = make(chan time.Duration, 1)
+	w.limitChanged = make(chan struct{}, 1)
+	wd = w
+	return
+}
+
+// Software tells if the WatchDog runs in software mode (see WithSoftwareMode).
+func (c *WatchDog) Software() bool {
+	return c.software
+}
+
+// checksDuration computes the checks duration for the given limit.
+func (c *WatchDog) checksDuration(limit time.Duration) (time.Duration, error) {
+	if c.fixedChecks > 0 {
+		if c.fixedChecks >= limit {
+			return 0, fmt.Errorf("heartbeat interval %s must be lower than the watchdog limit %s", c.fixedChecks, limit)
+		}
+		return c.fixedChecks, nil
 	}
-	wpidTyped, err := strconv.Atoi(wpid)
-	if err != nil {
-		err = fmt.Errorf("can't convert WATCHDOG_PID as int: %s", err)
+	return time.Duration(float64(limit) * c.fraction), nil
+}
+
+func getWatchDogInterval(unsetEnv bool) (interval time.Duration, err error) {
+	if interval, err = sysdnotify.WatchDogEnabled(unsetEnv); err != nil {
 		return
 	}
-	if os.Getpid() != wpidTyped {
-		err = fmt.Errorf("WATCHDOG_PID is %d and we are %d: we are not the watched PID", wpidTyped, os.Getpid())
+	if interval == 0 {
+		err = errors.New("watchdog does not seem to be enabled: WATCHDOG_USEC unset or WATCHDOG_PID is not us")
 	}
 	return
 }
 
 // SendHeartbeat sends a keepalive notification to systemd watchdog
 func (c *WatchDog) SendHeartbeat() error {
+	if c.transport != nil {
+		return c.transport()
+	}
 	if !sysdnotify.IsEnabled() {
 		return errors.New("failed to notify watchdog: systemd notify is diabled")
 	}
@@ -74,18 +253,57 @@ func (c *WatchDog) SendHeartbeat() error {
 }
 
 // GetChecksDuration returns the ideal time for a client to perform (active or passive collect) checks.
-// Is is equal at 1/3 of watchdogInterval
+// It is equal to 1/2 of the watchdog limit unless configured otherwise at creation
 func (c *WatchDog) GetChecksDuration() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	return c.checks
 }
 
 // GetLimitDuration returns the systemd watchdog limit provided by systemd
 func (c *WatchDog) GetLimitDuration() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	return c.interval
 }
 
-// NewTicker initializes and returns a ticker set at watchdogChecks (which is set at 1/3 of watchdogInterval).
+// SetLimitDuration asks systemd to use limit as the new watchdog timeout (WATCHDOG_USEC=)
+// and updates the limit and checks durations of the WatchDog accordingly.
+// It is useful to lengthen the watchdog window during a known-slow phase, and to shorten it back after.
+// The new limit overrides WatchdogSec= of the unit until the service is restarted (systemd v236 or later),
+// it takes effect immediately: make sure to send a heartbeat right before lengthening it.
+// Run follows the new durations right away, but tickers returned by NewTicker() keep their period:
+// use ticker.Reset(GetChecksDuration()) to follow.
+func (c *WatchDog) SetLimitDuration(limit time.Duration) error {
+	if limit < time.Microsecond {
+		return fmt.Errorf("watchdog limit must be at least 1µs: %s", limit)
+	}
+	if !c.software && !sysdnotify.IsEnabled() {
+		return errors.New("failed to update watchdog limit: systemd notify is diabled")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	checks, err := c.checksDuration(limit)
+	if err != nil {
+		return err
+	}
+	// in software mode the limit only exists locally
+	if !c.software {
+		if err = sysdnotify.WatchDogUSec(limit.Microseconds()); err != nil {
+			return err
+		}
+	}
+	c.interval = limit
+	c.checks = checks
+	select {
+	case c.limitChanged <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// NewTicker initializes and returns a ticker set at the checks duration (see GetChecksDuration).
 // It can be used by clients to trigger checks before using SendHeartbeat().
 func (c *WatchDog) NewTicker() *time.Ticker {
-	return time.NewTicker(c.checks)
+	return time.NewTicker(c.GetChecksDuration())
 }
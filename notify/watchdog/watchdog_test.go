@@ -0,0 +1,367 @@
+package sysdwatchdog
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRunChecks(t *testing.T) {
+	wd := &WatchDog{
+		interval: 100 * time.Millisecond,
+		checks:   50 * time.Millisecond,
+		lastBeat: time.Now(),
+	}
+	wd.RegisterCheck("ok", func(ctx context.Context) error { return nil })
+	if err := wd.RunChecks(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	wd.RegisterCheck("stuck", func(ctx context.Context) error {
+		select {} // ignores ctx on purpose
+	})
+	start := time.Now()
+	err := wd.RunChecks(context.Background())
+	var checkErr CheckError
+	if !errors.As(err, &checkErr) || len(checkErr.Checks) != 1 || checkErr.Checks["stuck"] == nil {
+		t.Fatal("expected the stuck check to fail, got", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Error("stuck check was waited for", elapsed)
+	}
+	wd.UnregisterCheck("stuck")
+	if err = wd.RunChecks(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewHeartbeatOptions(t *testing.T) {
+	t.Setenv("WATCHDOG_USEC", "10000000")
+	t.Setenv("WATCHDOG_PID", "")
+	transport := WithHeartbeatTransport(func() error { return nil })
+	if _, err := New(); err == nil {
+		t.Error("watchdog should not be usable without notify socket nor transport")
+	}
+	wd, err := New(transport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wd.GetLimitDuration() != 10*time.Second || wd.GetChecksDuration() != 5*time.Second {
+		t.Error("unexpected default durations", wd.GetLimitDuration(), wd.GetChecksDuration())
+	}
+	if wd, err = New(transport, WithHeartbeatFraction(0.25)); err != nil {
+		t.Fatal(err)
+	}
+	if wd.GetChecksDuration() != 2500*time.Millisecond {
+		t.Error("unexpected checks duration", wd.GetChecksDuration())
+	}
+	if wd, err = New(transport, WithHeartbeatInterval(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if wd.GetChecksDuration() != time.Second {
+		t.Error("unexpected checks duration", wd.GetChecksDuration())
+	}
+	if _, err = New(transport, WithHeartbeatInterval(time.Minute)); err == nil {
+		t.Error("heartbeat interval above the limit should be rejected")
+	}
+	if _, err = New(transport, WithHeartbeatFraction(1)); err == nil {
+		t.Error("heartbeat fraction of 1 should be rejected")
+	}
+}
+
+func TestRunWithInjectedLimit(t *testing.T) {
+	beats := make(chan struct{}, 10)
+	wd, err := New(WithLimit(20*time.Millisecond), WithHeartbeatTransport(func() error {
+		beats <- struct{}{}
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go wd.Run(ctx)
+	select {
+	case <-beats:
+	case <-time.After(time.Second):
+		t.Fatal("no heartbeat sent")
+	}
+}
+
+func TestPauseAndMaintenance(t *testing.T) {
+	var beats int
+	wd, err := New(WithLimit(time.Second), WithHeartbeatTransport(func() error {
+		beats++
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wd.RegisterCheck("failing", func(ctx context.Context) error { return errors.New("ko") })
+	ctx := context.Background()
+	wd.beat(ctx)
+	if beats != 0 {
+		t.Fatal("heartbeat sent while a check fails")
+	}
+	mctx, cancel := wd.WithMaintenanceWindow(ctx)
+	wd.beat(ctx)
+	if beats != 1 {
+		t.Fatal("heartbeat not sent during maintenance")
+	}
+	wd.Pause()
+	wd.beat(ctx)
+	if beats != 1 {
+		t.Fatal("heartbeat sent while paused")
+	}
+	wd.Resume()
+	cancel()
+	<-mctx.Done()
+	wd.beat(ctx)
+	if beats != 1 {
+		t.Fatal("heartbeat sent after the maintenance window closed")
+	}
+	// windows are closed when Run returns
+	rctx, stop := context.WithCancel(ctx)
+	mctx, cancel = wd.WithMaintenanceWindow(ctx)
+	defer cancel()
+	ran := make(chan struct{})
+	go func() {
+		wd.Run(rctx)
+		close(ran)
+	}()
+	stop()
+	<-ran
+	<-mctx.Done()
+	if _, maintenance := wd.state(); maintenance {
+		t.Fatal("maintenance window still open after Run returned")
+	}
+}
+
+func TestHooks(t *testing.T) {
+	var (
+		events []HeartbeatEvent
+		failed []string
+		fail   = true
+	)
+	wd, err := New(WithLimit(time.Second),
+		WithHeartbeatTransport(func() error { return nil }),
+		WithHeartbeatHook(func(e HeartbeatEvent) { events = append(events, e) }),
+		WithCheckFailedHook(func(name string, err error) { failed = append(failed, name) }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wd.RegisterCheck("db", func(ctx context.Context) error {
+		if fail {
+			return errors.New("down")
+		}
+		return nil
+	})
+	wd.beat(context.Background())
+	fail = false
+	wd.beat(context.Background())
+	if len(failed) != 1 || failed[0] != "db" {
+		t.Error("unexpected failed checks", failed)
+	}
+	if len(events) != 1 || events[0].Err != nil {
+		t.Error("unexpected heartbeat events", events)
+	}
+}
+
+func TestTokens(t *testing.T) {
+	wd, err := New(WithLimit(time.Second), WithHeartbeatTransport(func() error { return nil }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := wd.NewToken("worker", 20*time.Millisecond)
+	if err = wd.RunChecks(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if err = wd.RunChecks(context.Background()); !errors.Is(err, ErrStaleToken) {
+		t.Fatal("expected a stale token error, got", err)
+	}
+	// a check may share its name with a token
+	wd.RegisterCheck("worker", func(ctx context.Context) error { return errors.New("ko") })
+	var checkErr CheckError
+	if err = wd.RunChecks(context.Background()); !errors.As(err, &checkErr) || checkErr.Checks["worker"] == nil || checkErr.Tokens["worker"] == nil {
+		t.Fatal("expected both the check and the token to fail, got", err)
+	}
+	wd.UnregisterCheck("worker")
+	token.Ping()
+	if err = wd.RunChecks(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(30 * time.Millisecond)
+	token.Release()
+	if err = wd.RunChecks(context.Background()); err != nil {
+		t.Fatal("released token should not be checked", err)
+	}
+}
+
+func TestUnsetEnv(t *testing.T) {
+	t.Setenv("WATCHDOG_USEC", "10000000")
+	t.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()))
+	if _, err := New(WithUnsetEnv(), WithHeartbeatTransport(func() error { return nil })); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := os.LookupEnv("WATCHDOG_USEC"); ok {
+		t.Error("WATCHDOG_USEC should have been unset")
+	}
+	if _, ok := os.LookupEnv("WATCHDOG_PID"); ok {
+		t.Error("WATCHDOG_PID should have been unset")
+	}
+}
+
+func TestSoftwareMode(t *testing.T) {
+	t.Setenv("WATCHDOG_USEC", "")
+	missed := make(chan struct{}, 1)
+	wd, err := New(WithSoftwareMode(30*time.Millisecond, func() {
+		select {
+		case missed <- struct{}{}:
+		default:
+		}
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !wd.Software() {
+		t.Fatal("software mode expected")
+	}
+	wd.RegisterCheck("failing", func(ctx context.Context) error { return errors.New("ko") })
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go wd.Run(ctx)
+	select {
+	case <-missed:
+	case <-time.After(time.Second):
+		t.Fatal("onMissed not called")
+	}
+}
+
+func TestRunFollowsLimit(t *testing.T) {
+	t.Setenv("WATCHDOG_USEC", "")
+	beats := make(chan struct{}, 1)
+	wd, err := New(WithSoftwareMode(time.Hour, func() {}), WithHeartbeatHook(func(HeartbeatEvent) {
+		select {
+		case beats <- struct{}{}:
+		default:
+		}
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go wd.Run(ctx)
+	if err = wd.SetLimitDuration(40 * time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-beats:
+	case <-time.After(time.Second):
+		t.Fatal("Run kept the checks duration of the previous limit")
+	}
+}
+
+func TestGoPanic(t *testing.T) {
+	var beats int
+	failed := make(chan error, 1)
+	wd, err := New(WithLimit(time.Second),
+		WithHeartbeatTransport(func() error {
+			beats++
+			return nil
+		}),
+		WithFailureHook(func(name string, err error) { failed <- err }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wd.Go("worker", func() { panic("boom") })
+	select {
+	case err = <-failed:
+	case <-time.After(time.Second):
+		t.Fatal("failure hook not called")
+	}
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) || panicErr.Value != "boom" {
+		t.Error("unexpected failure", err)
+	}
+	wd.beat(context.Background())
+	if beats != 0 {
+		t.Error("heartbeat sent after a failure")
+	}
+}
+
+func TestMissed(t *testing.T) {
+	wd, err := New(WithLimit(80*time.Millisecond), WithHeartbeatTransport(func() error { return nil }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wd.RegisterCheck("failing", func(ctx context.Context) error { return errors.New("ko") })
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go wd.Run(ctx)
+	select {
+	case since := <-wd.Missed():
+		if since < 60*time.Millisecond {
+			t.Error("missed fired too early", since)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("missed did not fire")
+	}
+	if wd.TimeSinceLastHeartbeat() < 60*time.Millisecond {
+		t.Error("no heartbeat should have been sent")
+	}
+}
+
+func TestMissedWhileBeating(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	wd, err := New(WithLimit(80*time.Millisecond), WithHeartbeatTransport(func() error {
+		<-release // stuck heartbeat
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go wd.Run(ctx)
+	select {
+	case <-wd.Missed():
+	case <-time.After(time.Second):
+		t.Fatal("missed did not fire while Run was blocked in a heartbeat")
+	}
+}
+
+func TestChecksBudget(t *testing.T) {
+	wd := &WatchDog{
+		interval: 100 * time.Millisecond,
+		checks:   40 * time.Millisecond,
+		lastBeat: time.Now(),
+	}
+	if budget := wd.checksBudget(); budget != 30*time.Millisecond {
+		t.Errorf("expected the checks duration minus the margin, got %s", budget)
+	}
+	wd.lastBeat = time.Now().Add(-80 * time.Millisecond)
+	if budget := wd.checksBudget(); budget > 10*time.Millisecond {
+		t.Errorf("expected the budget to end before the watchdog deadline, got %s", budget)
+	}
+}
+
+func TestHeartbeatError(t *testing.T) {
+	sendErr := errors.New("notify socket gone")
+	wd, err := New(WithLimit(time.Second), WithHeartbeatTransport(func() error { return sendErr }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wd.beat(context.Background()) {
+		t.Error("failed heartbeat reported as sent")
+	}
+	if !errors.Is(wd.LastHeartbeatError(), sendErr) {
+		t.Errorf("expected the send error, got %v", wd.LastHeartbeatError())
+	}
+}
@@ -0,0 +1,50 @@
+package sysdnotify
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// WatchDogEnabled returns the watchdog limit systemd expects this process to honor, read from
+// WATCHDOG_USEC and WATCHDOG_PID like sd_watchdog_enabled() does. limit is 0 if the watchdog is not
+// enabled or if it is meant for another process (WATCHDOG_PID mismatch).
+// If unsetEnv is true both variables are removed from the environment, whatever the outcome,
+// so they do not leak to child processes.
+func WatchDogEnabled(unsetEnv bool) (limit time.Duration, err error) {
+	if unsetEnv {
+		defer func() {
+			os.Unsetenv("WATCHDOG_USEC")
+			os.Unsetenv("WATCHDOG_PID")
+		}()
+	}
+	// WATCHDOG_USEC
+	wusec := os.Getenv("WATCHDOG_USEC")
+	if wusec == "" {
+		return
+	}
+	wusecTyped, err := strconv.ParseInt(wusec, 10, 64)
+	if err != nil {
+		err = fmt.Errorf("can't convert WATCHDOG_USEC as int: %w", err)
+		return
+	}
+	if wusecTyped <= 0 {
+		err = fmt.Errorf("WATCHDOG_USEC must be a positive number")
+		return
+	}
+	// WATCHDOG_PID
+	if wpid := os.Getenv("WATCHDOG_PID"); wpid != "" {
+		var wpidTyped int
+		if wpidTyped, err = strconv.Atoi(wpid); err != nil {
+			err = fmt.Errorf("can't convert WATCHDOG_PID as int: %w", err)
+			return
+		}
+		if os.Getpid() != wpidTyped {
+			return // not for us
+		}
+	}
+	// No WATCHDOG_PID: can't check if we are the one, let's go with it
+	limit = time.Duration(wusecTyped) * time.Microsecond
+	return
+}
@@ -0,0 +1,25 @@
+package portable1
+
+import (
+	"errors"
+
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+var (
+	// ErrNoSuchImage is returned when the image does not exist.
+	ErrNoSuchImage = errors.New("no such image")
+	// ErrAccessDenied is returned when polkit (or the caller privileges) denied the operation,
+	// see WithInteractiveAuthorization.
+	ErrAccessDenied = sysdbus.ErrAccessDenied
+)
+
+var dbusErrors = map[string]error{
+	// portabled shares the image errors of machined
+	"org.freedesktop.machine1.NoSuchImage": ErrNoSuchImage,
+}
+
+// typedError wraps the known portabled dbus errors into the Err* errors, so they can be tested with errors.Is.
+func typedError(err error) error {
+	return sysdbus.TypedError(err, dbusErrors)
+}
@@ -0,0 +1,155 @@
+package portable1
+
+import (
+	"context"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/iguanesolutions/go-systemd/v6/internal/osrelease"
+)
+
+// ImageState is the attachment state of a portable image.
+type ImageState string
+
+const (
+	StateDetached        ImageState = "detached"
+	StateAttached        ImageState = "attached"
+	StateAttachedRuntime ImageState = "attached-runtime"
+	StateEnabled         ImageState = "enabled"
+	StateEnabledRuntime  ImageState = "enabled-runtime"
+	StateRunning         ImageState = "running"
+	StateRunningRuntime  ImageState = "running-runtime"
+)
+
+// CopyMode is the way the image unit files are installed on attach.
+type CopyMode string
+
+const (
+	CopyAuto    CopyMode = "" // symlinks if possible, copies otherwise
+	CopySymlink CopyMode = "symlink"
+	CopyCopy    CopyMode = "copy"
+)
+
+// ImageStatus represents a portable image as returned by ListImages.
+type ImageStatus struct {
+	Name      string
+	Type      string // directory, subvolume, raw or block
+	ReadOnly  bool
+	Created   time.Time // zero if unknown
+	Modified  time.Time // zero if unknown
+	DiskUsage uint64    // bytes, ^uint64(0) if unknown
+	State     ImageState
+	Path      dbus.ObjectPath
+}
+
+// imageStatus is the dbus (ssbtttso) representation of ImageStatus.
+type imageStatus struct {
+	Name      string
+	Type      string
+	ReadOnly  bool
+	Created   uint64
+	Modified  uint64
+	DiskUsage uint64
+	State     string
+	Path      dbus.ObjectPath
+}
+
+// ListImages returns the portable images found in the image directories (eg: /var/lib/portables).
+// ctx: Context to use
+func (c *Conn) ListImages(ctx context.Context) ([]ImageStatus, error) {
+	var raw []imageStatus
+	if err := c.Call(ctx, "ListImages").Store(&raw); err != nil {
+		return nil, err
+	}
+	images := make([]ImageStatus, len(raw))
+	for i, img := range raw {
+		images[i] = ImageStatus{
+			Name:      img.Name,
+			Type:      img.Type,
+			ReadOnly:  img.ReadOnly,
+			Created:   usecTime(img.Created),
+			Modified:  usecTime(img.Modified),
+			DiskUsage: img.DiskUsage,
+			State:     ImageState(img.State),
+			Path:      img.Path,
+		}
+	}
+	return images, nil
+}
+
+// GetImageState returns the attachment state of the image.
+// ctx: Context to use
+// image: image name or path
+func (c *Conn) GetImageState(ctx context.Context, image string) (ImageState, error) {
+	var state string
+	if err := c.Call(ctx, "GetImageState", image).Store(&state); err != nil {
+		return "", typedError(err)
+	}
+	return ImageState(state), nil
+}
+
+// ImageMetadata is the metadata of a portable image.
+type ImageMetadata struct {
+	Image     string            // resolved image path
+	OSRelease map[string]string // os-release(5) fields of the image
+	UnitFiles map[string][]byte // unit files matching the image, by name
+}
+
+// GetImageMetadata returns the os-release and the unit files of the image.
+// ctx: Context to use
+// image: image name or path
+// matches: unit name prefixes to select the unit files, empty for the image name
+func (c *Conn) GetImageMetadata(ctx context.Context, image string, matches ...string) (*ImageMetadata, error) {
+	if matches == nil {
+		matches = []string{}
+	}
+	var (
+		m         ImageMetadata
+		osRelease []byte
+	)
+	if err := c.Call(ctx, "GetImageMetadata", image, matches).Store(&m.Image, &osRelease, &m.UnitFiles); err != nil {
+		return nil, typedError(err)
+	}
+	m.OSRelease = osrelease.Parse(osRelease)
+	return &m, nil
+}
+
+// Change is a file change made by AttachImage or DetachImage.
+type Change struct {
+	Type   string // symlink, copy, mkdir, unlink...
+	Path   string // changed path
+	Source string // symlink target or copy source, if any
+}
+
+// AttachImage attaches the portable image: its unit files (and drop-ins enforcing the profile)
+// are installed in the host, the units still have to be enabled and started.
+// ctx: Context to use
+// image: image name or path
+// matches: unit name prefixes to select the unit files, empty for the image name
+// profile: security profile (eg: default, nonetwork, strict, trusted)
+// mode: copy mode of the unit files
+// runtime: attach until the next reboot only
+func (c *Conn) AttachImage(ctx context.Context, image string, matches []string, profile string, mode CopyMode, runtime bool) (changes []Change, err error) {
+	if matches == nil {
+		matches = []string{}
+	}
+	err = typedError(c.Call(ctx, "AttachImage", image, matches, profile, runtime, string(mode)).Store(&changes))
+	return
+}
+
+// DetachImage detaches the portable image, its units should be stopped first.
+// ctx: Context to use
+// image: image name or path
+// runtime: whether the image was attached until the next reboot only
+func (c *Conn) DetachImage(ctx context.Context, image string, runtime bool) (changes []Change, err error) {
+	err = typedError(c.Call(ctx, "DetachImage", image, runtime).Store(&changes))
+	return
+}
+
+// usecTime returns the µs since epoch usec as a time.Time, zero if unset.
+func usecTime(usec uint64) time.Time {
+	if usec == 0 || usec == ^uint64(0) {
+		return time.Time{}
+	}
+	return time.UnixMicro(int64(usec))
+}
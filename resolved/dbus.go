@@ -20,13 +20,33 @@ const (
 
 // Conn represents a systemd-resolved dbus connection.
 type Conn struct {
-	conn *dbus.Conn
-	obj  dbus.BusObject
+	conn  *dbus.Conn
+	obj   dbus.BusObject
+	flags dbus.Flags
+}
+
+// ConnOption configures a Conn, see NewConn.
+type ConnOption func(c *Conn) error
+
+// WithInteractiveAuthorization sets the ALLOW_INTERACTIVE_AUTHORIZATION flag on method calls,
+// so polkit may prompt the user for privileged operations (eg: SetLinkDNS) instead of
+// failing with an access denied error. Calls may then block until the user answers.
+func WithInteractiveAuthorization() ConnOption {
+	return func(c *Conn) error {
+		c.flags |= dbus.FlagAllowInteractiveAuthorization
+		return nil
+	}
 }
 
 // NewConn returns a new and ready to use dbus connection.
 // You must close that connection when you have been done with it.
-func NewConn() (*Conn, error) {
+func NewConn(opts ...ConnOption) (*Conn, error) {
+	c := &Conn{}
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
 	conn, err := dbus.SystemBusPrivate()
 	if err != nil {
 		return nil, fmt.Errorf("failed to init private conn to system bus: %v", err)
@@ -42,15 +62,15 @@ func NewConn() (*Conn, error) {
 		conn.Close()
 		return nil, fmt.Errorf("failed to make hello call: %v", err)
 	}
-	return &Conn{
-		conn: conn,
-		obj:  conn.Object(dbusDest, dbus.ObjectPath(dbusPath)),
-	}, nil
+	c.conn = conn
+	c.obj = conn.Object(dbusDest, dbus.ObjectPath(dbusPath))
+	return c, nil
 }
 
-// Call wraps obj.CallWithContext by using 0 as flags and format the method with the dbus manager interface.
+// Call wraps obj.CallWithContext by using the connection flags (see WithInteractiveAuthorization)
+// and format the method with the dbus manager interface.
 func (c *Conn) Call(ctx context.Context, method string, args ...interface{}) *dbus.Call {
-	return c.obj.CallWithContext(ctx, fmt.Sprintf("%s.%s", dbusInterface, method), 0, args...)
+	return c.obj.CallWithContext(ctx, fmt.Sprintf("%s.%s", dbusInterface, method), c.flags, args...)
 }
 
 // Close closes the current dbus connection.
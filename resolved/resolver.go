@@ -44,10 +44,11 @@ type Resolver struct {
 	profile *idna.Profile
 }
 
-type resolverOption func(r *Resolver) error
+// ResolverOption configures a Resolver, see NewResolver.
+type ResolverOption func(r *Resolver) error
 
 // WithConn allow you to use a custom systemd-resolved dbus connection.
-func WithConn(c *Conn) resolverOption {
+func WithConn(c *Conn) ResolverOption {
 	return func(r *Resolver) error {
 		if c == nil {
 			return errors.New("conn is nil")
@@ -58,7 +59,7 @@ func WithConn(c *Conn) resolverOption {
 }
 
 // WithDialer allow you to use a custom net.Dialer.
-func WithDialer(d *net.Dialer) resolverOption {
+func WithDialer(d *net.Dialer) ResolverOption {
 	return func(r *Resolver) error {
 		if d == nil {
 			return errors.New("dialer is nil")
@@ -69,7 +70,7 @@ func WithDialer(d *net.Dialer) resolverOption {
 }
 
 // WithProfile allow you to use custom idna.Profile.
-func WithProfile(p *idna.Profile) resolverOption {
+func WithProfile(p *idna.Profile) ResolverOption {
 	return func(r *Resolver) error {
 		if p == nil {
 			return errors.New("profile is nil")
@@ -81,7 +82,7 @@ func WithProfile(p *idna.Profile) resolverOption {
 
 // NewResolver returns a new systemd Resolver with an initialized dbus connection.
 // it's up to you to close that connection when you have been done with the Resolver.
-func NewResolver(opts ...resolverOption) (*Resolver, error) {
+func NewResolver(opts ...ResolverOption) (*Resolver, error) {
 	r := &Resolver{}
 	var err error
 	for _, opt := range opts {
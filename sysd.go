@@ -1,6 +1,10 @@
 package sysd
 
-import "os"
+import (
+	"os"
+
+	sysdid128 "github.com/iguanesolutions/go-systemd/v6/id128"
+)
 
 // GetInvocationID returns the systemd invocation ID.
 // If exists is false, we have not been launched by systemd.
@@ -8,3 +12,18 @@ import "os"
 func GetInvocationID() (ID string, exists bool) {
 	return os.LookupEnv("INVOCATION_ID")
 }
+
+// InvocationID returns the systemd invocation ID, typed.
+// If exists is false, we have not been launched by systemd.
+//
+// The ID is read from $INVOCATION_ID. On Linux, when an intermediate process stripped it, it falls back
+// on the invocation_id extended attribute of the unit cgroup (as found thru /proc/self/cgroup),
+// then on the InvocationID property of the unit asked to the manager over dbus.
+// Fallbacks only apply to services: processes in scopes (eg: a login shell) are not considered launched by systemd.
+func InvocationID() (ID sysdid128.ID128, exists bool) {
+	if value, ok := GetInvocationID(); ok {
+		id, err := sysdid128.Parse(value)
+		return id, err == nil
+	}
+	return fallbackInvocationID()
+}
@@ -0,0 +1,70 @@
+package sysd
+
+import (
+	"context"
+	"syscall"
+	"time"
+
+	sysdid128 "github.com/iguanesolutions/go-systemd/v6/id128"
+	"github.com/iguanesolutions/go-systemd/v6/internal/cgroupfs"
+	"github.com/iguanesolutions/go-systemd/v6/systemd1"
+)
+
+// invocationIDTimeout bounds the dbus fallback of InvocationID.
+const invocationIDTimeout = 5 * time.Second
+
+// fallbackInvocationID looks for the invocation ID of the service unit when $INVOCATION_ID is not set.
+func fallbackInvocationID() (ID sysdid128.ID128, exists bool) {
+	cu, ok := serviceUnit()
+	if !ok {
+		return
+	}
+	if ID, exists = cgroupInvocationID(cu.Cgroup); exists {
+		return
+	}
+	if cu.UserUnit != "" {
+		return dbusInvocationID(cu.UserUnit, true)
+	}
+	return dbusInvocationID(cu.Unit, false)
+}
+
+// cgroupInvocationID reads the invocation ID systemd stores as an extended attribute of the unit cgroup.
+// trusted.invocation_id needs privileges, user.invocation_id is also set since systemd v248.
+func cgroupInvocationID(cgroup string) (sysdid128.ID128, bool) {
+	buf := make([]byte, 64)
+	for _, attr := range []string{"trusted.invocation_id", "user.invocation_id"} {
+		n, err := syscall.Getxattr(cgroupfs.Dir(cgroup), attr, buf)
+		if err != nil {
+			continue
+		}
+		if id, err := sysdid128.Parse(string(buf[:n])); err == nil && !id.IsNull() {
+			return id, true
+		}
+	}
+	return sysdid128.Null, false
+}
+
+// dbusInvocationID asks the system (or user) manager the InvocationID property of unit.
+func dbusInvocationID(unit string, user bool) (id sysdid128.ID128, exists bool) {
+	var opts []systemd1.ConnOption
+	if user {
+		opts = append(opts, systemd1.WithUserInstance())
+	}
+	conn, err := systemd1.NewConn(opts...)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), invocationIDTimeout)
+	defer cancel()
+	value, err := conn.GetUnitProperty(ctx, unit, "org.freedesktop.systemd1.Unit", "InvocationID")
+	if err != nil {
+		return
+	}
+	raw, _ := value.Value().([]byte)
+	if len(raw) != len(id) {
+		return
+	}
+	copy(id[:], raw)
+	return id, !id.IsNull()
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package sysd
+
+import sysdid128 "github.com/iguanesolutions/go-systemd/v6/id128"
+
+// fallbackInvocationID only applies to Linux, systemd is not available elsewhere.
+func fallbackInvocationID() (ID sysdid128.ID128, exists bool) {
+	return
+}
@@ -0,0 +1,102 @@
+package systemd1
+
+import (
+	"context"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+// Condition is a Condition*= or Assert*= setting of a unit and its last evaluation result.
+type Condition struct {
+	Type      string // eg: ConditionPathExists, AssertVirtualization
+	Trigger   bool   // "|" prefix: the unit only needs one triggering condition to pass
+	Negate    bool   // "!" prefix
+	Parameter string
+	State     int32 // > 0 passed, < 0 failed, 0 not evaluated
+}
+
+// Passed tells if the condition passed on its last evaluation.
+func (c Condition) Passed() bool {
+	return c.State > 0
+}
+
+// Failed tells if the condition failed on its last evaluation.
+func (c Condition) Failed() bool {
+	return c.State < 0
+}
+
+// String returns the condition as written in the unit file, eg: ConditionPathExists=!/etc/foo.
+func (c Condition) String() string {
+	s := c.Type + "="
+	if c.Trigger {
+		s += "|"
+	}
+	if c.Negate {
+		s += "!"
+	}
+	return s + c.Parameter
+}
+
+// ConditionReport holds the last evaluation of the conditions and asserts of a unit.
+// When ConditionResult is false the unit has been skipped, when AssertResult is false its start failed.
+type ConditionReport struct {
+	ConditionResult    bool
+	ConditionTimestamp time.Time // zero if never evaluated
+	Conditions         []Condition
+	AssertResult       bool
+	AssertTimestamp    time.Time // zero if never evaluated
+	Asserts            []Condition
+}
+
+// Failed returns the conditions and asserts which failed on their last evaluation.
+func (r *ConditionReport) Failed() (failed []Condition) {
+	for _, list := range [][]Condition{r.Conditions, r.Asserts} {
+		for _, c := range list {
+			if c.Failed() {
+				failed = append(failed, c)
+			}
+		}
+	}
+	return
+}
+
+// Conditions returns the last evaluation of the conditions and asserts of the unit,
+// eg: to explain why it has been skipped.
+// ctx: Context to use
+func (u *Unit) Conditions(ctx context.Context) (*ConditionReport, error) {
+	props, err := u.AllProperties(ctx, unitInterface)
+	if err != nil {
+		return nil, err
+	}
+	return newConditionReport(props)
+}
+
+// newConditionReport decodes the conditions report from the unit properties.
+func newConditionReport(props map[string]dbus.Variant) (r *ConditionReport, err error) {
+	r = &ConditionReport{
+		ConditionResult:    sysdbus.Prop[bool](props, "ConditionResult"),
+		ConditionTimestamp: sysdbus.Timestamp(props, "ConditionTimestamp"),
+		AssertResult:       sysdbus.Prop[bool](props, "AssertResult"),
+		AssertTimestamp:    sysdbus.Timestamp(props, "AssertTimestamp"),
+	}
+	if err = sysdbus.StoreProp(props, "Conditions", &r.Conditions); err != nil {
+		return nil, err
+	}
+	if err = sysdbus.StoreProp(props, "Asserts", &r.Asserts); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetUnitConditions returns the conditions report of the unit name, see Unit.Conditions.
+// ctx: Context to use
+// name: unit name
+func (c *Conn) GetUnitConditions(ctx context.Context, name string) (*ConditionReport, error) {
+	u, err := c.Unit(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return u.Conditions(ctx)
+}
@@ -0,0 +1,141 @@
+// Package systemd1 is a pure Go implementation of the org.freedesktop.systemd1 dbus interface,
+// which allows to manage units the way systemctl does.
+// The only exception is reading the journal (see Unit.Logs), which runs journalctl.
+package systemd1
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+const (
+	dbusDest      = "org.freedesktop.systemd1"
+	dbusInterface = "org.freedesktop.systemd1.Manager"
+	dbusPath      = "/org/freedesktop/systemd1"
+)
+
+// Conn represents a systemd manager dbus connection.
+type Conn struct {
+	conn    *dbus.Conn
+	obj     dbus.BusObject
+	dial    func() (*dbus.Conn, error)
+	private bool
+	user    bool
+	flags   dbus.Flags
+
+	sigOnce   sync.Once
+	sigErr    error
+	propsOnce sync.Once
+	propsErr  error
+	jobsMu    sync.Mutex
+	jobs      map[dbus.ObjectPath]*Job
+	subsMu    sync.Mutex
+	subs      map[*Subscription]struct{}
+}
+
+// ConnOption configures a Conn, see NewConn.
+type ConnOption func(c *Conn) error
+
+// WithUserInstance connects to the per-user systemd instance thru the session bus
+// instead of the system manager, to manage the user units.
+func WithUserInstance() ConnOption {
+	return func(c *Conn) error {
+		c.dial = sysdbus.SessionBus
+		c.private = false
+		c.user = true
+		return nil
+	}
+}
+
+// WithUserPrivateSocket connects to the per-user systemd instance thru its private socket
+// ($XDG_RUNTIME_DIR/systemd/private), which does not need a session bus.
+func WithUserPrivateSocket() ConnOption {
+	return func(c *Conn) error {
+		c.dial = sysdbus.UserManagerPrivate
+		c.private = true
+		c.user = true
+		return nil
+	}
+}
+
+// WithInteractiveAuthorization sets the ALLOW_INTERACTIVE_AUTHORIZATION flag on method calls,
+// so polkit may prompt the user (eg: thru a polkit agent of the terminal) for privileged operations
+// instead of failing with an access denied error. Calls may then block until the user answers.
+func WithInteractiveAuthorization() ConnOption {
+	return func(c *Conn) error {
+		c.flags |= dbus.FlagAllowInteractiveAuthorization
+		return nil
+	}
+}
+
+// NewConn returns a new and ready to use dbus connection, to the system manager by default.
+// You must close that connection when you have been done with it.
+func NewConn(opts ...ConnOption) (*Conn, error) {
+	c := &Conn{
+		dial: sysdbus.SystemBus,
+		jobs: make(map[dbus.ObjectPath]*Job),
+		subs: make(map[*Subscription]struct{}),
+	}
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+	conn, err := c.dial()
+	if err != nil {
+		return nil, err
+	}
+	c.conn = conn
+	c.obj = conn.Object(dbusDest, dbus.ObjectPath(dbusPath))
+	return c, nil
+}
+
+// Call wraps obj.CallWithContext by using the connection flags (see WithInteractiveAuthorization)
+// and format the method with the dbus manager interface.
+func (c *Conn) Call(ctx context.Context, method string, args ...interface{}) *dbus.Call {
+	return c.obj.CallWithContext(ctx, fmt.Sprintf("%s.%s", dbusInterface, method), c.flags, args...)
+}
+
+// Close closes the current dbus connection.
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+// signals subscribes, once, to the manager signals and starts dispatching them.
+func (c *Conn) signals() error {
+	c.sigOnce.Do(func() {
+		// there is no bus daemon to add matches to on the private socket: every signal is received
+		if !c.private {
+			err := c.conn.AddMatchSignal(
+				dbus.WithMatchObjectPath(dbusPath),
+				dbus.WithMatchInterface(dbusInterface),
+			)
+			if err != nil {
+				c.sigErr = fmt.Errorf("failed to add signals match: %w", err)
+				return
+			}
+		}
+		// the manager only emits most signals when at least one client is subscribed
+		if err := c.Call(context.Background(), "Subscribe").Store(); err != nil {
+			c.sigErr = fmt.Errorf("failed to subscribe to manager signals: %w", err)
+			return
+		}
+		ch := make(chan *dbus.Signal, 64)
+		c.conn.Signal(ch)
+		go c.dispatch(ch)
+	})
+	return c.sigErr
+}
+
+func (c *Conn) dispatch(ch chan *dbus.Signal) {
+	for sig := range ch {
+		if sig.Name == dbusInterface+".JobRemoved" {
+			c.jobRemoved(sig)
+		}
+		c.publish(sig)
+	}
+}
@@ -0,0 +1,155 @@
+package systemd1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/iguanesolutions/go-systemd/v6/internal/cgroupfs"
+)
+
+// sysPidfdOpen is the pidfd_open syscall number, the same on every architecture.
+const sysPidfdOpen = 434
+
+// CgroupRoot is the mount point of the cgroup v2 hierarchy.
+const CgroupRoot = cgroupfs.Root
+
+// StartAuxiliaryScope creates and starts a scope unit holding the processes pids, taken out of the
+// unit of the caller, with its own resources (systemd v251 or later). It is meant for services which
+// spawn workers that must outlive them or be accounted separately. The processes are referenced by
+// pidfds so a recycled PID can't be moved by mistake.
+// ctx: Context to use
+// name: scope unit name (eg: worker-1.scope)
+// pids: processes IDs, they must belong to the unit of the caller
+// properties: scope unit properties, see the Prop* functions
+func (c *Conn) StartAuxiliaryScope(ctx context.Context, name string, pids []int, properties ...Property) (*Job, error) {
+	if len(pids) == 0 {
+		return nil, errors.New("no processes to move")
+	}
+	fds := make([]dbus.UnixFD, 0, len(pids))
+	defer func() {
+		for _, fd := range fds {
+			syscall.Close(int(fd))
+		}
+	}()
+	for _, pid := range pids {
+		fd, _, errno := syscall.Syscall(sysPidfdOpen, uintptr(pid), 0, 0)
+		if errno != 0 {
+			return nil, fmt.Errorf("failed to open pidfd of %d: %w", pid, errno)
+		}
+		fds = append(fds, dbus.UnixFD(fd))
+	}
+	if properties == nil {
+		properties = []Property{}
+	}
+	return c.enqueue(ctx, name, "StartAuxiliaryScope", name, fds, uint64(0), properties)
+}
+
+// DelegatedCgroup is a cgroup v2 directory the caller may manage itself,
+// as granted by Delegate=yes (see PropDelegate).
+type DelegatedCgroup struct {
+	Path string // absolute path, under CgroupRoot
+}
+
+// OwnDelegatedCgroup returns the cgroup of the calling process, which must belong to a unit with Delegate=yes.
+func OwnDelegatedCgroup() (*DelegatedCgroup, error) {
+	cu, err := CurrentCgroupUnit()
+	if err != nil {
+		return nil, err
+	}
+	return &DelegatedCgroup{Path: cgroupfs.Dir(cu.Cgroup)}, nil
+}
+
+// Controllers returns the controllers available in the cgroup (eg: cpu, memory, pids).
+func (d *DelegatedCgroup) Controllers() ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(d.Path, "cgroup.controllers"))
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(data)), nil
+}
+
+// Processes returns the processes IDs of the cgroup itself, not including its subgroups.
+func (d *DelegatedCgroup) Processes() ([]int, error) {
+	data, err := os.ReadFile(filepath.Join(d.Path, "cgroup.procs"))
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.Fields(string(data))
+	pids := make([]int, 0, len(fields))
+	for _, f := range fields {
+		pid, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pid %q: %w", f, err)
+		}
+		pids = append(pids, pid)
+	}
+	return pids, nil
+}
+
+// Subgroup creates, if needed, and returns the sub cgroup name.
+func (d *DelegatedCgroup) Subgroup(name string) (*DelegatedCgroup, error) {
+	if name == "" || strings.ContainsRune(name, '/') || name == "." || name == ".." {
+		return nil, fmt.Errorf("invalid cgroup name: %q", name)
+	}
+	sub := &DelegatedCgroup{Path: filepath.Join(d.Path, name)}
+	if err := os.Mkdir(sub.Path, 0o755); err != nil && !errors.Is(err, os.ErrExist) {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// Attach moves the process pid into the cgroup.
+func (d *DelegatedCgroup) Attach(pid int) error {
+	return os.WriteFile(filepath.Join(d.Path, "cgroup.procs"), []byte(strconv.Itoa(pid)), 0)
+}
+
+// EnableControllers enables controllers (eg: cpu, memory) for the subgroups of the cgroup.
+// The cgroup must not hold processes itself ("no internal processes" rule), see Isolate.
+func (d *DelegatedCgroup) EnableControllers(controllers ...string) error {
+	if len(controllers) == 0 {
+		return nil
+	}
+	var b strings.Builder
+	for i, ctrl := range controllers {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString("+" + ctrl)
+	}
+	if err := os.WriteFile(filepath.Join(d.Path, "cgroup.subtree_control"), []byte(b.String()), 0); err != nil {
+		return fmt.Errorf("failed to enable controllers %v: %w", controllers, err)
+	}
+	return nil
+}
+
+// Isolate moves every process of the cgroup into its subgroup leaf (eg: "supervisor"), which
+// is required before enabling controllers: in cgroup v2 only leaves may hold processes.
+func (d *DelegatedCgroup) Isolate(leaf string) (*DelegatedCgroup, error) {
+	sub, err := d.Subgroup(leaf)
+	if err != nil {
+		return nil, err
+	}
+	pids, err := d.Processes()
+	if err != nil {
+		return nil, err
+	}
+	for _, pid := range pids {
+		// the process may have exited meanwhile
+		if err = sub.Attach(pid); err != nil && !errors.Is(err, syscall.ESRCH) {
+			return nil, fmt.Errorf("failed to move %d: %w", pid, err)
+		}
+	}
+	return sub, nil
+}
+
+// Remove removes the cgroup, it must not hold processes nor subgroups anymore.
+func (d *DelegatedCgroup) Remove() error {
+	return os.Remove(d.Path)
+}
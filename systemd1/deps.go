@@ -0,0 +1,135 @@
+package systemd1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+// DependencyType is a unit dependency property name.
+type DependencyType string
+
+const (
+	Requires     DependencyType = "Requires"
+	Requisite    DependencyType = "Requisite"
+	Wants        DependencyType = "Wants"
+	BindsTo      DependencyType = "BindsTo"
+	PartOf       DependencyType = "PartOf"
+	Upholds      DependencyType = "Upholds"
+	Conflicts    DependencyType = "Conflicts"
+	Before       DependencyType = "Before"
+	After        DependencyType = "After"
+	Triggers     DependencyType = "Triggers"
+	RequiredBy   DependencyType = "RequiredBy"   // reverse of Requires
+	RequisiteOf  DependencyType = "RequisiteOf"  // reverse of Requisite
+	WantedBy     DependencyType = "WantedBy"     // reverse of Wants
+	BoundBy      DependencyType = "BoundBy"      // reverse of BindsTo
+	ConsistsOf   DependencyType = "ConsistsOf"   // reverse of PartOf
+	UpheldBy     DependencyType = "UpheldBy"     // reverse of Upholds
+	ConflictedBy DependencyType = "ConflictedBy" // reverse of Conflicts
+	TriggeredBy  DependencyType = "TriggeredBy"  // reverse of Triggers
+)
+
+// DependencyTypes are every dependency type read by Unit.Dependencies.
+var DependencyTypes = []DependencyType{
+	Requires, Requisite, Wants, BindsTo, PartOf, Upholds, Conflicts, Before, After, Triggers,
+	RequiredBy, RequisiteOf, WantedBy, BoundBy, ConsistsOf, UpheldBy, ConflictedBy, TriggeredBy,
+}
+
+// StopPropagation are the dependency types followed by a stop (or restart) job:
+// units requiring, bound to or part of the stopped unit are stopped too.
+var StopPropagation = []DependencyType{RequiredBy, BoundBy, ConsistsOf}
+
+// Dependencies maps dependency types to unit names.
+type Dependencies map[DependencyType][]string
+
+// Dependencies returns the dependencies of the unit, of every type of DependencyTypes.
+// ctx: Context to use
+func (u *Unit) Dependencies(ctx context.Context) (Dependencies, error) {
+	props, err := u.AllProperties(ctx, unitInterface)
+	if err != nil {
+		return nil, err
+	}
+	deps := make(Dependencies, len(DependencyTypes))
+	for _, t := range DependencyTypes {
+		if names := sysdbus.Prop[[]string](props, string(t)); len(names) > 0 {
+			deps[t] = names
+		}
+	}
+	return deps, nil
+}
+
+// GetUnitDependencies returns the dependencies of the unit name, see Unit.Dependencies.
+// ctx: Context to use
+// name: unit name
+func (c *Conn) GetUnitDependencies(ctx context.Context, name string) (Dependencies, error) {
+	u, err := c.Unit(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return u.Dependencies(ctx)
+}
+
+// DependencyGraph maps unit names to their dependencies, see Conn.DependencyGraph.
+type DependencyGraph map[string]Dependencies
+
+// Units returns the sorted names of the units of the graph.
+func (g DependencyGraph) Units() []string {
+	names := make([]string, 0, len(g))
+	for name := range g {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DependencyGraph walks the dependencies of the unit name, only following types, and returns the
+// (partial) graph of the visited units. Only the dependencies of types are kept in the graph.
+// ctx: Context to use
+// name: unit name to start from
+// depth: maximum number of hops from name, 0 meaning no limit
+// types: dependency types to follow
+func (c *Conn) DependencyGraph(ctx context.Context, name string, depth int, types ...DependencyType) (DependencyGraph, error) {
+	if len(types) == 0 {
+		return nil, fmt.Errorf("no dependency types to follow")
+	}
+	graph := make(DependencyGraph)
+	level := []string{name}
+	for hops := 0; len(level) > 0 && (depth == 0 || hops <= depth); hops++ {
+		var next []string
+		for _, n := range level {
+			if _, seen := graph[n]; seen {
+				continue
+			}
+			all, err := c.GetUnitDependencies(ctx, n)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get dependencies of %s: %w", n, err)
+			}
+			deps := make(Dependencies, len(types))
+			for _, t := range types {
+				if names := all[t]; len(names) > 0 {
+					deps[t] = names
+					next = append(next, names...)
+				}
+			}
+			graph[n] = deps
+		}
+		level = next
+	}
+	return graph, nil
+}
+
+// Affected returns the sorted names of the units also stopped (or restarted) when
+// the unit name is, following StopPropagation transitively.
+// ctx: Context to use
+// name: unit name
+func (c *Conn) Affected(ctx context.Context, name string) ([]string, error) {
+	graph, err := c.DependencyGraph(ctx, name, 0, StopPropagation...)
+	if err != nil {
+		return nil, err
+	}
+	delete(graph, name)
+	return graph.Units(), nil
+}
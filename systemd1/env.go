@@ -0,0 +1,49 @@
+package systemd1
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+// GetEnvironment returns the environment block of the manager, passed to every unit it starts,
+// as KEY=value entries.
+// ctx: Context to use
+func (c *Conn) GetEnvironment(ctx context.Context) ([]string, error) {
+	v, err := sysdbus.GetProperty(ctx, c.obj, dbusInterface, "Environment")
+	if err != nil {
+		return nil, err
+	}
+	env, _ := v.Value().([]string)
+	return env, nil
+}
+
+// SetEnvironment adds or replaces variables of the manager environment block,
+// they apply to units started afterward.
+// ctx: Context to use
+// env: KEY=value entries
+func (c *Conn) SetEnvironment(ctx context.Context, env ...string) error {
+	for _, kv := range env {
+		if key, _, ok := strings.Cut(kv, "="); !ok || key == "" {
+			return errors.New("invalid environment entry, KEY=value expected: " + kv)
+		}
+	}
+	return c.Call(ctx, "SetEnvironment", nilToEmpty(env)).Store()
+}
+
+// UnsetEnvironment removes variables from the manager environment block.
+// ctx: Context to use
+// names: variable names, or KEY=value entries to only remove the variables having that exact value
+func (c *Conn) UnsetEnvironment(ctx context.Context, names ...string) error {
+	return c.Call(ctx, "UnsetEnvironment", nilToEmpty(names)).Store()
+}
+
+// UnsetAndSetEnvironment atomically removes then sets variables of the manager environment block.
+// ctx: Context to use
+// unset: variable names (or KEY=value entries) to remove
+// set: KEY=value entries to add or replace
+func (c *Conn) UnsetAndSetEnvironment(ctx context.Context, unset, set []string) error {
+	return c.Call(ctx, "UnsetAndSetEnvironment", nilToEmpty(unset), nilToEmpty(set)).Store()
+}
@@ -0,0 +1,51 @@
+package systemd1
+
+import (
+	"context"
+	"sync"
+)
+
+// ListFailedUnits returns the loaded units in the failed state.
+// ctx: Context to use
+func (c *Conn) ListFailedUnits(ctx context.Context) ([]UnitStatus, error) {
+	return c.ListUnitsFiltered(ctx, []string{"failed"})
+}
+
+// RestartResult is the outcome of the recovery of a failed unit by RestartFailed.
+type RestartResult struct {
+	Unit   string
+	Result JobResult // result of the restart job, empty if Err is set
+	Err    error     // reset or enqueue error, or ctx error while waiting for the job
+}
+
+// RestartFailed resets and restarts every failed unit matching patterns, and waits for the restart jobs.
+// Units are restarted concurrently, one RestartResult is returned per failed unit found.
+// ctx: Context to use
+// patterns: shell-style glob patterns matching unit names (eg: "app-*.service"), empty means any
+func (c *Conn) RestartFailed(ctx context.Context, patterns ...string) ([]RestartResult, error) {
+	units, err := c.ListUnitsByPatterns(ctx, []string{"failed"}, patterns)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]RestartResult, len(units))
+	var wg sync.WaitGroup
+	for i, u := range units {
+		wg.Add(1)
+		go func(res *RestartResult, name string) {
+			defer wg.Done()
+			res.Unit = name
+			// reset first: the restart would be refused if the start rate limit has been hit
+			if res.Err = c.ResetFailedUnit(ctx, name); res.Err != nil {
+				return
+			}
+			job, err := c.RestartUnit(ctx, name, ModeReplace)
+			if err != nil {
+				res.Err = err
+				return
+			}
+			res.Result, res.Err = job.Wait(ctx)
+		}(&results[i], u.Name)
+	}
+	wg.Wait()
+	return results, nil
+}
@@ -0,0 +1,38 @@
+package systemd1
+
+import (
+	"context"
+	"errors"
+)
+
+// FreezeUnit freezes the processes of the unit name with the cgroup v2 freezer,
+// they stay in memory but are not scheduled anymore until ThawUnit is called.
+// It fails with errors.ErrUnsupported if the manager is older than systemd v246.
+// ctx: Context to use
+// name: unit name
+func (c *Conn) FreezeUnit(ctx context.Context, name string) error {
+	return c.unsupported(ctx, c.Call(ctx, "FreezeUnit", name).Store(), (*Version).SupportsFreeze)
+}
+
+// ThawUnit resumes the processes of the unit name frozen by FreezeUnit.
+// ctx: Context to use
+// name: unit name
+func (c *Conn) ThawUnit(ctx context.Context, name string) error {
+	return c.unsupported(ctx, c.Call(ctx, "ThawUnit", name).Store(), (*Version).SupportsFreeze)
+}
+
+// WhileFrozen freezes the unit name, calls fn and thaws the unit, even if fn fails, panics or ctx is done.
+// It is meant to quiesce a service briefly (eg: while taking a filesystem snapshot).
+// ctx: Context to use
+// name: unit name
+// fn: function to call while the unit is frozen
+func (c *Conn) WhileFrozen(ctx context.Context, name string, fn func() error) (err error) {
+	if err = c.FreezeUnit(ctx, name); err != nil {
+		return
+	}
+	defer func() {
+		// thaw even if ctx is done: leaving the unit frozen is worse
+		err = errors.Join(err, c.ThawUnit(context.WithoutCancel(ctx), name))
+	}()
+	return fn()
+}
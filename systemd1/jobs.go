@@ -0,0 +1,142 @@
+package systemd1
+
+import (
+	"context"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const jobInterface = "org.freedesktop.systemd1.Job"
+
+// Mode is the job mode used when enqueuing a job (start, stop, restart...).
+type Mode string
+
+const (
+	// ModeReplace replaces conflicting queued jobs.
+	ModeReplace Mode = "replace"
+	// ModeFail fails if the job would conflict with queued jobs.
+	ModeFail Mode = "fail"
+	// ModeIsolate stops every other unit (start only).
+	ModeIsolate Mode = "isolate"
+	// ModeIgnoreDependencies ignores every unit dependency.
+	ModeIgnoreDependencies Mode = "ignore-dependencies"
+	// ModeIgnoreRequirements only honors ordering dependencies.
+	ModeIgnoreRequirements Mode = "ignore-requirements"
+)
+
+// JobResult is the result of a finished job.
+type JobResult string
+
+const (
+	JobDone       JobResult = "done"       // successful execution
+	JobCanceled   JobResult = "canceled"   // job has been canceled before it finished
+	JobTimeout    JobResult = "timeout"    // job timeout was reached
+	JobFailed     JobResult = "failed"     // job failed
+	JobDependency JobResult = "dependency" // a job this job depended on failed
+	JobSkipped    JobResult = "skipped"    // job was skipped as it didn't apply to the unit's current state
+)
+
+// Job represents a job enqueued by the manager.
+type Job struct {
+	Path   dbus.ObjectPath // job object path
+	Unit   string          // unit the job applies to
+	c      *Conn
+	result chan JobResult
+}
+
+// Wait waits for the job to finish and returns its result.
+func (j *Job) Wait(ctx context.Context) (JobResult, error) {
+	select {
+	case res := <-j.result:
+		// allow several Wait calls
+		j.result <- res
+		return res, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// enqueue calls method, which must return a job path, and tracks the resulting job.
+func (c *Conn) enqueue(ctx context.Context, unit string, method string, args ...interface{}) (*Job, error) {
+	if err := c.signals(); err != nil {
+		return nil, err
+	}
+	// hold the lock while calling so the JobRemoved signal can't be handled before the job is tracked
+	c.jobsMu.Lock()
+	defer c.jobsMu.Unlock()
+	var path dbus.ObjectPath
+	if err := c.Call(ctx, method, args...).Store(&path); err != nil {
+		return nil, err
+	}
+	job := &Job{
+		Path:   path,
+		Unit:   unit,
+		c:      c,
+		result: make(chan JobResult, 1),
+	}
+	c.jobs[path] = job
+	return job, nil
+}
+
+func (c *Conn) jobRemoved(sig *dbus.Signal) {
+	var (
+		id     uint32
+		path   dbus.ObjectPath
+		unit   string
+		result string
+	)
+	if err := dbus.Store(sig.Body, &id, &path, &unit, &result); err != nil {
+		return
+	}
+	c.jobsMu.Lock()
+	job, ok := c.jobs[path]
+	delete(c.jobs, path)
+	c.jobsMu.Unlock()
+	if ok {
+		job.result <- JobResult(result)
+	}
+}
+
+// JobStatus describes a queued job, as returned by ListJobs.
+type JobStatus struct {
+	ID       uint32
+	Unit     string          // unit the job applies to
+	Type     string          // start, stop, restart, reload...
+	State    string          // waiting or running
+	Path     dbus.ObjectPath // job object path
+	UnitPath dbus.ObjectPath // unit object path
+}
+
+// ListJobs returns the jobs currently queued by the manager.
+// ctx: Context to use
+func (c *Conn) ListJobs(ctx context.Context) (jobs []JobStatus, err error) {
+	err = c.Call(ctx, "ListJobs").Store(&jobs)
+	return
+}
+
+// GetJob returns the job object path of the queued job id.
+// ctx: Context to use
+// id: job ID
+func (c *Conn) GetJob(ctx context.Context, id uint32) (path dbus.ObjectPath, err error) {
+	err = c.Call(ctx, "GetJob", id).Store(&path)
+	return
+}
+
+// CancelJob cancels the queued job id, waiting Job.Wait calls then return JobCanceled.
+// ctx: Context to use
+// id: job ID
+func (c *Conn) CancelJob(ctx context.Context, id uint32) error {
+	return c.Call(ctx, "CancelJob", id).Store()
+}
+
+// ClearJobs cancels every queued job.
+// ctx: Context to use
+func (c *Conn) ClearJobs(ctx context.Context) error {
+	return c.Call(ctx, "ClearJobs").Store()
+}
+
+// Cancel cancels the job.
+// ctx: Context to use
+func (j *Job) Cancel(ctx context.Context) error {
+	return j.c.conn.Object(dbusDest, j.Path).CallWithContext(ctx, jobInterface+".Cancel", j.c.flags).Store()
+}
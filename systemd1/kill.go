@@ -0,0 +1,37 @@
+package systemd1
+
+import (
+	"context"
+	"syscall"
+)
+
+// KillWho selects which processes of a unit KillUnit signals.
+type KillWho string
+
+const (
+	KillMain    KillWho = "main"    // main process only
+	KillControl KillWho = "control" // control process only (ExecReload=, ExecStop=...)
+	KillAll     KillWho = "all"     // every process of the unit
+)
+
+// KillUnit sends signal to the processes of the unit name selected by who.
+// ctx: Context to use
+// name: unit name
+// who: processes to signal
+// signal: signal to send
+func (c *Conn) KillUnit(ctx context.Context, name string, who KillWho, signal syscall.Signal) error {
+	return c.Call(ctx, "KillUnit", name, string(who), int32(signal)).Store()
+}
+
+// ResetFailedUnit resets the failed state of the unit name, and its restart counter.
+// ctx: Context to use
+// name: unit name
+func (c *Conn) ResetFailedUnit(ctx context.Context, name string) error {
+	return c.Call(ctx, "ResetFailedUnit", name).Store()
+}
+
+// ResetFailed resets the failed state of every unit.
+// ctx: Context to use
+func (c *Conn) ResetFailed(ctx context.Context) error {
+	return c.Call(ctx, "ResetFailed").Store()
+}
@@ -0,0 +1,61 @@
+package systemd1
+
+import (
+	"context"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// UnitStatus represents a unit as returned by the ListUnits* methods.
+type UnitStatus struct {
+	Name        string          // primary unit name
+	Description string          // human readable description
+	LoadState   string          // load state (loaded, not-found, masked...)
+	ActiveState string          // active state (active, inactive, failed...)
+	SubState    string          // sub state, unit type specific (running, exited, dead...)
+	Following   string          // unit this unit is following in its state, if any
+	Path        dbus.ObjectPath // unit object path
+	JobID       uint32          // queued job id, 0 if none
+	JobType     string          // queued job type
+	JobPath     dbus.ObjectPath // queued job object path
+}
+
+// ListUnits returns the units currently loaded.
+// ctx: Context to use
+func (c *Conn) ListUnits(ctx context.Context) (units []UnitStatus, err error) {
+	err = c.Call(ctx, "ListUnits").Store(&units)
+	return
+}
+
+// ListUnitsFiltered returns the units currently loaded filtered by states.
+// ctx: Context to use
+// states: active, load or sub states to match (eg: "failed", "running")
+func (c *Conn) ListUnitsFiltered(ctx context.Context, states []string) (units []UnitStatus, err error) {
+	err = c.Call(ctx, "ListUnitsFiltered", nilToEmpty(states)).Store(&units)
+	return
+}
+
+// ListUnitsByPatterns returns the units currently loaded filtered by states and name patterns.
+// ctx: Context to use
+// states: active, load or sub states to match, empty means any
+// patterns: shell-style glob patterns matching unit names (eg: "nginx*.service"), empty means any
+func (c *Conn) ListUnitsByPatterns(ctx context.Context, states []string, patterns []string) (units []UnitStatus, err error) {
+	err = c.Call(ctx, "ListUnitsByPatterns", nilToEmpty(states), nilToEmpty(patterns)).Store(&units)
+	return
+}
+
+// ListUnitsByNames returns the units with the given names, loading them if needed.
+// ctx: Context to use
+// names: unit names
+func (c *Conn) ListUnitsByNames(ctx context.Context, names []string) (units []UnitStatus, err error) {
+	err = c.Call(ctx, "ListUnitsByNames", nilToEmpty(names)).Store(&units)
+	return
+}
+
+// nilToEmpty makes sure a nil slice is sent as an empty dbus array.
+func nilToEmpty(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
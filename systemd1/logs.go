@@ -0,0 +1,167 @@
+package systemd1
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+// journalctl is the command used to read the journal, the package has no native journal reader.
+var journalctl = "journalctl"
+
+// LogEntry is a journal entry of a unit.
+type LogEntry struct {
+	Time     time.Time
+	Priority int // syslog priority, 0 (emerg) to 7 (debug)
+	Message  string
+	PID      int
+	Fields   map[string]string // every field of the entry, binary ones included as is
+}
+
+// LogStream delivers the journal entries of a unit, see Unit.Logs.
+type LogStream struct {
+	// C receives the entries, it is closed once the stream ended (see Err).
+	C <-chan LogEntry
+
+	err  error
+	done chan struct{}
+}
+
+// Err waits for the stream to end and returns the reason, nil if ended by its context.
+func (s *LogStream) Err() error {
+	<-s.done
+	return s.err
+}
+
+// Logs streams the journal entries of the unit logged since since with a priority lower or equal
+// to priority (0 to 7), then follows the new ones until ctx is done. It runs journalctl, which
+// must be installed and readable by the caller (eg: member of the systemd-journal group).
+// ctx: Context to use, cancel it to stop the stream
+// since: oldest entries time, zero for the whole journal
+// priority: maximum priority, 7 for every entry
+func (u *Unit) Logs(ctx context.Context, since time.Time, priority int) (*LogStream, error) {
+	return u.logs(ctx, since, priority, true)
+}
+
+// RecentLogs returns the journal entries of the unit logged since since with a priority lower or equal
+// to priority (0 to 7), eg: to show why it failed. See Logs.
+// ctx: Context to use
+// since: oldest entries time, zero for the whole journal
+// priority: maximum priority, 7 for every entry
+func (u *Unit) RecentLogs(ctx context.Context, since time.Time, priority int) ([]LogEntry, error) {
+	stream, err := u.logs(ctx, since, priority, false)
+	if err != nil {
+		return nil, err
+	}
+	var entries []LogEntry
+	for e := range stream.C {
+		entries = append(entries, e)
+	}
+	if err = stream.Err(); err == nil {
+		err = ctx.Err()
+	}
+	return entries, err
+}
+
+func (u *Unit) logs(ctx context.Context, since time.Time, priority int, follow bool) (*LogStream, error) {
+	if priority < 0 || priority > 7 {
+		return nil, fmt.Errorf("invalid priority: %d", priority)
+	}
+	args := []string{"--output=json", "--no-pager", "--priority=" + strconv.Itoa(priority)}
+	if u.c.user {
+		args = append(args, "--user-unit="+u.Name)
+	} else {
+		args = append(args, "--unit="+u.Name)
+	}
+	if !since.IsZero() {
+		args = append(args, "--since=@"+strconv.FormatInt(since.Unix(), 10))
+	}
+	if follow {
+		args = append(args, "--follow")
+	}
+	cmd := exec.CommandContext(ctx, journalctl, args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err = cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to run journalctl: %w", err)
+	}
+	out := make(chan LogEntry)
+	s := &LogStream{C: out, done: make(chan struct{})}
+	go func() {
+		defer close(s.done)
+		defer close(out)
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for scanner.Scan() {
+			e, err := parseLogEntry(scanner.Bytes())
+			if err != nil {
+				s.err = err
+				// journalctl would block on a full pipe: stop it and drain what it already wrote
+				cmd.Process.Kill()
+				io.Copy(io.Discard, stdout)
+				break
+			}
+			select {
+			case out <- e:
+			case <-ctx.Done():
+			}
+		}
+		if s.err == nil {
+			s.err = scanner.Err()
+		}
+		waitErr := cmd.Wait()
+		if s.err == nil && ctx.Err() == nil && waitErr != nil {
+			s.err = fmt.Errorf("journalctl failed: %w", waitErr)
+		}
+	}()
+	return s, nil
+}
+
+// parseLogEntry parses a journalctl JSON entry: fields are strings, arrays of bytes
+// for binary values, or arrays of those when a field has several values (first one is kept).
+func parseLogEntry(line []byte) (e LogEntry, err error) {
+	var raw map[string]json.RawMessage
+	if err = json.Unmarshal(line, &raw); err != nil {
+		return e, fmt.Errorf("invalid journal entry: %w", err)
+	}
+	e.Fields = make(map[string]string, len(raw))
+	for name, value := range raw {
+		if v, ok := fieldValue(value); ok {
+			e.Fields[name] = v
+		}
+	}
+	if usec, err := strconv.ParseInt(e.Fields["__REALTIME_TIMESTAMP"], 10, 64); err == nil {
+		e.Time = time.UnixMicro(usec)
+	}
+	e.Priority, _ = strconv.Atoi(e.Fields["PRIORITY"])
+	e.PID, _ = strconv.Atoi(e.Fields["_PID"])
+	e.Message = e.Fields["MESSAGE"]
+	return e, nil
+}
+
+func fieldValue(raw json.RawMessage) (string, bool) {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s, true
+	}
+	var ints []int
+	if json.Unmarshal(raw, &ints) == nil {
+		b := make([]byte, len(ints))
+		for i, v := range ints {
+			b[i] = byte(v)
+		}
+		return string(b), true
+	}
+	var multi []json.RawMessage
+	if json.Unmarshal(raw, &multi) == nil && len(multi) > 0 {
+		return fieldValue(multi[0])
+	}
+	return "", false
+}
@@ -0,0 +1,37 @@
+package systemd1
+
+import "context"
+
+// MountOption holds the mount options of a partition of a disk image, see MountImageUnit.
+type MountOption struct {
+	Partition string // partition name (eg: root, usr, home), see systemd.exec(5) MountImages=
+	Options   string // comma separated mount options
+}
+
+// BindMountUnit bind mounts source from the host into the mount namespace of the running unit name
+// at destination (systemd v248 or later). The unit must have its own mount namespace.
+// ctx: Context to use
+// name: unit name
+// source: host path
+// destination: path inside the unit mount namespace
+// readOnly: make the bind mount read-only
+// mkdir: create destination if missing
+func (c *Conn) BindMountUnit(ctx context.Context, name, source, destination string, readOnly, mkdir bool) error {
+	return c.Call(ctx, "BindMountUnit", name, source, destination, readOnly, mkdir).Store()
+}
+
+// MountImageUnit mounts the disk image source into the mount namespace of the running unit name
+// at destination (systemd v248 or later). The unit must have its own mount namespace.
+// ctx: Context to use
+// name: unit name
+// source: host path of the disk image
+// destination: path inside the unit mount namespace
+// readOnly: mount the image read-only
+// mkdir: create destination if missing
+// options: per partition mount options
+func (c *Conn) MountImageUnit(ctx context.Context, name, source, destination string, readOnly, mkdir bool, options ...MountOption) error {
+	if options == nil {
+		options = []MountOption{}
+	}
+	return c.Call(ctx, "MountImageUnit", name, source, destination, readOnly, mkdir, options).Store()
+}
@@ -0,0 +1,111 @@
+package systemd1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/iguanesolutions/go-systemd/v6/internal/cgroupfs"
+	"github.com/iguanesolutions/go-systemd/v6/unitfile"
+)
+
+// GetUnitByPID returns the unit object path of the unit the process pid belongs to.
+// ctx: Context to use
+// pid: process ID, 0 being the caller as seen by the manager
+func (c *Conn) GetUnitByPID(ctx context.Context, pid uint32) (path dbus.ObjectPath, err error) {
+	err = c.Call(ctx, "GetUnitByPID", pid).Store(&path)
+	return
+}
+
+// UnitByPID returns the unit the process pid belongs to.
+// ctx: Context to use
+// pid: process ID
+func (c *Conn) UnitByPID(ctx context.Context, pid uint32) (*Unit, error) {
+	path, err := c.GetUnitByPID(ctx, pid)
+	if err != nil {
+		return nil, err
+	}
+	u := c.unit("", path)
+	id, err := u.Property(ctx, unitInterface, "Id")
+	if err != nil {
+		return nil, err
+	}
+	u.Name, _ = id.Value().(string)
+	return u, nil
+}
+
+// CurrentUnit returns the unit the calling process belongs to.
+// ctx: Context to use
+func (c *Conn) CurrentUnit(ctx context.Context) (*Unit, error) {
+	return c.UnitByPID(ctx, uint32(os.Getpid()))
+}
+
+// AttachProcessesToUnit moves the processes pids into the control group of the unit name,
+// or into its sub cgroup subcgroup (relative path, empty for the unit cgroup itself).
+// Unlike writing to cgroupfs directly it complies with the delegation rules: the caller must be
+// privileged or own both the processes and the delegated unit (Delegate=yes) when using subcgroup.
+// ctx: Context to use
+// name: unit name
+// subcgroup: sub cgroup path of the unit cgroup, empty for none
+// pids: processes IDs
+func (c *Conn) AttachProcessesToUnit(ctx context.Context, name, subcgroup string, pids ...uint32) error {
+	if len(pids) == 0 {
+		return errors.New("no processes to attach")
+	}
+	return c.Call(ctx, "AttachProcessesToUnit", name, subcgroup, pids).Store()
+}
+
+// CgroupUnit describes the units a process belongs to, as found in its cgroup path.
+type CgroupUnit struct {
+	Cgroup   string // cgroup path, eg: /user.slice/user-1000.slice/user@1000.service/app.slice/foo.service
+	Slice    string // slice of Unit, eg: user-1000.slice
+	Unit     string // unit of the system manager, eg: user@1000.service
+	UserUnit string // unit of the user manager when Unit is one, eg: foo.service
+}
+
+// ErrNoUnit is returned when a process does not belong to any unit (eg: systemd is not PID 1).
+var ErrNoUnit = errors.New("process does not belong to any unit")
+
+// CurrentCgroupUnit returns the units the calling process belongs to by parsing /proc/self/cgroup,
+// it does not need a connection to the manager.
+func CurrentCgroupUnit() (CgroupUnit, error) {
+	return PIDCgroupUnit(0)
+}
+
+// PIDCgroupUnit returns the units the process pid (0 being the caller) belongs to by parsing /proc/<pid>/cgroup.
+func PIDCgroupUnit(pid int) (CgroupUnit, error) {
+	cgroup, err := cgroupfs.Read(pid)
+	if err != nil {
+		return CgroupUnit{}, err
+	}
+	return parseCgroupUnit(cgroup)
+}
+
+func parseCgroupUnit(cgroup string) (cu CgroupUnit, err error) {
+	cu.Cgroup = cgroup
+	for _, elem := range strings.Split(strings.Trim(cgroup, "/"), "/") {
+		switch {
+		case strings.HasSuffix(elem, ".slice"):
+			if cu.Unit == "" {
+				cu.Slice = elem
+			}
+		case !unitfile.IsValidName(elem):
+			// not a unit (eg: sub cgroup of a delegated unit)
+		case cu.Unit == "":
+			cu.Unit = elem
+			if !strings.HasPrefix(elem, "user@") || !strings.HasSuffix(elem, ".service") {
+				return
+			}
+		default:
+			cu.UserUnit = elem
+			return
+		}
+	}
+	if cu.Unit == "" {
+		err = fmt.Errorf("%w: cgroup %q", ErrNoUnit, cgroup)
+	}
+	return
+}
@@ -0,0 +1,144 @@
+package systemd1
+
+import (
+	"errors"
+	"net/netip"
+	"syscall"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Property is a unit property as used by StartTransientUnit and SetUnitProperties.
+// Use the Prop* functions to build them, or Prop for any other property.
+type Property struct {
+	Name  string
+	Value dbus.Variant
+}
+
+// Prop returns a raw property, value must be of the dbus type expected by systemd for name.
+func Prop(name string, value interface{}) Property {
+	return Property{
+		Name:  name,
+		Value: dbus.MakeVariant(value),
+	}
+}
+
+// execCommand is the dbus (sasb) representation of an Exec*= command line.
+type execCommand struct {
+	Path          string   // binary path
+	Args          []string // argv, including argv[0]
+	IgnoreFailure bool     // like the "-" prefix in unit files
+}
+
+// PropExecStart returns an ExecStart= property running argv, argv[0] being the binary path.
+// It fails if argv is empty.
+func PropExecStart(argv []string, ignoreFailure bool) (Property, error) {
+	if len(argv) == 0 {
+		return Property{}, errors.New("empty ExecStart= command line")
+	}
+	return Prop("ExecStart", []execCommand{{
+		Path:          argv[0],
+		Args:          argv,
+		IgnoreFailure: ignoreFailure,
+	}}), nil
+}
+
+// PropDescription returns a Description= property.
+func PropDescription(description string) Property {
+	return Prop("Description", description)
+}
+
+// PropType returns a service Type= property (simple, exec, oneshot, notify...).
+func PropType(serviceType string) Property {
+	return Prop("Type", serviceType)
+}
+
+// PropRemainAfterExit returns a RemainAfterExit= property.
+func PropRemainAfterExit(remain bool) Property {
+	return Prop("RemainAfterExit", remain)
+}
+
+// PropEnvironment returns an Environment= property, each entry being formatted as KEY=value.
+func PropEnvironment(env ...string) Property {
+	return Prop("Environment", env)
+}
+
+// PropWorkingDirectory returns a WorkingDirectory= property.
+func PropWorkingDirectory(dir string) Property {
+	return Prop("WorkingDirectory", dir)
+}
+
+// PropUser returns a User= property.
+func PropUser(user string) Property {
+	return Prop("User", user)
+}
+
+// PropGroup returns a Group= property.
+func PropGroup(group string) Property {
+	return Prop("Group", group)
+}
+
+// PropSlice returns a Slice= property.
+func PropSlice(slice string) Property {
+	return Prop("Slice", slice)
+}
+
+// PropDelegate returns a Delegate= property.
+func PropDelegate(delegate bool) Property {
+	return Prop("Delegate", delegate)
+}
+
+// PropCPUQuota returns a CPUQuota= property, percent being relative to one CPU (200 means two CPUs).
+func PropCPUQuota(percent uint64) Property {
+	// CPUQuota= is exposed as CPUQuotaPerSecUSec on dbus
+	return Prop("CPUQuotaPerSecUSec", percent*10000)
+}
+
+// PropMemoryMax returns a MemoryMax= property in bytes.
+func PropMemoryMax(bytes uint64) Property {
+	return Prop("MemoryMax", bytes)
+}
+
+// PropTasksMax returns a TasksMax= property.
+func PropTasksMax(tasks uint64) Property {
+	return Prop("TasksMax", tasks)
+}
+
+// PropCollectMode returns a CollectMode= property (inactive, inactive-or-failed),
+// inactive-or-failed allows to get rid of failed transient units automatically.
+func PropCollectMode(mode string) Property {
+	return Prop("CollectMode", mode)
+}
+
+// ipAddressPrefix is the dbus (iayu) representation of an IP address prefix.
+type ipAddressPrefix struct {
+	Family    int32
+	Address   []byte
+	PrefixLen uint32
+}
+
+func ipAddressPrefixes(prefixes []netip.Prefix) []ipAddressPrefix {
+	list := make([]ipAddressPrefix, len(prefixes))
+	for i, p := range prefixes {
+		family := int32(syscall.AF_INET6)
+		if p.Addr().Is4() {
+			family = syscall.AF_INET
+		}
+		list[i] = ipAddressPrefix{
+			Family:    family,
+			Address:   p.Addr().AsSlice(),
+			PrefixLen: uint32(p.Bits()),
+		}
+	}
+	return list
+}
+
+// PropIPAddressAllow returns an IPAddressAllow= property.
+func PropIPAddressAllow(prefixes ...netip.Prefix) Property {
+	return Prop("IPAddressAllow", ipAddressPrefixes(prefixes))
+}
+
+// PropIPAddressDeny returns an IPAddressDeny= property.
+func PropIPAddressDeny(prefixes ...netip.Prefix) Property {
+	return Prop("IPAddressDeny", ipAddressPrefixes(prefixes))
+}
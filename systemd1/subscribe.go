@@ -0,0 +1,319 @@
+package systemd1
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+const (
+	unitPathPrefix          = dbusPath + "/unit/"
+	propertiesChangedSignal = "org.freedesktop.DBus.Properties.PropertiesChanged"
+)
+
+// EventType is the type of an Event.
+type EventType int
+
+const (
+	UnitNew EventType = iota
+	UnitRemoved
+	JobNew
+	JobRemoved
+	PropertiesChanged
+)
+
+func (t EventType) String() string {
+	switch t {
+	case UnitNew:
+		return "UnitNew"
+	case UnitRemoved:
+		return "UnitRemoved"
+	case JobNew:
+		return "JobNew"
+	case JobRemoved:
+		return "JobRemoved"
+	case PropertiesChanged:
+		return "PropertiesChanged"
+	default:
+		return "EventType(" + strconv.Itoa(int(t)) + ")"
+	}
+}
+
+// Event is a manager signal received thru a Subscription.
+type Event struct {
+	Type       EventType
+	Unit       string                  // unit name
+	Path       dbus.ObjectPath         // unit object path, or job object path for job events
+	JobID      uint32                  // job events only
+	JobResult  JobResult               // JobRemoved only
+	Interface  string                  // PropertiesChanged only: interface of the changed properties
+	Properties map[string]dbus.Variant // PropertiesChanged only: changed properties
+}
+
+// Subscription delivers manager events, see Conn.Subscribe.
+type Subscription struct {
+	// C receives the events, it is closed by Close.
+	C <-chan Event
+
+	c       *Conn
+	out     chan Event
+	units   map[string]struct{}
+	mu      sync.Mutex
+	pending []Event
+	max     int
+	dropped uint64
+	wake    chan struct{}
+	done    chan struct{}
+	closed  bool
+}
+
+// SubscribeOption configures a Subscription, see Conn.Subscribe.
+type SubscribeOption func(s *Subscription) error
+
+// WithUnits only delivers the events related to the given unit names.
+func WithUnits(names ...string) SubscribeOption {
+	return func(s *Subscription) error {
+		if len(names) == 0 {
+			return errors.New("no unit names")
+		}
+		s.units = make(map[string]struct{}, len(names))
+		for _, name := range names {
+			s.units[name] = struct{}{}
+		}
+		return nil
+	}
+}
+
+// WithBuffer sets the buffer size of the subscription channel, 64 by default.
+func WithBuffer(size int) SubscribeOption {
+	return func(s *Subscription) error {
+		if size < 0 {
+			return fmt.Errorf("invalid buffer size: %d", size)
+		}
+		s.out = make(chan Event, size)
+		return nil
+	}
+}
+
+// DefaultMaxPending is the number of events a Subscription keeps aside by default
+// while its subscriber lags behind, see WithMaxPending.
+const DefaultMaxPending = 4096
+
+// WithMaxPending sets the number of events kept aside while the subscription channel is full,
+// DefaultMaxPending by default. See Subscribe for what happens once it is reached.
+func WithMaxPending(n int) SubscribeOption {
+	return func(s *Subscription) error {
+		if n <= 0 {
+			return fmt.Errorf("invalid max pending events: %d", n)
+		}
+		s.max = n
+		return nil
+	}
+}
+
+// Subscribe returns a new Subscription delivering UnitNew, UnitRemoved, JobNew, JobRemoved and
+// PropertiesChanged events. Events never block the connection: when the subscriber lags behind and
+// the channel is full, they are kept aside and consecutive PropertiesChanged events of the same
+// unit and interface are coalesced into one (latest values win). Up to WithMaxPending events are kept
+// aside: past it, PropertiesChanged events are merged into the pending one of the same unit and interface,
+// so the latest state of each unit is still delivered, and the other events are dropped (see Dropped).
+// The Properties maps are shared between subscriptions and must not be modified.
+// Close must be called once done with it.
+func (c *Conn) Subscribe(opts ...SubscribeOption) (*Subscription, error) {
+	s := &Subscription{
+		c:    c,
+		wake: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		if err := opt(s); err != nil {
+			return nil, err
+		}
+	}
+	if s.out == nil {
+		s.out = make(chan Event, 64)
+	}
+	if s.max == 0 {
+		s.max = DefaultMaxPending
+	}
+	s.C = s.out
+	if err := c.signals(); err != nil {
+		return nil, err
+	}
+	if err := c.propertiesSignals(); err != nil {
+		return nil, err
+	}
+	c.subsMu.Lock()
+	c.subs[s] = struct{}{}
+	c.subsMu.Unlock()
+	go s.pump()
+	return s, nil
+}
+
+// Dropped returns the number of events dropped because the subscriber lagged behind,
+// a subscriber seeing it increase should read the state of the units again.
+func (s *Subscription) Dropped() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dropped
+}
+
+// Close stops the subscription and closes its channel.
+func (s *Subscription) Close() {
+	s.c.subsMu.Lock()
+	delete(s.c.subs, s)
+	s.c.subsMu.Unlock()
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.done)
+	}
+	s.mu.Unlock()
+}
+
+func (s *Subscription) deliver(e Event) {
+	if s.units != nil {
+		if _, ok := s.units[e.Unit]; !ok {
+			return
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	if n := len(s.pending); n > 0 && e.Type == PropertiesChanged {
+		// only the last pending event may be coalesced, so events are never reordered
+		if p := &s.pending[n-1]; p.Type == PropertiesChanged && p.Path == e.Path && p.Interface == e.Interface {
+			p.Properties = mergeProperties(p.Properties, e.Properties)
+			return
+		}
+	}
+	if s.max > 0 && len(s.pending) >= s.max {
+		// full: keep the latest state of the unit, even out of order, drop anything else
+		if e.Type == PropertiesChanged {
+			for i := len(s.pending) - 1; i >= 0; i-- {
+				if p := &s.pending[i]; p.Type == PropertiesChanged && p.Path == e.Path && p.Interface == e.Interface {
+					p.Properties = mergeProperties(p.Properties, e.Properties)
+					return
+				}
+			}
+		}
+		s.dropped++
+		return
+	}
+	s.pending = append(s.pending, e)
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// mergeProperties returns the properties of p updated with e, the properties maps are shared
+// by all the subscriptions so they are merged into a copy.
+func mergeProperties(p, e map[string]dbus.Variant) map[string]dbus.Variant {
+	merged := make(map[string]dbus.Variant, len(p)+len(e))
+	for k, v := range p {
+		merged[k] = v
+	}
+	for k, v := range e {
+		merged[k] = v
+	}
+	return merged
+}
+
+func (s *Subscription) pump() {
+	defer close(s.out)
+	for {
+		s.mu.Lock()
+		if len(s.pending) == 0 {
+			s.mu.Unlock()
+			select {
+			case <-s.wake:
+				continue
+			case <-s.done:
+				return
+			}
+		}
+		e := s.pending[0]
+		s.pending = s.pending[1:]
+		s.mu.Unlock()
+		select {
+		case s.out <- e:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// propertiesSignals adds, once, the match for the units PropertiesChanged signals.
+func (c *Conn) propertiesSignals() error {
+	c.propsOnce.Do(func() {
+		if c.private {
+			return
+		}
+		err := c.conn.AddMatchSignal(
+			dbus.WithMatchPathNamespace(dbusPath+"/unit"),
+			dbus.WithMatchInterface("org.freedesktop.DBus.Properties"),
+			dbus.WithMatchMember("PropertiesChanged"),
+		)
+		if err != nil {
+			c.propsErr = fmt.Errorf("failed to add properties signals match: %w", err)
+		}
+	})
+	return c.propsErr
+}
+
+func (c *Conn) publish(sig *dbus.Signal) {
+	var e Event
+	switch sig.Name {
+	case dbusInterface + ".UnitNew", dbusInterface + ".UnitRemoved":
+		if dbus.Store(sig.Body, &e.Unit, &e.Path) != nil {
+			return
+		}
+		if e.Type = UnitNew; sig.Name == dbusInterface+".UnitRemoved" {
+			e.Type = UnitRemoved
+		}
+	case dbusInterface + ".JobNew":
+		if dbus.Store(sig.Body, &e.JobID, &e.Path, &e.Unit) != nil {
+			return
+		}
+		e.Type = JobNew
+	case dbusInterface + ".JobRemoved":
+		var result string
+		if dbus.Store(sig.Body, &e.JobID, &e.Path, &e.Unit, &result) != nil {
+			return
+		}
+		e.Type = JobRemoved
+		e.JobResult = JobResult(result)
+	case propertiesChangedSignal:
+		var invalidated []string
+		if dbus.Store(sig.Body, &e.Interface, &e.Properties, &invalidated) != nil {
+			return
+		}
+		e.Type = PropertiesChanged
+		e.Path = sig.Path
+		e.Unit = unitNameFromPath(sig.Path)
+	default:
+		return
+	}
+	c.subsMu.Lock()
+	defer c.subsMu.Unlock()
+	for s := range c.subs {
+		s.deliver(e)
+	}
+}
+
+// unitNameFromPath unescapes a unit object path back to the unit name.
+func unitNameFromPath(path dbus.ObjectPath) string {
+	escaped, ok := strings.CutPrefix(string(path), unitPathPrefix)
+	if !ok {
+		return ""
+	}
+	return sysdbus.UnescapePathElement(escaped)
+}
@@ -0,0 +1,191 @@
+package systemd1
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestUnitNameFromPath(t *testing.T) {
+	for path, name := range map[dbus.ObjectPath]string{
+		"/org/freedesktop/systemd1/unit/nginx_2eservice":                   "nginx.service",
+		"/org/freedesktop/systemd1/unit/backup_40home_2duser_2eservice":    "backup@home-user.service",
+		"/org/freedesktop/systemd1/unit/dev_2ddisk_2dby_5cx2dlabel_2eswap": `dev-disk-by\x2dlabel.swap`,
+		"/org/freedesktop/systemd1/job/42":                                 "",
+	} {
+		if got := unitNameFromPath(path); got != name {
+			t.Errorf("unitNameFromPath(%q) = %q, expected %q", path, got, name)
+		}
+	}
+}
+
+func TestSubscriptionCoalescing(t *testing.T) {
+	s := &Subscription{
+		wake: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+	path := dbus.ObjectPath(unitPathPrefix + "a_2eservice")
+	// published maps are shared with the other subscriptions
+	shared := map[string]dbus.Variant{"ActiveState": dbus.MakeVariant("activating")}
+	s.deliver(Event{Type: PropertiesChanged, Unit: "a.service", Path: path, Interface: unitInterface, Properties: shared})
+	s.deliver(Event{Type: PropertiesChanged, Unit: "a.service", Path: path, Interface: unitInterface,
+		Properties: map[string]dbus.Variant{"ActiveState": dbus.MakeVariant("active"), "SubState": dbus.MakeVariant("running")}})
+	if len(s.pending) != 1 {
+		t.Fatal("events should have been coalesced", s.pending)
+	}
+	if state := s.pending[0].Properties["ActiveState"].Value(); state != "active" {
+		t.Error("latest value should win, got", state)
+	}
+	if len(shared) != 1 || shared["ActiveState"].Value() != "activating" {
+		t.Error("published properties should not be modified", shared)
+	}
+	s.deliver(Event{Type: JobRemoved, Unit: "a.service", JobID: 1})
+	s.deliver(Event{Type: PropertiesChanged, Unit: "a.service", Path: path, Interface: unitInterface,
+		Properties: map[string]dbus.Variant{"ActiveState": dbus.MakeVariant("inactive")}})
+	if len(s.pending) != 3 || s.pending[1].Type != JobRemoved {
+		t.Fatal("events should not be coalesced across other events", s.pending)
+	}
+	s.pending = s.pending[:1]
+	s.units = map[string]struct{}{"b.service": {}}
+	s.deliver(Event{Type: UnitNew, Unit: "a.service"})
+	if len(s.pending) != 1 {
+		t.Error("filtered unit event should not be delivered")
+	}
+	// once full, only the latest state of the units is kept
+	s.units = nil
+	s.max = 2
+	s.deliver(Event{Type: JobRemoved, Unit: "a.service", JobID: 2})
+	s.deliver(Event{Type: JobRemoved, Unit: "a.service", JobID: 3})
+	s.deliver(Event{Type: PropertiesChanged, Unit: "a.service", Path: path, Interface: unitInterface,
+		Properties: map[string]dbus.Variant{"ActiveState": dbus.MakeVariant("failed")}})
+	if len(s.pending) != 2 || s.Dropped() != 1 {
+		t.Fatal("overflowing event should have been dropped", s.pending, s.Dropped())
+	}
+	if state := s.pending[0].Properties["ActiveState"].Value(); state != "failed" {
+		t.Error("latest state should be kept once full, got", state)
+	}
+}
+
+func TestPropExecStart(t *testing.T) {
+	p, err := PropExecStart([]string{"/bin/true", "-v"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmds, ok := p.Value.Value().([]execCommand); !ok || len(cmds) != 1 || cmds[0].Path != "/bin/true" || len(cmds[0].Args) != 2 {
+		t.Errorf("unexpected ExecStart= value %v", p.Value)
+	}
+	if _, err = PropExecStart(nil, false); err == nil {
+		t.Error("expected an error for an empty command line")
+	}
+}
+
+func TestCgroupUnit(t *testing.T) {
+	for cgroup, expected := range map[string]CgroupUnit{
+		"/system.slice/nginx.service":          {Slice: "system.slice", Unit: "nginx.service"},
+		"/system.slice/docker.service/payload": {Slice: "system.slice", Unit: "docker.service"},
+		"/user.slice/user-1000.slice/user@1000.service/app.slice/foo.service": {
+			Slice: "user-1000.slice", Unit: "user@1000.service", UserUnit: "foo.service"},
+		"/init.scope": {Unit: "init.scope"},
+	} {
+		expected.Cgroup = cgroup
+		if cu, err := parseCgroupUnit(cgroup); err != nil || cu != expected {
+			t.Errorf("parseCgroupUnit(%q) = %+v, %v", cgroup, cu, err)
+		}
+	}
+	if _, err := parseCgroupUnit("/"); err == nil {
+		t.Error("root cgroup should not belong to any unit")
+	}
+}
+
+func TestParseLogEntry(t *testing.T) {
+	e, err := parseLogEntry([]byte(`{"__REALTIME_TIMESTAMP":"1700000000000000","PRIORITY":"3","_PID":"42",` +
+		`"MESSAGE":[104,105,0],"_SYSTEMD_UNIT":["a.service","b.service"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Priority != 3 || e.PID != 42 || !e.Time.Equal(time.UnixMicro(1700000000000000)) {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+	if e.Message != "hi\x00" || e.Fields["_SYSTEMD_UNIT"] != "a.service" {
+		t.Errorf("unexpected fields: %q", e.Fields)
+	}
+}
+
+func TestLogsParseError(t *testing.T) {
+	// an invalid entry followed by more output than the pipe can hold
+	script := filepath.Join(t.TempDir(), "journalctl")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho garbage\nexec yes '{}'\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	defer func(previous string) { journalctl = previous }(journalctl)
+	journalctl = script
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	u := &Unit{Name: "test.service", c: &Conn{}}
+	if _, err := u.RecentLogs(ctx, time.Time{}, 7); err == nil || ctx.Err() != nil {
+		t.Fatal("expected a parse error before the deadline, got", err)
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	for version, expected := range map[string][2]int{
+		"255.4-1ubuntu8": {255, 4},
+		"v256-rc1":       {256, 0},
+		"256~rc1":        {256, 0},
+		"252-22.el9":     {252, 0},
+		"249":            {249, 0},
+	} {
+		v, err := ParseVersion(version, "+PAM -SELINUX default-hierarchy=unified")
+		if err != nil {
+			t.Errorf("%s: %v", version, err)
+			continue
+		}
+		if v.Major != expected[0] || v.Minor != expected[1] {
+			t.Errorf("%s: expected %v, got %d.%d", version, expected, v.Major, v.Minor)
+		}
+		if !v.HasFeature("PAM") || v.HasFeature("SELINUX") || v.Settings["default-hierarchy"] != "unified" {
+			t.Errorf("%s: unexpected features %v %v", version, v.Features, v.Settings)
+		}
+	}
+	v, _ := ParseVersion("252-22.el9", "")
+	if v.SupportsNotifyReload() || !v.SupportsCredentials() {
+		t.Errorf("unexpected capabilities of %s", v)
+	}
+	if _, err := ParseVersion("unknown", ""); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestConditionReport(t *testing.T) {
+	// a(sbbsi) as received from the bus
+	r, err := newConditionReport(map[string]dbus.Variant{
+		"ConditionResult":    dbus.MakeVariant(false),
+		"ConditionTimestamp": dbus.MakeVariant(uint64(1700000000000000)),
+		"Conditions": dbus.MakeVariant([][]interface{}{
+			{"ConditionPathExists", false, true, "/etc/foo", int32(-1)},
+			{"ConditionVirtualization", true, false, "container", int32(1)},
+		}),
+		"AssertResult": dbus.MakeVariant(true),
+		"Asserts":      dbus.MakeVariant([][]interface{}{}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.ConditionResult || !r.AssertResult || r.AssertTimestamp != (time.Time{}) || !r.ConditionTimestamp.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("unexpected report: %+v", r)
+	}
+	failed := r.Failed()
+	if len(r.Conditions) != 2 || len(r.Asserts) != 0 || len(failed) != 1 {
+		t.Fatalf("unexpected conditions: %+v %+v", r.Conditions, r.Asserts)
+	}
+	if s := failed[0].String(); s != "ConditionPathExists=!/etc/foo" {
+		t.Errorf("unexpected failed condition: %s", s)
+	}
+	if s := r.Conditions[1].String(); s != "ConditionVirtualization=|container" || !r.Conditions[1].Passed() {
+		t.Errorf("unexpected condition: %s", s)
+	}
+}
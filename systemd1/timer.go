@@ -0,0 +1,101 @@
+package systemd1
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// TimerSpec describes when a transient timer elapses, see systemd.timer(5).
+type TimerSpec struct {
+	OnCalendar         []string      // OnCalendar= expressions
+	OnActiveSec        time.Duration // OnActiveSec=, relative to the timer activation
+	OnBootSec          time.Duration // OnBootSec=
+	OnStartupSec       time.Duration // OnStartupSec=
+	OnUnitActiveSec    time.Duration // OnUnitActiveSec=, relative to the last activation of the service
+	OnUnitInactiveSec  time.Duration // OnUnitInactiveSec=
+	AccuracySec        time.Duration // AccuracySec=, systemd's default (1min) if 0
+	RandomizedDelaySec time.Duration // RandomizedDelaySec=
+	Persistent         bool          // Persistent=, catch up missed OnCalendar= runs
+	RemainAfterElapse  *bool         // RemainAfterElapse=, systemd's default (true) if nil
+}
+
+// timerCalendar is the dbus (ss) representation of a calendar timer.
+type timerCalendar struct {
+	Base string
+	Spec string
+}
+
+// timerMonotonic is the dbus (st) representation of a monotonic timer.
+type timerMonotonic struct {
+	Base string
+	USec uint64
+}
+
+// Properties returns the timer unit properties described by t.
+func (t TimerSpec) Properties() ([]Property, error) {
+	var (
+		calendar  []timerCalendar
+		monotonic []timerMonotonic
+	)
+	for _, spec := range t.OnCalendar {
+		calendar = append(calendar, timerCalendar{Base: "OnCalendar", Spec: spec})
+	}
+	for _, m := range []struct {
+		base string
+		d    time.Duration
+	}{
+		{"OnActiveUSec", t.OnActiveSec},
+		{"OnBootUSec", t.OnBootSec},
+		{"OnStartupUSec", t.OnStartupSec},
+		{"OnUnitActiveUSec", t.OnUnitActiveSec},
+		{"OnUnitInactiveUSec", t.OnUnitInactiveSec},
+	} {
+		if m.d > 0 {
+			monotonic = append(monotonic, timerMonotonic{Base: m.base, USec: uint64(m.d.Microseconds())})
+		}
+	}
+	if len(calendar) == 0 && len(monotonic) == 0 {
+		return nil, errors.New("timer never elapses: no calendar nor monotonic trigger")
+	}
+	props := []Property{
+		Prop("Persistent", t.Persistent),
+	}
+	if len(calendar) > 0 {
+		props = append(props, Prop("TimersCalendar", calendar))
+	}
+	if len(monotonic) > 0 {
+		props = append(props, Prop("TimersMonotonic", monotonic))
+	}
+	if t.AccuracySec > 0 {
+		props = append(props, Prop("AccuracyUSec", uint64(t.AccuracySec.Microseconds())))
+	}
+	if t.RandomizedDelaySec > 0 {
+		props = append(props, Prop("RandomizedDelayUSec", uint64(t.RandomizedDelaySec.Microseconds())))
+	}
+	if t.RemainAfterElapse != nil {
+		props = append(props, Prop("RemainAfterElapse", *t.RemainAfterElapse))
+	}
+	return props, nil
+}
+
+// StartTransientTimer creates and starts the transient timer name.timer, which activates
+// the transient service name.service created along with it from service properties
+// (see PropExecStart and friends), like systemd-run --on-calendar does.
+// ctx: Context to use
+// name: base name of both units, without suffix
+// timer: when the timer elapses
+// service: properties of the activated service
+func (c *Conn) StartTransientTimer(ctx context.Context, name string, timer TimerSpec, service []Property) (*Job, error) {
+	props, err := timer.Properties()
+	if err != nil {
+		return nil, err
+	}
+	if service == nil {
+		service = []Property{}
+	}
+	return c.StartTransientUnit(ctx, name+".timer", ModeFail, props, []AuxUnit{{
+		Name:       name + ".service",
+		Properties: service,
+	}})
+}
@@ -0,0 +1,133 @@
+package systemd1
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+// BootTimes is the boot performance report of the manager, like "systemd-analyze time" prints it.
+// Phases which did not happen (eg: no initrd, firmware times unknown, in a container) are zero.
+type BootTimes struct {
+	Finished bool // false while the boot is not finished yet, then Userspace and Total are zero
+
+	FirmwareTimestamp  time.Time
+	LoaderTimestamp    time.Time
+	KernelTimestamp    time.Time
+	InitRDTimestamp    time.Time
+	UserspaceTimestamp time.Time
+	FinishTimestamp    time.Time
+
+	Firmware  time.Duration // time spent in the firmware
+	Loader    time.Duration // time spent in the boot loader
+	Kernel    time.Duration // time spent in the kernel before the initrd, or userspace without initrd
+	InitRD    time.Duration // time spent in the initrd
+	Userspace time.Duration // time spent in userspace until the boot finished
+	Total     time.Duration // sum of the above
+}
+
+// BootTimes returns the boot performance report of the manager.
+// ctx: Context to use
+func (c *Conn) BootTimes(ctx context.Context) (*BootTimes, error) {
+	props, err := sysdbus.GetAllProperties(ctx, c.obj, dbusInterface)
+	if err != nil {
+		return nil, err
+	}
+	mono := func(name string) time.Duration {
+		return time.Duration(sysdbus.Prop[uint64](props, name+"Monotonic")) * time.Microsecond
+	}
+	bt := &BootTimes{
+		FirmwareTimestamp:  sysdbus.Timestamp(props, "FirmwareTimestamp"),
+		LoaderTimestamp:    sysdbus.Timestamp(props, "LoaderTimestamp"),
+		KernelTimestamp:    sysdbus.Timestamp(props, "KernelTimestamp"),
+		InitRDTimestamp:    sysdbus.Timestamp(props, "InitRDTimestamp"),
+		UserspaceTimestamp: sysdbus.Timestamp(props, "UserspaceTimestamp"),
+		FinishTimestamp:    sysdbus.Timestamp(props, "FinishTimestamp"),
+	}
+	// firmware and loader monotonic timestamps count backwards from the kernel start
+	firmware, loader := mono("FirmwareTimestamp"), mono("LoaderTimestamp")
+	initrd, userspace, finish := mono("InitRDTimestamp"), mono("UserspaceTimestamp"), mono("FinishTimestamp")
+	if firmware > 0 {
+		bt.Firmware = firmware - loader
+	}
+	bt.Loader = loader
+	if initrd > 0 {
+		bt.Kernel = initrd
+		bt.InitRD = userspace - initrd
+	} else {
+		bt.Kernel = userspace
+	}
+	if bt.Finished = finish > 0; bt.Finished {
+		bt.Userspace = finish - userspace
+		bt.Total = firmware + finish
+		if firmware == 0 {
+			bt.Total = loader + finish
+		}
+	}
+	return bt, nil
+}
+
+// UnitTiming is the last activation timing of a unit.
+type UnitTiming struct {
+	Name         string
+	Activating   time.Time     // time the unit started activating (InactiveExitTimestamp)
+	Activated    time.Time     // time the unit became active (ActiveEnterTimestamp)
+	Deactivating time.Time     // time the unit started deactivating (ActiveExitTimestamp)
+	Deactivated  time.Time     // time the unit became inactive (InactiveEnterTimestamp)
+	Time         time.Duration // activation duration, zero if the unit did not finish activating
+}
+
+// Timing returns the last activation timing of the unit.
+// ctx: Context to use
+func (u *Unit) Timing(ctx context.Context) (*UnitTiming, error) {
+	props, err := u.AllProperties(ctx, unitInterface)
+	if err != nil {
+		return nil, err
+	}
+	return unitTiming(props), nil
+}
+
+func unitTiming(props map[string]dbus.Variant) *UnitTiming {
+	t := &UnitTiming{
+		Name:         sysdbus.Prop[string](props, "Id"),
+		Activating:   sysdbus.Timestamp(props, "InactiveExitTimestamp"),
+		Activated:    sysdbus.Timestamp(props, "ActiveEnterTimestamp"),
+		Deactivating: sysdbus.Timestamp(props, "ActiveExitTimestamp"),
+		Deactivated:  sysdbus.Timestamp(props, "InactiveEnterTimestamp"),
+	}
+	// use monotonic timestamps so clock changes during boot do not skew the duration
+	activating := sysdbus.Prop[uint64](props, "InactiveExitTimestampMonotonic")
+	activated := sysdbus.Prop[uint64](props, "ActiveEnterTimestampMonotonic")
+	if activating > 0 && activated > activating {
+		t.Time = time.Duration(activated-activating) * time.Microsecond
+	}
+	return t
+}
+
+// UnitTimings returns the activation timing of every loaded unit which took time to activate,
+// slowest first, like "systemd-analyze blame" prints them.
+// ctx: Context to use
+func (c *Conn) UnitTimings(ctx context.Context) ([]UnitTiming, error) {
+	units, err := c.ListUnits(ctx)
+	if err != nil {
+		return nil, err
+	}
+	timings := make([]UnitTiming, 0, len(units))
+	for _, us := range units {
+		t, err := c.unit(us.Name, us.Path).Timing(ctx)
+		if err != nil {
+			// the unit may have been unloaded meanwhile
+			continue
+		}
+		if t.Time > 0 {
+			timings = append(timings, *t)
+		}
+	}
+	sort.Slice(timings, func(i, j int) bool {
+		return timings[i].Time > timings[j].Time
+	})
+	return timings, nil
+}
@@ -0,0 +1,27 @@
+package systemd1
+
+import "context"
+
+// AuxUnit is an auxiliary transient unit created along with the main one by StartTransientUnit.
+type AuxUnit struct {
+	Name       string
+	Properties []Property
+}
+
+// StartTransientUnit creates and starts a transient unit (like systemd-run does).
+// Use Wait on the returned job to wait for the start to complete: for Type=oneshot services it
+// means the command has exited, the service Result can then be read with GetServiceProperties.
+// ctx: Context to use
+// name: unit name, its suffix gives the unit type (eg: myjob.service)
+// mode: job mode, usually ModeFail
+// properties: unit properties, see the Prop* functions
+// aux: auxiliary units to create along with it, can be nil
+func (c *Conn) StartTransientUnit(ctx context.Context, name string, mode Mode, properties []Property, aux []AuxUnit) (*Job, error) {
+	if properties == nil {
+		properties = []Property{}
+	}
+	if aux == nil {
+		aux = []AuxUnit{}
+	}
+	return c.enqueue(ctx, name, "StartTransientUnit", name, string(mode), properties, aux)
+}
@@ -0,0 +1,174 @@
+package systemd1
+
+import (
+	"context"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+const (
+	unitInterface    = "org.freedesktop.systemd1.Unit"
+	serviceInterface = "org.freedesktop.systemd1.Service"
+)
+
+// Unit represents a unit object of the manager.
+type Unit struct {
+	Name string          // unit name as requested
+	Path dbus.ObjectPath // unit object path
+	c    *Conn
+	obj  dbus.BusObject
+}
+
+// GetUnit returns the unit object path of an already loaded unit.
+// ctx: Context to use
+// name: unit name
+func (c *Conn) GetUnit(ctx context.Context, name string) (path dbus.ObjectPath, err error) {
+	err = c.Call(ctx, "GetUnit", name).Store(&path)
+	return
+}
+
+// LoadUnit returns the unit object path of a unit, loading it if needed.
+// ctx: Context to use
+// name: unit name
+func (c *Conn) LoadUnit(ctx context.Context, name string) (path dbus.ObjectPath, err error) {
+	err = c.Call(ctx, "LoadUnit", name).Store(&path)
+	return
+}
+
+// Unit returns the unit named name, loading it if needed.
+func (c *Conn) Unit(ctx context.Context, name string) (*Unit, error) {
+	path, err := c.LoadUnit(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return c.unit(name, path), nil
+}
+
+func (c *Conn) unit(name string, path dbus.ObjectPath) *Unit {
+	return &Unit{
+		Name: name,
+		Path: path,
+		c:    c,
+		obj:  c.conn.Object(dbusDest, path),
+	}
+}
+
+// Property returns the raw value of a property, iface being the dbus interface
+// holding it (eg: org.freedesktop.systemd1.Unit, org.freedesktop.systemd1.Service).
+func (u *Unit) Property(ctx context.Context, iface, name string) (dbus.Variant, error) {
+	return sysdbus.GetProperty(ctx, u.obj, iface, name)
+}
+
+// AllProperties returns the raw values of every property of the iface interface.
+func (u *Unit) AllProperties(ctx context.Context, iface string) (map[string]dbus.Variant, error) {
+	return sysdbus.GetAllProperties(ctx, u.obj, iface)
+}
+
+// UnitProperties holds the most used properties common to every unit type.
+type UnitProperties struct {
+	ID                     string
+	Description            string
+	LoadState              string
+	ActiveState            string
+	SubState               string
+	FragmentPath           string
+	UnitFileState          string
+	Following              string
+	StateChangeTimestamp   time.Time
+	ActiveEnterTimestamp   time.Time
+	ActiveExitTimestamp    time.Time
+	InactiveEnterTimestamp time.Time
+	InactiveExitTimestamp  time.Time
+}
+
+// Properties returns the typed unit properties.
+func (u *Unit) Properties(ctx context.Context) (*UnitProperties, error) {
+	props, err := u.AllProperties(ctx, unitInterface)
+	if err != nil {
+		return nil, err
+	}
+	return &UnitProperties{
+		ID:                     sysdbus.Prop[string](props, "Id"),
+		Description:            sysdbus.Prop[string](props, "Description"),
+		LoadState:              sysdbus.Prop[string](props, "LoadState"),
+		ActiveState:            sysdbus.Prop[string](props, "ActiveState"),
+		SubState:               sysdbus.Prop[string](props, "SubState"),
+		FragmentPath:           sysdbus.Prop[string](props, "FragmentPath"),
+		UnitFileState:          sysdbus.Prop[string](props, "UnitFileState"),
+		Following:              sysdbus.Prop[string](props, "Following"),
+		StateChangeTimestamp:   sysdbus.Timestamp(props, "StateChangeTimestamp"),
+		ActiveEnterTimestamp:   sysdbus.Timestamp(props, "ActiveEnterTimestamp"),
+		ActiveExitTimestamp:    sysdbus.Timestamp(props, "ActiveExitTimestamp"),
+		InactiveEnterTimestamp: sysdbus.Timestamp(props, "InactiveEnterTimestamp"),
+		InactiveExitTimestamp:  sysdbus.Timestamp(props, "InactiveExitTimestamp"),
+	}, nil
+}
+
+// ServiceProperties holds the most used properties of service units.
+type ServiceProperties struct {
+	Type                   string
+	Result                 string
+	StatusText             string
+	MainPID                uint32
+	ControlPID             uint32
+	ExecMainStartTimestamp time.Time
+	ExecMainExitTimestamp  time.Time
+	ExecMainCode           int32
+	ExecMainStatus         int32
+	NRestarts              uint32
+	MemoryCurrent          uint64 // bytes, math.MaxUint64 if unknown
+	CPUUsageNSec           uint64 // nanoseconds, math.MaxUint64 if unknown
+	TasksCurrent           uint64 // math.MaxUint64 if unknown
+}
+
+// ServiceProperties returns the typed service properties, the unit must be a service.
+func (u *Unit) ServiceProperties(ctx context.Context) (*ServiceProperties, error) {
+	props, err := u.AllProperties(ctx, serviceInterface)
+	if err != nil {
+		return nil, err
+	}
+	return &ServiceProperties{
+		Type:                   sysdbus.Prop[string](props, "Type"),
+		Result:                 sysdbus.Prop[string](props, "Result"),
+		StatusText:             sysdbus.Prop[string](props, "StatusText"),
+		MainPID:                sysdbus.Prop[uint32](props, "MainPID"),
+		ControlPID:             sysdbus.Prop[uint32](props, "ControlPID"),
+		ExecMainStartTimestamp: sysdbus.Timestamp(props, "ExecMainStartTimestamp"),
+		ExecMainExitTimestamp:  sysdbus.Timestamp(props, "ExecMainExitTimestamp"),
+		ExecMainCode:           sysdbus.Prop[int32](props, "ExecMainCode"),
+		ExecMainStatus:         sysdbus.Prop[int32](props, "ExecMainStatus"),
+		NRestarts:              sysdbus.Prop[uint32](props, "NRestarts"),
+		MemoryCurrent:          sysdbus.Prop[uint64](props, "MemoryCurrent"),
+		CPUUsageNSec:           sysdbus.Prop[uint64](props, "CPUUsageNSec"),
+		TasksCurrent:           sysdbus.Prop[uint64](props, "TasksCurrent"),
+	}, nil
+}
+
+// GetUnitProperties returns the typed properties of the unit name.
+func (c *Conn) GetUnitProperties(ctx context.Context, name string) (*UnitProperties, error) {
+	u, err := c.Unit(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return u.Properties(ctx)
+}
+
+// GetServiceProperties returns the typed service properties of the unit name.
+func (c *Conn) GetServiceProperties(ctx context.Context, name string) (*ServiceProperties, error) {
+	u, err := c.Unit(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return u.ServiceProperties(ctx)
+}
+
+// GetUnitProperty returns the raw value of a property of the unit name, see Unit.Property.
+func (c *Conn) GetUnitProperty(ctx context.Context, name, iface, property string) (dbus.Variant, error) {
+	u, err := c.Unit(ctx, name)
+	if err != nil {
+		return dbus.Variant{}, err
+	}
+	return u.Property(ctx, iface, property)
+}
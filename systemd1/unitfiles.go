@@ -0,0 +1,101 @@
+package systemd1
+
+import "context"
+
+// Unit file states returned by GetUnitFileState and ListUnitFiles.
+const (
+	UnitFileEnabled        = "enabled"
+	UnitFileEnabledRuntime = "enabled-runtime"
+	UnitFileLinked         = "linked"
+	UnitFileLinkedRuntime  = "linked-runtime"
+	UnitFileAlias          = "alias"
+	UnitFileMasked         = "masked"
+	UnitFileMaskedRuntime  = "masked-runtime"
+	UnitFileStatic         = "static"
+	UnitFileDisabled       = "disabled"
+	UnitFileIndirect       = "indirect"
+	UnitFileGenerated      = "generated"
+	UnitFileTransient      = "transient"
+	UnitFileBad            = "bad"
+)
+
+// UnitFileStatus is a unit file as returned by the ListUnitFiles* methods.
+type UnitFileStatus struct {
+	Path  string // unit file path
+	State string // unit file state (enabled, static, masked...)
+}
+
+// UnitFileChange is a change made to the unit files configuration (eg: by PresetUnitFiles).
+type UnitFileChange struct {
+	Type        string // symlink or unlink
+	Filename    string // symlink path
+	Destination string // symlink destination, empty for unlink
+}
+
+// PresetMode selects which changes PresetUnitFilesWithMode applies.
+type PresetMode string
+
+const (
+	PresetFull        PresetMode = "full"         // enable and disable units according to the preset
+	PresetEnableOnly  PresetMode = "enable-only"  // only enable units according to the preset
+	PresetDisableOnly PresetMode = "disable-only" // only disable units according to the preset
+)
+
+// GetUnitFileState returns the state of the unit file name (see the UnitFile* constants).
+// ctx: Context to use
+// name: unit file name
+func (c *Conn) GetUnitFileState(ctx context.Context, name string) (state string, err error) {
+	err = c.Call(ctx, "GetUnitFileState", name).Store(&state)
+	return
+}
+
+// GetUnitFilePreset returns the vendor preset of the unit file name: enabled, disabled or
+// ignored, empty when the unit has no [Install] section.
+// ctx: Context to use
+// name: unit name
+func (c *Conn) GetUnitFilePreset(ctx context.Context, name string) (string, error) {
+	v, err := c.GetUnitProperty(ctx, name, unitInterface, "UnitFilePreset")
+	if err != nil {
+		return "", err
+	}
+	preset, _ := v.Value().(string)
+	return preset, nil
+}
+
+// ListUnitFiles returns every installed unit file and its state.
+// ctx: Context to use
+func (c *Conn) ListUnitFiles(ctx context.Context) (files []UnitFileStatus, err error) {
+	err = c.Call(ctx, "ListUnitFiles").Store(&files)
+	return
+}
+
+// ListUnitFilesByPatterns returns the installed unit files filtered by states and name patterns.
+// ctx: Context to use
+// states: unit file states to match, empty means any
+// patterns: shell-style glob patterns matching unit names, empty means any
+func (c *Conn) ListUnitFilesByPatterns(ctx context.Context, states []string, patterns []string) (files []UnitFileStatus, err error) {
+	err = c.Call(ctx, "ListUnitFilesByPatterns", nilToEmpty(states), nilToEmpty(patterns)).Store(&files)
+	return
+}
+
+// PresetUnitFiles enables or disables the unit files according to the preset policy, like "systemctl preset".
+// It returns whether the unit files carry install information, and the changes made.
+// ctx: Context to use
+// files: unit file names or paths
+// runtime: only change /run (lost on reboot) instead of /etc
+// force: replace existing symlinks pointing to other units
+func (c *Conn) PresetUnitFiles(ctx context.Context, files []string, runtime, force bool) (installInfo bool, changes []UnitFileChange, err error) {
+	err = c.Call(ctx, "PresetUnitFiles", nilToEmpty(files), runtime, force).Store(&installInfo, &changes)
+	return
+}
+
+// PresetUnitFilesWithMode is PresetUnitFiles restricted to the changes selected by mode.
+// ctx: Context to use
+// files: unit file names or paths
+// mode: changes to apply
+// runtime: only change /run (lost on reboot) instead of /etc
+// force: replace existing symlinks pointing to other units
+func (c *Conn) PresetUnitFilesWithMode(ctx context.Context, files []string, mode PresetMode, runtime, force bool) (installInfo bool, changes []UnitFileChange, err error) {
+	err = c.Call(ctx, "PresetUnitFilesWithMode", nilToEmpty(files), string(mode), runtime, force).Store(&installInfo, &changes)
+	return
+}
@@ -0,0 +1,64 @@
+package systemd1
+
+import "context"
+
+// StartUnit enqueues a start job for the unit name and possibly depending units.
+// Use Wait on the returned job to wait for its completion.
+func (c *Conn) StartUnit(ctx context.Context, name string, mode Mode) (*Job, error) {
+	return c.enqueue(ctx, name, "StartUnit", name, string(mode))
+}
+
+// StopUnit enqueues a stop job for the unit name and possibly depending units.
+// Use Wait on the returned job to wait for its completion.
+func (c *Conn) StopUnit(ctx context.Context, name string, mode Mode) (*Job, error) {
+	return c.enqueue(ctx, name, "StopUnit", name, string(mode))
+}
+
+// RestartUnit enqueues a restart job for the unit name, it is started if not running.
+// Use Wait on the returned job to wait for its completion.
+func (c *Conn) RestartUnit(ctx context.Context, name string, mode Mode) (*Job, error) {
+	return c.enqueue(ctx, name, "RestartUnit", name, string(mode))
+}
+
+// ReloadUnit enqueues a reload job for the unit name.
+// Use Wait on the returned job to wait for its completion.
+func (c *Conn) ReloadUnit(ctx context.Context, name string, mode Mode) (*Job, error) {
+	return c.enqueue(ctx, name, "ReloadUnit", name, string(mode))
+}
+
+// TryRestartUnit enqueues a restart job for the unit name only if it is running.
+// Use Wait on the returned job to wait for its completion.
+func (c *Conn) TryRestartUnit(ctx context.Context, name string, mode Mode) (*Job, error) {
+	return c.enqueue(ctx, name, "TryRestartUnit", name, string(mode))
+}
+
+// ReloadOrRestartUnit enqueues a reload job for the unit name if it supports it, a restart job otherwise.
+// The unit is started if not running. Use Wait on the returned job to wait for its completion.
+func (c *Conn) ReloadOrRestartUnit(ctx context.Context, name string, mode Mode) (*Job, error) {
+	return c.enqueue(ctx, name, "ReloadOrRestartUnit", name, string(mode))
+}
+
+// ReloadOrTryRestartUnit is like ReloadOrRestartUnit but does nothing if the unit is not running.
+// Use Wait on the returned job to wait for its completion.
+func (c *Conn) ReloadOrTryRestartUnit(ctx context.Context, name string, mode Mode) (*Job, error) {
+	return c.enqueue(ctx, name, "ReloadOrTryRestartUnit", name, string(mode))
+}
+
+// Reload reloads every unit file and recreates the dependency tree (systemctl daemon-reload).
+// It returns once the reload is complete.
+func (c *Conn) Reload(ctx context.Context) error {
+	return c.Call(ctx, "Reload").Store()
+}
+
+// SetUnitProperties changes properties of the unit name at runtime (like systemctl set-property),
+// eg: CPUQuota=, MemoryMax=, TasksMax=, IPAddressDeny=, see the Prop* functions.
+// ctx: Context to use
+// name: unit name
+// runtime: if true the change is lost on reboot, otherwise it is persisted in a drop-in
+// properties: properties to set
+func (c *Conn) SetUnitProperties(ctx context.Context, name string, runtime bool, properties ...Property) error {
+	if properties == nil {
+		properties = []Property{}
+	}
+	return c.Call(ctx, "SetUnitProperties", name, runtime, properties).Store()
+}
@@ -0,0 +1,141 @@
+package systemd1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+// Version is the version and the compile time features of the running manager.
+type Version struct {
+	Full     string            // as reported by the manager, eg: 255.4-1ubuntu8
+	Major    int               // eg: 255
+	Minor    int               // stable release, eg: 4, 0 if none
+	Features map[string]bool   // compile time features, eg: PAM or SELINUX, true if enabled
+	Settings map[string]string // compile time settings, eg: default-hierarchy
+}
+
+// Version returns the version and the compile time features of the manager, from its Version
+// and Features properties (as "systemctl --version" prints them).
+// ctx: Context to use
+func (c *Conn) Version(ctx context.Context) (*Version, error) {
+	version, err := sysdbus.GetProperty(ctx, c.obj, dbusInterface, "Version")
+	if err != nil {
+		return nil, err
+	}
+	features, err := sysdbus.GetProperty(ctx, c.obj, dbusInterface, "Features")
+	if err != nil {
+		return nil, err
+	}
+	v, _ := version.Value().(string)
+	f, _ := features.Value().(string)
+	return ParseVersion(v, f)
+}
+
+// unsupported wraps err into errors.ErrUnsupported if the method is unknown to the manager
+// because it is too old for it, as told by supported.
+// ctx: Context to use
+// err: error of the method call
+// supported: Supports* method of Version telling if the manager has the method
+func (c *Conn) unsupported(ctx context.Context, err error, supported func(*Version) bool) error {
+	var dbusErr dbus.Error
+	if !errors.As(err, &dbusErr) || dbusErr.Name != "org.freedesktop.DBus.Error.UnknownMethod" {
+		return err
+	}
+	v, verr := c.Version(ctx)
+	if verr != nil || supported(v) {
+		return err
+	}
+	return fmt.Errorf("%w by systemd %s: %w", errors.ErrUnsupported, v, err)
+}
+
+// ParseVersion parses the Version and Features properties of the manager,
+// eg: "255.4-1ubuntu8" and "+PAM +AUDIT -SELINUX default-hierarchy=unified".
+func ParseVersion(version, features string) (*Version, error) {
+	v := &Version{
+		Full:     version,
+		Features: make(map[string]bool),
+		Settings: make(map[string]string),
+	}
+	// eg: 255.4-1ubuntu8, v256-rc1, 256~rc1, 252-22.el9
+	s := strings.TrimPrefix(version, "v")
+	end := strings.IndexFunc(s, func(r rune) bool {
+		return r < '0' || r > '9'
+	})
+	if end < 0 {
+		end = len(s)
+	}
+	var err error
+	if v.Major, err = strconv.Atoi(s[:end]); err != nil {
+		return nil, fmt.Errorf("invalid systemd version %q", version)
+	}
+	if rest, ok := strings.CutPrefix(s[end:], "."); ok {
+		end = strings.IndexFunc(rest, func(r rune) bool {
+			return r < '0' || r > '9'
+		})
+		if end < 0 {
+			end = len(rest)
+		}
+		v.Minor, _ = strconv.Atoi(rest[:end])
+	}
+	for _, f := range strings.Fields(features) {
+		switch {
+		case strings.HasPrefix(f, "+"):
+			v.Features[f[1:]] = true
+		case strings.HasPrefix(f, "-"):
+			v.Features[f[1:]] = false
+		default:
+			if key, value, ok := strings.Cut(f, "="); ok {
+				v.Settings[key] = value
+			}
+		}
+	}
+	return v, nil
+}
+
+// String returns the full version.
+func (v *Version) String() string {
+	return v.Full
+}
+
+// AtLeast tells if the manager is systemd major or later.
+func (v *Version) AtLeast(major int) bool {
+	return v.Major >= major
+}
+
+// HasFeature tells if the manager has been built with the feature name (eg: "SELINUX", "TPM2").
+func (v *Version) HasFeature(name string) bool {
+	return v.Features[name]
+}
+
+// SupportsNotifyReload tells if the manager supports Type=notify-reload services (systemd v253).
+func (v *Version) SupportsNotifyReload() bool {
+	return v.AtLeast(253)
+}
+
+// SupportsCredentials tells if the manager passes credentials to services with LoadCredential= and
+// SetCredential= (systemd v247).
+func (v *Version) SupportsCredentials() bool {
+	return v.AtLeast(247)
+}
+
+// SupportsMemoryPressure tells if the manager passes the memory pressure watch to services
+// thru $MEMORY_PRESSURE_WATCH (systemd v254).
+func (v *Version) SupportsMemoryPressure() bool {
+	return v.AtLeast(254)
+}
+
+// SupportsFreeze tells if units can be frozen and thawed (systemd v246).
+func (v *Version) SupportsFreeze() bool {
+	return v.AtLeast(246)
+}
+
+// SupportsSoftReboot tells if the manager can soft reboot, ie: restart the userspace only (systemd v254).
+func (v *Version) SupportsSoftReboot() bool {
+	return v.AtLeast(254)
+}
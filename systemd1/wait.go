@@ -0,0 +1,91 @@
+package systemd1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/iguanesolutions/go-systemd/v6/unitfile"
+)
+
+// DefaultPollInterval is the interval at which WaitForUnitState polls the unit state
+// when signals are not available, signals being the preferred way.
+const DefaultPollInterval = time.Second
+
+// ErrUnitFailed is returned by WaitForUnitState when the unit enters the failed state instead of the awaited one.
+var ErrUnitFailed = errors.New("unit failed")
+
+// UnitState is an ActiveState and SubState pair, an empty field matches any value.
+type UnitState struct {
+	ActiveState string // eg: active, inactive, failed...
+	SubState    string // eg: running, exited, dead...
+}
+
+func (s UnitState) match(props *UnitProperties) bool {
+	return (s.ActiveState == "" || s.ActiveState == props.ActiveState) &&
+		(s.SubState == "" || s.SubState == props.SubState)
+}
+
+func (s UnitState) String() string {
+	return fmt.Sprintf("%s/%s", s.ActiveState, s.SubState)
+}
+
+// WaitForUnitState blocks until the unit name reaches the state want, and returns its properties at that time.
+// It fails with ErrUnitFailed if the unit enters the failed state, after the call, while another one is awaited:
+// a unit still failed from a previous run (eg: being restarted) is awaited as usual.
+// It fails with the context error once ctx is done. The unit state is re-read on each signal related to it
+// (see Subscribe); when signals can't be received the state is polled every DefaultPollInterval instead.
+// ctx: Context to use
+// name: unit name, an alias or a name without unit type (mangled as a service, see unitfile.Mangle)
+// want: state to wait for
+func (c *Conn) WaitForUnitState(ctx context.Context, name string, want UnitState) (*UnitProperties, error) {
+	start := time.Now()
+	mangled, err := unitfile.Mangle(name, ".service")
+	if err != nil {
+		return nil, err
+	}
+	u, err := c.Unit(ctx, mangled)
+	if err != nil {
+		return nil, err
+	}
+	props, err := u.Properties(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var events <-chan Event
+	poll := DefaultPollInterval
+	// signals carry the unit id, not the alias which may have been given
+	if sub, err := c.Subscribe(WithUnits(props.ID)); err == nil {
+		defer sub.Close()
+		events = sub.C
+		// signals may still be missed (eg: private connection), keep a slow safety net
+		poll *= 10
+	}
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+	// the failed state only counts once left, or if entered after the call
+	left := props.ActiveState != "failed"
+	for {
+		if props, err = u.Properties(ctx); err != nil {
+			return nil, err
+		}
+		if want.match(props) {
+			return props, nil
+		}
+		if props.ActiveState != "failed" {
+			left = true
+		} else if left || props.StateChangeTimestamp.After(start) {
+			return props, fmt.Errorf("%w: %s did not reach %s", ErrUnitFailed, name, want)
+		}
+		select {
+		case _, ok := <-events:
+			if !ok {
+				events = nil
+			}
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
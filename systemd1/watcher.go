@@ -0,0 +1,183 @@
+package systemd1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultDebounce is the default delay a Watcher waits for states to settle before emitting a snapshot.
+const DefaultDebounce = 200 * time.Millisecond
+
+// Snapshot maps unit names to their ActiveState.
+type Snapshot map[string]string
+
+// All tells if every unit of the snapshot is in state (eg: "active").
+func (s Snapshot) All(state string) bool {
+	for _, st := range s {
+		if st != state {
+			return false
+		}
+	}
+	return true
+}
+
+// Watcher tracks the ActiveState of a set of units, see Conn.NewWatcher.
+type Watcher struct {
+	// C receives the snapshots, it is closed by Close.
+	// Only the latest snapshot is kept when the receiver lags behind.
+	C <-chan Snapshot
+
+	c        *Conn
+	out      chan Snapshot
+	sub      *Subscription
+	states   Snapshot
+	debounce time.Duration
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// WatcherOption configures a Watcher, see Conn.NewWatcher.
+type WatcherOption func(w *Watcher) error
+
+// WithDebounce sets the delay without state change after which a snapshot is emitted, DefaultDebounce by default.
+func WithDebounce(d time.Duration) WatcherOption {
+	return func(w *Watcher) error {
+		if d < 0 {
+			return fmt.Errorf("invalid debounce delay: %s", d)
+		}
+		w.debounce = d
+		return nil
+	}
+}
+
+// NewWatcher returns a Watcher tracking the ActiveState of the units names.
+// The initial snapshot is emitted right away, then a new one is emitted each time the states
+// changed and did not change again for the debounce delay (see WithDebounce).
+// It is meant to gate readiness on several dependencies, eg: wait for a snapshot where All("active").
+// Close must be called once done with it.
+// ctx: Context to use for the initial states
+// names: unit names
+func (c *Conn) NewWatcher(ctx context.Context, names []string, opts ...WatcherOption) (*Watcher, error) {
+	if len(names) == 0 {
+		return nil, errors.New("no unit names")
+	}
+	w := &Watcher{
+		c:        c,
+		out:      make(chan Snapshot, 1),
+		states:   make(Snapshot, len(names)),
+		debounce: DefaultDebounce,
+		done:     make(chan struct{}),
+	}
+	w.C = w.out
+	for _, opt := range opts {
+		if err := opt(w); err != nil {
+			return nil, err
+		}
+	}
+	// subscribe before reading the states so no change is missed in between
+	sub, err := c.Subscribe(WithUnits(names...))
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if err = w.refresh(ctx, name); err != nil {
+			sub.Close()
+			return nil, err
+		}
+	}
+	w.sub = sub
+	w.emit()
+	var runCtx context.Context
+	runCtx, w.cancel = context.WithCancel(context.Background())
+	go w.run(runCtx)
+	return w, nil
+}
+
+// Close stops the watcher and closes its channel.
+func (w *Watcher) Close() {
+	w.cancel()
+	w.sub.Close()
+	<-w.done
+}
+
+func (w *Watcher) refresh(ctx context.Context, name string) error {
+	v, err := w.c.GetUnitProperty(ctx, name, unitInterface, "ActiveState")
+	if err != nil {
+		return fmt.Errorf("failed to get %s state: %w", name, err)
+	}
+	state, _ := v.Value().(string)
+	w.states[name] = state
+	return nil
+}
+
+func (w *Watcher) run(ctx context.Context) {
+	defer close(w.done)
+	defer close(w.out)
+	timer := time.NewTimer(0)
+	if !timer.Stop() {
+		<-timer.C
+	}
+	for {
+		select {
+		case e, ok := <-w.sub.C:
+			if !ok {
+				return
+			}
+			if !w.apply(ctx, e) {
+				continue
+			}
+			// drain a tick not received yet, it would emit before the debounce interval
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(w.debounce)
+		case <-timer.C:
+			w.emit()
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// apply updates the states with e, and tells if they changed.
+func (w *Watcher) apply(ctx context.Context, e Event) bool {
+	before := w.states[e.Unit]
+	switch e.Type {
+	case PropertiesChanged:
+		if e.Interface != unitInterface {
+			return false
+		}
+		state, ok := e.Properties["ActiveState"]
+		if !ok {
+			return false
+		}
+		w.states[e.Unit], _ = state.Value().(string)
+	case UnitNew, UnitRemoved:
+		// the unit has been (un)loaded, read its state again
+		if w.refresh(ctx, e.Unit) != nil {
+			return false
+		}
+	default:
+		return false
+	}
+	return w.states[e.Unit] != before
+}
+
+// emit sends a copy of the states, replacing the previous snapshot if not received yet.
+func (w *Watcher) emit() {
+	snapshot := make(Snapshot, len(w.states))
+	for name, state := range w.states {
+		snapshot[name] = state
+	}
+	select {
+	case <-w.out:
+	default:
+	}
+	w.out <- snapshot
+}
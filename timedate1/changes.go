@@ -0,0 +1,18 @@
+package timedate1
+
+import (
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+// Change is a PropertiesChanged signal of the timedate1 interface, its Changed properties being
+// eg: Timezone, LocalRTC, NTP. Has tells if a property changed.
+type Change = sysdbus.PropertyChange
+
+// Subscription delivers Change events on its C channel, see Conn.Subscribe.
+type Subscription = sysdbus.PropertySubscription
+
+// Subscribe returns a new subscription to the property changes, so daemons can react
+// to timezone changes at runtime. Close must be called once done with it.
+func (c *Conn) Subscribe() (*Subscription, error) {
+	return sysdbus.SubscribeProperties(c.conn, dbusPath, dbusInterface)
+}
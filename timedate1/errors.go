@@ -0,0 +1,24 @@
+package timedate1
+
+import (
+	"errors"
+
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+var (
+	// ErrAccessDenied is returned when polkit (or the caller privileges) denied the operation,
+	// see WithInteractiveAuthorization.
+	ErrAccessDenied = sysdbus.ErrAccessDenied
+	// ErrNTPUnavailable is returned by SetNTP when no NTP service is installed.
+	ErrNTPUnavailable = errors.New("no NTP service available")
+)
+
+var dbusErrors = map[string]error{
+	dbusDest + ".NoNTPSupport": ErrNTPUnavailable,
+}
+
+// typedError wraps the known timedated dbus errors into the Err* errors, so they can be tested with errors.Is.
+func typedError(err error) error {
+	return sysdbus.TypedError(err, dbusErrors)
+}
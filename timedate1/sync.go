@@ -0,0 +1,49 @@
+package timedate1
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+)
+
+// SyncPollInterval is the interval at which WaitForSync checks the clock synchronization.
+const SyncPollInterval = time.Second
+
+// timesyncFlag is created by systemd-timesyncd once it synchronized the clock.
+const timesyncFlag = "/run/systemd/timesync/synchronized"
+
+// Synchronized tells if the system clock is synchronized, like systemd-time-wait-sync checks it:
+// systemd-timesyncd signaled it, or the kernel clock is not flagged unsynchronized anymore
+// (which is what the NTPSynchronized property reports). It does not need a dbus connection.
+func Synchronized() (bool, error) {
+	if _, err := os.Stat(timesyncFlag); err == nil {
+		return true, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return false, err
+	}
+	return kernelSynchronized()
+}
+
+// WaitForSync blocks until the system clock is synchronized (see Synchronized) or ctx is done.
+// Services relying on wall clock time correctness (eg: TLS certificates validation at boot) should
+// call it before starting, unless they are ordered after time-sync.target.
+// ctx: Context to use
+func WaitForSync(ctx context.Context) error {
+	ticker := time.NewTicker(SyncPollInterval)
+	defer ticker.Stop()
+	for {
+		synced, err := Synchronized()
+		if err != nil {
+			return err
+		}
+		if synced {
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package timedate1
+
+import "syscall"
+
+// staUnsync is the STA_UNSYNC bit of the adjtimex status, set while the clock is not synchronized.
+const staUnsync = 0x0040
+
+// kernelSynchronized tells if the kernel clock is not flagged unsynchronized.
+func kernelSynchronized() (bool, error) {
+	var tx syscall.Timex
+	if _, err := syscall.Adjtimex(&tx); err != nil {
+		return false, err
+	}
+	return tx.Status&staUnsync == 0, nil
+}
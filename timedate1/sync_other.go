@@ -0,0 +1,10 @@
+//go:build !linux
+
+package timedate1
+
+import "errors"
+
+// kernelSynchronized fails with errors.ErrUnsupported, adjtimex only exists on Linux.
+func kernelSynchronized() (bool, error) {
+	return false, errors.ErrUnsupported
+}
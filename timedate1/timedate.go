@@ -0,0 +1,116 @@
+package timedate1
+
+import (
+	"context"
+	"time"
+
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+// Status holds the clock, timezone and NTP settings.
+type Status struct {
+	Timezone        string    // eg: Europe/Paris
+	LocalRTC        bool      // the RTC is in local time instead of UTC
+	CanNTP          bool      // an NTP service is available
+	NTP             bool      // the NTP service is enabled
+	NTPSynchronized bool      // the system clock is synchronized (kernel view)
+	Time            time.Time // system time
+	RTCTime         time.Time // RTC time, zero if there is no RTC
+}
+
+// Status returns the clock, timezone and NTP settings.
+// ctx: Context to use
+func (c *Conn) Status(ctx context.Context) (*Status, error) {
+	props, err := sysdbus.GetAllProperties(ctx, c.obj, dbusInterface)
+	if err != nil {
+		return nil, err
+	}
+	return &Status{
+		Timezone:        sysdbus.Prop[string](props, "Timezone"),
+		LocalRTC:        sysdbus.Prop[bool](props, "LocalRTC"),
+		CanNTP:          sysdbus.Prop[bool](props, "CanNTP"),
+		NTP:             sysdbus.Prop[bool](props, "NTP"),
+		NTPSynchronized: sysdbus.Prop[bool](props, "NTPSynchronized"),
+		Time:            sysdbus.Timestamp(props, "TimeUSec"),
+		RTCTime:         sysdbus.Timestamp(props, "RTCTimeUSec"),
+	}, nil
+}
+
+// Timezone returns the system timezone (eg: Europe/Paris).
+// ctx: Context to use
+func (c *Conn) Timezone(ctx context.Context) (string, error) {
+	v, err := sysdbus.GetProperty(ctx, c.obj, dbusInterface, "Timezone")
+	if err != nil {
+		return "", err
+	}
+	tz, _ := v.Value().(string)
+	return tz, nil
+}
+
+// LocalRTC tells if the RTC is in local time instead of UTC.
+// ctx: Context to use
+func (c *Conn) LocalRTC(ctx context.Context) (bool, error) {
+	return c.boolProperty(ctx, "LocalRTC")
+}
+
+// NTP tells if the NTP service is enabled.
+// ctx: Context to use
+func (c *Conn) NTP(ctx context.Context) (bool, error) {
+	return c.boolProperty(ctx, "NTP")
+}
+
+// NTPSynchronized tells if the system clock is synchronized.
+// ctx: Context to use
+func (c *Conn) NTPSynchronized(ctx context.Context) (bool, error) {
+	return c.boolProperty(ctx, "NTPSynchronized")
+}
+
+func (c *Conn) boolProperty(ctx context.Context, name string) (bool, error) {
+	v, err := sysdbus.GetProperty(ctx, c.obj, dbusInterface, name)
+	if err != nil {
+		return false, err
+	}
+	b, _ := v.Value().(bool)
+	return b, nil
+}
+
+// SetTimezone sets the system timezone. The name is checked with ValidateTimezone first,
+// so invalid names fail with ErrInvalidTimezone before reaching polkit.
+// ctx: Context to use
+// timezone: timezone name (eg: Europe/Paris)
+func (c *Conn) SetTimezone(ctx context.Context, timezone string) error {
+	if err := ValidateTimezone(timezone); err != nil {
+		return err
+	}
+	return typedError(c.Call(ctx, "SetTimezone", timezone, c.interactive()).Store())
+}
+
+// SetLocalRTC sets whether the RTC is in local time instead of UTC.
+// ctx: Context to use
+// localRTC: whether the RTC is in local time
+// fixSystem: set the system clock from the RTC instead of the RTC from the system clock
+func (c *Conn) SetLocalRTC(ctx context.Context, localRTC, fixSystem bool) error {
+	return typedError(c.Call(ctx, "SetLocalRTC", localRTC, fixSystem, c.interactive()).Store())
+}
+
+// SetNTP enables or disables the NTP service.
+// It fails with ErrNTPUnavailable if no NTP service is installed.
+// ctx: Context to use
+// enable: whether to enable NTP
+func (c *Conn) SetNTP(ctx context.Context, enable bool) error {
+	return typedError(c.Call(ctx, "SetNTP", enable, c.interactive()).Store())
+}
+
+// SetTime sets the system clock (and the RTC). It fails if NTP is enabled.
+// ctx: Context to use
+// t: new time
+func (c *Conn) SetTime(ctx context.Context, t time.Time) error {
+	return typedError(c.Call(ctx, "SetTime", t.UnixMicro(), false, c.interactive()).Store())
+}
+
+// AdjustTime shifts the system clock (and the RTC) by d. It fails if NTP is enabled.
+// ctx: Context to use
+// d: shift to apply
+func (c *Conn) AdjustTime(ctx context.Context, d time.Duration) error {
+	return typedError(c.Call(ctx, "SetTime", d.Microseconds(), true, c.interactive()).Store())
+}
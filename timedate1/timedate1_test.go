@@ -0,0 +1,17 @@
+package timedate1
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateTimezone(t *testing.T) {
+	for _, tz := range []string{"", "Local", "/etc/passwd", "Europe/../etc", "Europe/", "Europe/Pa ris", "Not/AZone"} {
+		if err := ValidateTimezone(tz); !errors.Is(err, ErrInvalidTimezone) {
+			t.Errorf("%q should be invalid, got %v", tz, err)
+		}
+	}
+	if err := ValidateTimezone("UTC"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,41 @@
+package timedate1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidTimezone is returned for timezone names unknown to the system.
+var ErrInvalidTimezone = errors.New("invalid timezone")
+
+// ListTimezones returns the timezones known to the system.
+// ctx: Context to use
+func (c *Conn) ListTimezones(ctx context.Context) (timezones []string, err error) {
+	err = c.Call(ctx, "ListTimezones").Store(&timezones)
+	return
+}
+
+// ValidateTimezone checks that timezone is a well formed name (like systemd does) present in
+// the local timezone database, it returns ErrInvalidTimezone otherwise.
+func ValidateTimezone(timezone string) error {
+	if timezone == "" || timezone == "Local" || strings.HasPrefix(timezone, "/") || strings.HasSuffix(timezone, "/") {
+		return fmt.Errorf("%w: %q", ErrInvalidTimezone, timezone)
+	}
+	for _, elem := range strings.Split(timezone, "/") {
+		if elem == "" || elem == "." || elem == ".." || strings.HasPrefix(elem, "-") {
+			return fmt.Errorf("%w: %q", ErrInvalidTimezone, timezone)
+		}
+	}
+	for _, r := range timezone {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("/-_+", r)) {
+			return fmt.Errorf("%w: %q contains %q", ErrInvalidTimezone, timezone, r)
+		}
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidTimezone, err)
+	}
+	return nil
+}
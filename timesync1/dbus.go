@@ -0,0 +1,46 @@
+// Package timesync1 is a pure Go implementation of the org.freedesktop.timesync1 dbus interface,
+// which exposes the NTP synchronization status of systemd-timesyncd.
+package timesync1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+const (
+	dbusDest      = "org.freedesktop.timesync1"
+	dbusInterface = "org.freedesktop.timesync1.Manager"
+	dbusPath      = "/org/freedesktop/timesync1"
+)
+
+// Conn represents a systemd-timesyncd dbus connection.
+type Conn struct {
+	conn *dbus.Conn
+	obj  dbus.BusObject
+}
+
+// NewConn returns a new and ready to use dbus connection.
+// You must close that connection when you have been done with it.
+func NewConn() (*Conn, error) {
+	conn, err := sysdbus.SystemBus()
+	if err != nil {
+		return nil, err
+	}
+	return &Conn{
+		conn: conn,
+		obj:  conn.Object(dbusDest, dbus.ObjectPath(dbusPath)),
+	}, nil
+}
+
+// Call wraps obj.CallWithContext by using 0 as flags and format the method with the dbus manager interface.
+func (c *Conn) Call(ctx context.Context, method string, args ...interface{}) *dbus.Call {
+	return c.obj.CallWithContext(ctx, fmt.Sprintf("%s.%s", dbusInterface, method), 0, args...)
+}
+
+// Close closes the current dbus connection.
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
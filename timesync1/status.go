@@ -0,0 +1,144 @@
+package timesync1
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+// NTPMessage is the last NTP response received from the server, along with the derived
+// offset and delay (like "timedatectl timesync-status" shows them).
+type NTPMessage struct {
+	Leap            uint32
+	Version         uint32
+	Mode            uint32
+	Stratum         uint32
+	Precision       int32 // log2 seconds
+	RootDelay       time.Duration
+	RootDispersion  time.Duration
+	Reference       string // reference ID: IPv4 address, or 4 ASCII characters for stratum 1
+	OriginTime      time.Time
+	ReceiveTime     time.Time
+	TransmitTime    time.Time
+	DestinationTime time.Time
+	Spike           bool
+	PacketCount     uint64
+	Jitter          time.Duration
+	Offset          time.Duration // estimated local clock offset to the server
+	Delay           time.Duration // round trip delay
+}
+
+// ntpMessage is the dbus (uuuuittayttttbtt) representation of NTPMessage.
+type ntpMessage struct {
+	Leap, Version, Mode, Stratum    uint32
+	Precision                       int32
+	RootDelay, RootDispersion       uint64
+	Reference                       []byte
+	Origin, Receive, Transmit, Dest uint64
+	Spike                           bool
+	PacketCount, Jitter             uint64
+}
+
+// Status holds the synchronization status of timesyncd.
+type Status struct {
+	ServerName         string
+	ServerAddress      net.IP // nil if not connected
+	LinkNTPServers     []string
+	SystemNTPServers   []string
+	RuntimeNTPServers  []string
+	FallbackNTPServers []string
+	RootDistanceMax    time.Duration
+	PollIntervalMin    time.Duration
+	PollIntervalMax    time.Duration
+	PollInterval       time.Duration
+	Frequency          int64       // kernel clock frequency adjustment, scaled ppm
+	NTPMessage         *NTPMessage // nil if no response has been received yet
+}
+
+// Status returns the synchronization status of timesyncd.
+// ctx: Context to use
+func (c *Conn) Status(ctx context.Context) (*Status, error) {
+	props, err := sysdbus.GetAllProperties(ctx, c.obj, dbusInterface)
+	if err != nil {
+		return nil, err
+	}
+	usec := func(name string) time.Duration {
+		return time.Duration(sysdbus.Prop[uint64](props, name)) * time.Microsecond
+	}
+	s := &Status{
+		ServerName:         sysdbus.Prop[string](props, "ServerName"),
+		LinkNTPServers:     sysdbus.Prop[[]string](props, "LinkNTPServers"),
+		SystemNTPServers:   sysdbus.Prop[[]string](props, "SystemNTPServers"),
+		RuntimeNTPServers:  sysdbus.Prop[[]string](props, "RuntimeNTPServers"),
+		FallbackNTPServers: sysdbus.Prop[[]string](props, "FallbackNTPServers"),
+		RootDistanceMax:    usec("RootDistanceMaxUSec"),
+		PollIntervalMin:    usec("PollIntervalMinUSec"),
+		PollIntervalMax:    usec("PollIntervalMaxUSec"),
+		PollInterval:       usec("PollIntervalUSec"),
+		Frequency:          sysdbus.Prop[int64](props, "Frequency"),
+	}
+	var address struct {
+		Family  int32
+		Address []byte
+	}
+	if err = sysdbus.StoreProp(props, "ServerAddress", &address); err != nil {
+		return nil, err
+	}
+	if len(address.Address) > 0 {
+		s.ServerAddress = net.IP(address.Address)
+	}
+	var msg ntpMessage
+	if err = sysdbus.StoreProp(props, "NTPMessage", &msg); err != nil {
+		return nil, err
+	}
+	if msg.Transmit != 0 {
+		s.NTPMessage = msg.typed()
+	}
+	return s, nil
+}
+
+func (m ntpMessage) typed() *NTPMessage {
+	usec := func(v uint64) time.Duration {
+		return time.Duration(v) * time.Microsecond
+	}
+	t := &NTPMessage{
+		Leap:            m.Leap,
+		Version:         m.Version,
+		Mode:            m.Mode,
+		Stratum:         m.Stratum,
+		Precision:       m.Precision,
+		RootDelay:       usec(m.RootDelay),
+		RootDispersion:  usec(m.RootDispersion),
+		Reference:       reference(m.Stratum, m.Reference),
+		OriginTime:      time.UnixMicro(int64(m.Origin)),
+		ReceiveTime:     time.UnixMicro(int64(m.Receive)),
+		TransmitTime:    time.UnixMicro(int64(m.Transmit)),
+		DestinationTime: time.UnixMicro(int64(m.Dest)),
+		Spike:           m.Spike,
+		PacketCount:     m.PacketCount,
+		Jitter:          usec(m.Jitter),
+	}
+	origin, receive, transmit, dest := int64(m.Origin), int64(m.Receive), int64(m.Transmit), int64(m.Dest)
+	t.Offset = time.Duration((receive-origin)+(transmit-dest)) * time.Microsecond / 2
+	t.Delay = time.Duration((dest-origin)-(transmit-receive)) * time.Microsecond
+	return t
+}
+
+// reference formats the reference ID: ASCII for stratum 0 and 1 (eg: GPS, PPS), an IPv4 address otherwise.
+func reference(stratum uint32, ref []byte) string {
+	if len(ref) != 4 {
+		return ""
+	}
+	if stratum <= 1 {
+		b := make([]byte, 0, 4)
+		for _, c := range ref {
+			if c != 0 {
+				b = append(b, c)
+			}
+		}
+		return string(b)
+	}
+	return net.IP(ref).String()
+}
@@ -0,0 +1,34 @@
+package timesync1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/iguanesolutions/go-systemd/v6/internal/sysdbus"
+)
+
+func TestNTPMessage(t *testing.T) {
+	props := map[string]dbus.Variant{
+		"NTPMessage": dbus.MakeVariant([]interface{}{
+			uint32(0), uint32(4), uint32(4), uint32(2), int32(-23), uint64(1000), uint64(2000),
+			[]byte{192, 0, 2, 1}, uint64(1000000), uint64(1000600), uint64(1000700), uint64(1000100),
+			false, uint64(12), uint64(300),
+		}),
+	}
+	var msg ntpMessage
+	if err := sysdbus.StoreProp(props, "NTPMessage", &msg); err != nil {
+		t.Fatal(err)
+	}
+	m := msg.typed()
+	if m.Stratum != 2 || m.Reference != "192.0.2.1" || m.Jitter != 300*time.Microsecond {
+		t.Errorf("unexpected message: %+v", m)
+	}
+	// ((600) + (600)) / 2 and (100) - (100)
+	if m.Offset != 600*time.Microsecond || m.Delay != 0 {
+		t.Errorf("unexpected offset %s or delay %s", m.Offset, m.Delay)
+	}
+	if ref := reference(1, []byte("GPS\x00")); ref != "GPS" {
+		t.Errorf("unexpected stratum 1 reference %q", ref)
+	}
+}
@@ -0,0 +1,424 @@
+package unitfile
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// calendarMaxYear bounds the calendar expressions and their elapse computations, as systemd does.
+const calendarMaxYear = 2199
+
+// ErrInvalidCalendar is returned when a calendar expression can't be parsed.
+var ErrInvalidCalendar = errors.New("invalid calendar expression")
+
+// calendarShorthands are the special expressions of systemd.time(7).
+var calendarShorthands = map[string]string{
+	"minutely":     "*-*-* *:*:00",
+	"hourly":       "*-*-* *:00:00",
+	"daily":        "*-*-* 00:00:00",
+	"monthly":      "*-*-01 00:00:00",
+	"weekly":       "Mon *-*-* 00:00:00",
+	"yearly":       "*-01-01 00:00:00",
+	"annually":     "*-01-01 00:00:00",
+	"quarterly":    "*-01,04,07,10-01 00:00:00",
+	"semiannually": "*-01,07-01 00:00:00",
+}
+
+// weekdays are the weekday names in systemd order, Monday first.
+var weekdays = [7]string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}
+
+// calendarValue is a value, a range (stop >= 0) or a repetition (repeat > 0) of a component.
+type calendarValue struct {
+	start, stop, repeat int
+}
+
+// calendarChain is the list of values of a component, nil meaning any value ("*").
+type calendarChain []calendarValue
+
+// Calendar is a calendar expression of systemd.time(7), as used by OnCalendar= of timers,
+// eg: "Mon..Fri *-*-* 02:00:00" or "hourly".
+type Calendar struct {
+	weekdays   uint8 // bit 0 is Monday, 0 meaning any day
+	year       calendarChain
+	month      calendarChain
+	day        calendarChain
+	endOfMonth bool // day counts from the end of the month ("~")
+	hour       calendarChain
+	minute     calendarChain
+	second     calendarChain
+	location   *time.Location // nil for the local time zone
+}
+
+// ParseCalendar parses the calendar expression s:
+// "[WEEKDAYS] [[YEAR-]MONTH-DAY] [HOUR:MINUTE[:SECOND]] [TIMEZONE]" or a shorthand like "daily".
+// Each component may be "*", a value, a list ("1,15"), a range ("1..5") or a repetition ("*/15", "0/20").
+// Fractional seconds are not supported.
+func ParseCalendar(s string) (*Calendar, error) {
+	c, err := parseCalendar(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %w", ErrInvalidCalendar, s, err)
+	}
+	return c, nil
+}
+
+func parseCalendar(s string) (c *Calendar, err error) {
+	c = new(Calendar)
+	tokens := strings.Fields(s)
+	if len(tokens) == 0 {
+		return nil, errors.New("empty expression")
+	}
+	if len(tokens) > 1 {
+		last := tokens[len(tokens)-1]
+		if isLetter(last[0]) {
+			if last == "UTC" {
+				c.location = time.UTC
+			} else if c.location, err = time.LoadLocation(last); err != nil {
+				return nil, fmt.Errorf("unknown time zone %q", last)
+			}
+			tokens = tokens[:len(tokens)-1]
+		}
+	}
+	if len(tokens) == 1 {
+		if expanded, ok := calendarShorthands[strings.ToLower(tokens[0])]; ok {
+			tokens = strings.Fields(expanded)
+		}
+	}
+	if len(tokens) > 0 && isLetter(tokens[0][0]) {
+		if c.weekdays, err = parseWeekdays(tokens[0]); err != nil {
+			return nil, err
+		}
+		tokens = tokens[1:]
+	}
+	var dateSet, timeSet bool
+	for _, token := range tokens {
+		switch {
+		case strings.Contains(token, ":") && !timeSet:
+			err = c.parseTime(token)
+			timeSet = true
+		case strings.ContainsAny(token, "-~") && !dateSet && !timeSet:
+			err = c.parseDate(token)
+			dateSet = true
+		default:
+			err = fmt.Errorf("unexpected %q", token)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !timeSet {
+		// midnight by default
+		c.hour, c.minute, c.second = calendarChain{{stop: -1}}, calendarChain{{stop: -1}}, calendarChain{{stop: -1}}
+	}
+	return c, nil
+}
+
+func isLetter(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// allWeekdays is the mask of a week, normalized to 0 (any day) as systemd does.
+const allWeekdays = 0x7f
+
+// parseWeekdays parses a list of weekday names or ranges, eg: "Mon..Fri,Sun".
+func parseWeekdays(s string) (mask uint8, err error) {
+	// a trailing comma is allowed before the date, eg: "Wed, 17:48"
+	for _, item := range strings.Split(strings.TrimSuffix(s, ","), ",") {
+		first, last, isRange := strings.Cut(item, "..")
+		start, err := parseWeekday(first)
+		if err != nil {
+			return 0, err
+		}
+		stop := start
+		if isRange {
+			if stop, err = parseWeekday(last); err != nil {
+				return 0, err
+			}
+		}
+		// ranges may wrap around the week, eg: Sat..Mon
+		for d := start; ; d = (d + 1) % 7 {
+			mask |= 1 << d
+			if d == stop {
+				break
+			}
+		}
+	}
+	if mask == allWeekdays {
+		mask = 0
+	}
+	return mask, nil
+}
+
+func parseWeekday(s string) (int, error) {
+	for i, name := range weekdays {
+		if strings.EqualFold(s, name) || strings.EqualFold(s, name[:3]) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid weekday %q", s)
+}
+
+// parseDate parses "[YEAR-]MONTH-DAY" or "[YEAR-]MONTH~DAY".
+func (c *Calendar) parseDate(s string) (err error) {
+	var day string
+	if before, after, found := strings.Cut(s, "~"); found {
+		s, day, c.endOfMonth = before, after, true
+	} else if i := strings.LastIndexByte(s, '-'); i >= 0 {
+		s, day = s[:i], s[i+1:]
+	}
+	month := s
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		if c.year, err = parseChain(s[:i], 1970, calendarMaxYear, true); err != nil {
+			return fmt.Errorf("year: %w", err)
+		}
+		month = s[i+1:]
+	}
+	if c.month, err = parseChain(month, 1, 12, false); err != nil {
+		return fmt.Errorf("month: %w", err)
+	}
+	if c.day, err = parseChain(day, 1, 31, false); err != nil {
+		return fmt.Errorf("day: %w", err)
+	}
+	return nil
+}
+
+// parseTime parses "HOUR:MINUTE[:SECOND]".
+func (c *Calendar) parseTime(s string) (err error) {
+	parts := strings.Split(s, ":")
+	switch len(parts) {
+	case 2:
+		parts = append(parts, "00")
+	case 3:
+	default:
+		return fmt.Errorf("invalid time %q", s)
+	}
+	if c.hour, err = parseChain(parts[0], 0, 23, false); err != nil {
+		return fmt.Errorf("hour: %w", err)
+	}
+	if c.minute, err = parseChain(parts[1], 0, 59, false); err != nil {
+		return fmt.Errorf("minute: %w", err)
+	}
+	if c.second, err = parseChain(parts[2], 0, 59, false); err != nil {
+		return fmt.Errorf("second: %w", err)
+	}
+	return nil
+}
+
+// parseChain parses a component made of comma separated values, ranges and repetitions.
+func parseChain(s string, lowest, highest int, year bool) (calendarChain, error) {
+	if s == "*" {
+		return nil, nil
+	}
+	var chain calendarChain
+	for _, item := range strings.Split(s, ",") {
+		v := calendarValue{stop: -1}
+		item, repeat, hasRepeat := strings.Cut(item, "/")
+		if hasRepeat {
+			n, err := strconv.Atoi(repeat)
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("invalid repetition %q", repeat)
+			}
+			v.repeat = n
+		}
+		first, last, isRange := strings.Cut(item, "..")
+		var err error
+		switch {
+		case first == "*" && !isRange && hasRepeat:
+			v.start = lowest
+		default:
+			if v.start, err = parseCalendarInt(first, lowest, highest, year); err != nil {
+				return nil, err
+			}
+			if isRange {
+				if v.stop, err = parseCalendarInt(last, lowest, highest, year); err != nil {
+					return nil, err
+				}
+				if v.stop < v.start {
+					return nil, fmt.Errorf("invalid range %q", item)
+				}
+			}
+		}
+		chain = append(chain, v)
+	}
+	sort.Slice(chain, func(i, j int) bool {
+		if chain[i].start != chain[j].start {
+			return chain[i].start < chain[j].start
+		}
+		return chain[i].stop < chain[j].stop
+	})
+	// drop duplicates
+	deduped := chain[:1]
+	for _, v := range chain[1:] {
+		if v != deduped[len(deduped)-1] {
+			deduped = append(deduped, v)
+		}
+	}
+	return deduped, nil
+}
+
+func parseCalendarInt(s string, lowest, highest int, year bool) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if year && len(s) == 2 {
+		// two digits years, as systemd does
+		if n >= 70 {
+			n += 1900
+		} else {
+			n += 2000
+		}
+	}
+	if n < lowest || n > highest {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", n, lowest, highest)
+	}
+	return n, nil
+}
+
+// String returns the normalized form of the calendar expression, as shown by systemd-analyze calendar,
+// eg: "hourly" is "*-*-* *:00:00".
+func (c *Calendar) String() string {
+	var b strings.Builder
+	if c.weekdays != 0 {
+		b.WriteString(formatWeekdays(c.weekdays))
+		b.WriteByte(' ')
+	}
+	b.WriteString(c.year.format(4))
+	b.WriteByte('-')
+	b.WriteString(c.month.format(2))
+	if c.endOfMonth {
+		b.WriteByte('~')
+	} else {
+		b.WriteByte('-')
+	}
+	b.WriteString(c.day.format(2))
+	b.WriteByte(' ')
+	b.WriteString(c.hour.format(2))
+	b.WriteByte(':')
+	b.WriteString(c.minute.format(2))
+	b.WriteByte(':')
+	b.WriteString(c.second.format(2))
+	if c.location != nil {
+		b.WriteByte(' ')
+		b.WriteString(c.location.String())
+	}
+	return b.String()
+}
+
+// formatWeekdays formats mask with ranges for 3 consecutive days or more, eg: "Mon..Wed,Fri".
+func formatWeekdays(mask uint8) string {
+	var items []string
+	for d := 0; d < 7; d++ {
+		if mask&(1<<d) == 0 {
+			continue
+		}
+		end := d
+		for end+1 < 7 && mask&(1<<(end+1)) != 0 {
+			end++
+		}
+		switch end - d {
+		case 0:
+			items = append(items, weekdays[d][:3])
+		case 1:
+			items = append(items, weekdays[d][:3], weekdays[end][:3])
+		default:
+			items = append(items, weekdays[d][:3]+".."+weekdays[end][:3])
+		}
+		d = end
+	}
+	return strings.Join(items, ",")
+}
+
+func (chain calendarChain) format(width int) string {
+	if chain == nil {
+		return "*"
+	}
+	items := make([]string, len(chain))
+	for i, v := range chain {
+		item := fmt.Sprintf("%0*d", width, v.start)
+		if v.stop >= 0 {
+			item += fmt.Sprintf("..%0*d", width, v.stop)
+		}
+		if v.repeat > 0 {
+			item += "/" + strconv.Itoa(v.repeat)
+		}
+		items[i] = item
+	}
+	return strings.Join(items, ",")
+}
+
+// matches tells if n matches the chain, reverse is used for the days counted from the end of the month
+// for which repetitions go backwards.
+func (chain calendarChain) matches(n int, reverse bool) bool {
+	if chain == nil {
+		return true
+	}
+	for _, v := range chain {
+		switch {
+		case v.repeat > 0 && reverse:
+			if n <= v.start && (v.stop < 0 || n >= v.stop) && (v.start-n)%v.repeat == 0 {
+				return true
+			}
+		case v.repeat > 0:
+			if n >= v.start && (v.stop < 0 || n <= v.stop) && (n-v.start)%v.repeat == 0 {
+				return true
+			}
+		case v.stop >= 0:
+			if n >= v.start && n <= v.stop {
+				return true
+			}
+		case n == v.start:
+			return true
+		}
+	}
+	return false
+}
+
+// NextElapse returns the first time strictly after after matching the calendar expression,
+// in its time zone (or after's one if it has none). ok is false if it never elapses again
+// (eg: "2020-01-01" or "*-02-30").
+func (c *Calendar) NextElapse(after time.Time) (next time.Time, ok bool) {
+	loc := c.location
+	if loc == nil {
+		loc = after.Location()
+	}
+	t := after.In(loc).Truncate(time.Second).Add(time.Second)
+	for t.Year() <= calendarMaxYear {
+		year, month, day := t.Date()
+		switch {
+		case !c.year.matches(year, false):
+			t = time.Date(year+1, time.January, 1, 0, 0, 0, 0, loc)
+		case !c.month.matches(int(month), false):
+			t = time.Date(year, month+1, 1, 0, 0, 0, 0, loc)
+		case !c.dayMatches(t):
+			t = time.Date(year, month, day+1, 0, 0, 0, 0, loc)
+		case !c.hour.matches(t.Hour(), false):
+			// advance in absolute time so repeated hours (DST) do not loop
+			t = t.Add(time.Hour - time.Duration(t.Minute())*time.Minute - time.Duration(t.Second())*time.Second)
+		case !c.minute.matches(t.Minute(), false):
+			t = t.Add(time.Minute - time.Duration(t.Second())*time.Second)
+		case !c.second.matches(t.Second(), false):
+			t = t.Add(time.Second)
+		default:
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (c *Calendar) dayMatches(t time.Time) bool {
+	if c.weekdays != 0 {
+		// time.Weekday starts on Sunday
+		if c.weekdays&(1<<((int(t.Weekday())+6)%7)) == 0 {
+			return false
+		}
+	}
+	if !c.endOfMonth {
+		return c.day.matches(t.Day(), false)
+	}
+	daysInMonth := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	return c.day.matches(daysInMonth-t.Day()+1, true)
+}
@@ -0,0 +1,86 @@
+package unitfile
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseCalendar(t *testing.T) {
+	for expr, normalized := range map[string]string{
+		"hourly":                        "*-*-* *:00:00",
+		"weekly":                        "Mon *-*-* 00:00:00",
+		"quarterly":                     "*-01,04,07,10-01 00:00:00",
+		"Mon..Fri *-*-* 02:00":          "Mon..Fri *-*-* 02:00:00",
+		"mon,tue,Sunday 2025-6-1 1:2:3": "Mon,Tue,Sun 2025-06-01 01:02:03",
+		"*:0/15":                        "*-*-* *:00/15:00",
+		"*-02~03":                       "*-02~03 00:00:00",
+		"24-12-25 12:00 UTC":            "2024-12-25 12:00:00 UTC",
+		"*-*-1..7,15 *:*:*/10":          "*-*-01..07,15 *:*:00/10",
+		"Mon..Sun 12:00":                "*-*-* 12:00:00",
+		"Sat..Fri":                      "*-*-* 00:00:00",
+		// systemd.time(7) examples
+		"Sat,Thu,Mon..Wed,Sat..Sun":   "Mon..Thu,Sat,Sun *-*-* 00:00:00",
+		"Mon,Sun 12-*-* 2,1:23":       "Mon,Sun 2012-*-* 01,02:23:00",
+		"Wed *-1":                     "Wed *-*-01 00:00:00",
+		"Wed..Wed,Wed *-1":            "Wed *-*-01 00:00:00",
+		"Wed, 17:48":                  "Wed *-*-* 17:48:00",
+		"Wed..Sat,Tue 12-10-15 1:2:3": "Tue..Sat 2012-10-15 01:02:03",
+		"*-*-7 0:0:0":                 "*-*-07 00:00:00",
+		"10-15":                       "*-10-15 00:00:00",
+		"monday *-12-* 17:00":         "Mon *-12-* 17:00:00",
+		"Mon,Fri *-*-3,1,2 *:30:45":   "Mon,Fri *-*-01,02,03 *:30:45",
+		"12,14,13,12:20,10,30":        "*-*-* 12,13,14:10,20,30:00",
+		"12..14:10,20,30":             "*-*-* 12..14:10,20,30:00",
+		"mon,fri *-1/2-1,3 *:30:45":   "Mon,Fri *-01/2-01,03 *:30:45",
+		"monthly":                     "*-*-01 00:00:00",
+		"*:2/3":                       "*-*-* *:02/3:00",
+	} {
+		c, err := ParseCalendar(expr)
+		if err != nil {
+			t.Errorf("%s: %v", expr, err)
+			continue
+		}
+		if c.String() != normalized {
+			t.Errorf("%s: expected %q, got %q", expr, normalized, c.String())
+		}
+	}
+	for _, expr := range []string{"", "Mon..Foo", "*-13-01", "*-*-* 24:00", "*-*-* *:*:*/0", "daily nowhere", "*-*-5..1"} {
+		if _, err := ParseCalendar(expr); !errors.Is(err, ErrInvalidCalendar) {
+			t.Errorf("%q: expected ErrInvalidCalendar, got %v", expr, err)
+		}
+	}
+}
+
+func TestCalendarNextElapse(t *testing.T) {
+	after := time.Date(2024, time.February, 23, 14, 7, 30, 0, time.UTC) // a Friday
+	for expr, expected := range map[string]time.Time{
+		"hourly":                   time.Date(2024, time.February, 23, 15, 0, 0, 0, time.UTC),
+		"Mon..Fri *-*-* 02:00:00":  time.Date(2024, time.February, 26, 2, 0, 0, 0, time.UTC),
+		"*:0/15":                   time.Date(2024, time.February, 23, 14, 15, 0, 0, time.UTC),
+		"*-02~01":                  time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+		"*-*~07/2":                 time.Date(2024, time.February, 25, 0, 0, 0, 0, time.UTC),
+		"*-02-29 12:00":            time.Date(2024, time.February, 29, 12, 0, 0, 0, time.UTC),
+		"Fri *-*-13":               time.Date(2024, time.September, 13, 0, 0, 0, 0, time.UTC),
+		"*-*-* 14:07:30":           time.Date(2024, time.February, 24, 14, 7, 30, 0, time.UTC),
+		"2024-02-23 15:00 Etc/GMT": time.Date(2024, time.February, 23, 15, 0, 0, 0, time.UTC),
+	} {
+		c, err := ParseCalendar(expr)
+		if err != nil {
+			t.Fatalf("%s: %v", expr, err)
+		}
+		next, ok := c.NextElapse(after)
+		if !ok || !next.Equal(expected) {
+			t.Errorf("%s: expected %s, got %s (%v)", expr, expected, next, ok)
+		}
+	}
+	for _, expr := range []string{"2020-01-01", "*-02-30"} {
+		c, err := ParseCalendar(expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if next, ok := c.NextElapse(after); ok {
+			t.Errorf("%s: unexpected elapse %s", expr, next)
+		}
+	}
+}
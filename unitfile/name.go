@@ -0,0 +1,215 @@
+package unitfile
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// UnitNameMax is the maximum length of a unit name.
+const UnitNameMax = 255
+
+// UnitTypes are the unit name suffixes known to systemd.
+var UnitTypes = []string{
+	".service", ".socket", ".device", ".mount", ".automount", ".swap",
+	".target", ".path", ".timer", ".slice", ".scope",
+}
+
+// ErrInvalidName is returned when a name can't be escaped or unescaped.
+var ErrInvalidName = errors.New("invalid unit name")
+
+// Escape escapes s like "systemd-escape" does: '/' becomes '-' and any character other
+// than ASCII letters, digits, ':', '_' and non leading '.' becomes a C style "\xNN" escape.
+func Escape(s string) string {
+	return escape(s, false)
+}
+
+// EscapePath escapes p like "systemd-escape --path" does: the path is simplified,
+// surrounding slashes are removed and the root directory becomes "-".
+func EscapePath(p string) (string, error) {
+	if p == "" {
+		return "", fmt.Errorf("%w: empty path", ErrInvalidName)
+	}
+	for _, elem := range strings.Split(p, "/") {
+		if elem == ".." {
+			return "", fmt.Errorf("%w: path %q is not normalized", ErrInvalidName, p)
+		}
+	}
+	p = strings.Trim(path.Clean("/"+p), "/")
+	if p == "" {
+		return "-", nil
+	}
+	return Escape(p), nil
+}
+
+// Unescape reverts Escape.
+func Unescape(s string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '-':
+			b.WriteByte('/')
+		case '\\':
+			if i+3 >= len(s) || s[i+1] != 'x' {
+				return "", fmt.Errorf("%w: bad escape sequence in %q", ErrInvalidName, s)
+			}
+			v, err := strconv.ParseUint(s[i+2:i+4], 16, 8)
+			if err != nil {
+				return "", fmt.Errorf("%w: bad escape sequence in %q", ErrInvalidName, s)
+			}
+			b.WriteByte(byte(v))
+			i += 3
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), nil
+}
+
+// UnescapePath reverts EscapePath, the returned path is absolute.
+func UnescapePath(s string) (string, error) {
+	if s == "-" {
+		return "/", nil
+	}
+	p, err := Unescape(s)
+	if err != nil {
+		return "", err
+	}
+	return "/" + p, nil
+}
+
+// InstanceName returns the name of the template unit instantiated with instance escaped,
+// eg: InstanceName("backup@.service", "home/user") returns "backup@home-user.service".
+func InstanceName(template, instance string) (string, error) {
+	return instanceName(template, Escape(instance))
+}
+
+// InstancePathName returns the name of the template unit instantiated with the path p escaped
+// by EscapePath, like "systemd-escape --path --template" does (the instance being read back with %f),
+// eg: InstancePathName("backup@.service", "/home/user") returns "backup@home-user.service".
+func InstancePathName(template, p string) (string, error) {
+	escaped, err := EscapePath(p)
+	if err != nil {
+		return "", err
+	}
+	return instanceName(template, escaped)
+}
+
+func instanceName(template, escaped string) (string, error) {
+	prefix, suffix, ok := strings.Cut(template, "@")
+	if !ok || prefix == "" || !validType(suffix) {
+		return "", fmt.Errorf("%w: %q is not a template", ErrInvalidName, template)
+	}
+	name := prefix + "@" + escaped + suffix
+	if len(name) > UnitNameMax {
+		return "", fmt.Errorf("%w: %q is too long", ErrInvalidName, name)
+	}
+	return name, nil
+}
+
+// TemplateInstance splits an instance name into its template and unescaped instance,
+// eg: "backup@home-user.service" gives "backup@.service" and "home/user".
+func TemplateInstance(name string) (template, instance string, err error) {
+	prefix, rest, ok := strings.Cut(name, "@")
+	dot := strings.LastIndexByte(rest, '.')
+	if !ok || prefix == "" || dot < 1 || !validType(rest[dot:]) {
+		return "", "", fmt.Errorf("%w: %q is not an instance", ErrInvalidName, name)
+	}
+	if instance, err = Unescape(rest[:dot]); err != nil {
+		return "", "", err
+	}
+	return prefix + "@" + rest[dot:], instance, nil
+}
+
+// Mangle turns a user provided string into a valid unit name like "systemctl" does:
+// valid names are kept as is, absolute paths become ".device" (under /dev) or ".mount" units,
+// other strings get their invalid characters escaped and suffix appended when they have no unit type.
+// suffix must be a unit type, eg: ".service".
+func Mangle(name, suffix string) (string, error) {
+	if !validType(suffix) {
+		return "", fmt.Errorf("%w: unknown unit type %q", ErrInvalidName, suffix)
+	}
+	if name == "" {
+		return "", fmt.Errorf("%w: empty name", ErrInvalidName)
+	}
+	if IsValidName(name) {
+		return name, nil
+	}
+	if strings.HasPrefix(name, "/") {
+		escaped, err := EscapePath(name)
+		if err != nil {
+			return "", err
+		}
+		if strings.HasPrefix(path.Clean(name), "/dev/") {
+			return escaped + ".device", nil
+		}
+		return escaped + ".mount", nil
+	}
+	mangled := escape(name, true)
+	if dot := strings.LastIndexByte(mangled, '.'); dot < 0 || !validType(mangled[dot:]) {
+		mangled += suffix
+	}
+	if len(mangled) > UnitNameMax {
+		return "", fmt.Errorf("%w: %q is too long", ErrInvalidName, mangled)
+	}
+	return mangled, nil
+}
+
+// IsValidName tells if name is a valid plain, template or instance unit name.
+func IsValidName(name string) bool {
+	if name == "" || len(name) > UnitNameMax {
+		return false
+	}
+	dot := strings.LastIndexByte(name, '.')
+	if dot < 1 || !validType(name[dot:]) {
+		return false
+	}
+	prefix, instance, templated := strings.Cut(name[:dot], "@")
+	if prefix == "" || strings.Contains(instance, "@") {
+		return false
+	}
+	for i := 0; i < dot; i++ {
+		if c := name[i]; !validChar(c) && !(templated && c == '@') {
+			return false
+		}
+	}
+	return true
+}
+
+func validType(suffix string) bool {
+	for _, t := range UnitTypes {
+		if suffix == t {
+			return true
+		}
+	}
+	return false
+}
+
+func validChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		strings.IndexByte(":-_.\\", c) >= 0
+}
+
+func escape(s string, mangle bool) string {
+	const hex = "0123456789abcdef"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '/':
+			b.WriteByte('-')
+		case mangle && (validChar(c) || c == '@'):
+			// mangling keeps the characters valid in unit names
+			b.WriteByte(c)
+		case c == '.' && i == 0, c == '-', c == '\\', !validChar(c):
+			b.WriteString(`\x`)
+			b.WriteByte(hex[c>>4])
+			b.WriteByte(hex[c&0xf])
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
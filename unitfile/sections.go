@@ -0,0 +1,553 @@
+package unitfile
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UnitFile is a typed unit file, nil sections are omitted.
+// Values of typed fields are literal: '%' is escaped so they are not taken for specifiers,
+// and command lines are quoted. Use the Extra options of each section for raw values.
+type UnitFile struct {
+	Unit    *UnitSection
+	Service *ServiceSection
+	Socket  *SocketSection
+	Timer   *TimerSection
+	Install *InstallSection
+}
+
+// UnitSection is the [Unit] section, see systemd.unit(5).
+type UnitSection struct {
+	Description           string
+	Documentation         []string
+	Requires              []string
+	Requisite             []string
+	Wants                 []string
+	BindsTo               []string
+	PartOf                []string
+	Conflicts             []string
+	Before                []string
+	After                 []string
+	OnFailure             []string
+	ConditionPathExists   []string
+	StartLimitIntervalSec time.Duration
+	StartLimitBurst       int
+	Extra                 []Option
+}
+
+// ServiceSection is the [Service] section, see systemd.service(5).
+// Command lines are argv slices, argv[0] being the binary path.
+type ServiceSection struct {
+	Type                   string
+	ExecStartPre           [][]string
+	ExecStart              [][]string
+	ExecStartPost          [][]string
+	ExecReload             [][]string
+	ExecStop               [][]string
+	ExecStopPost           [][]string
+	Restart                string
+	RestartSec             time.Duration
+	TimeoutStartSec        time.Duration
+	TimeoutStopSec         time.Duration
+	WatchdogSec            time.Duration
+	RemainAfterExit        bool
+	NotifyAccess           string
+	KillMode               string
+	User                   string
+	Group                  string
+	WorkingDirectory       string
+	Environment            []string // KEY=value entries
+	EnvironmentFile        []string
+	FileDescriptorStoreMax int
+	Extra                  []Option
+}
+
+// SocketSection is the [Socket] section, see systemd.socket(5).
+type SocketSection struct {
+	ListenStream       []string
+	ListenDatagram     []string
+	ListenSequential   []string
+	Accept             bool
+	Service            string
+	FileDescriptorName string
+	SocketUser         string
+	SocketGroup        string
+	SocketMode         string
+	Extra              []Option
+}
+
+// TimerSection is the [Timer] section, see systemd.timer(5).
+type TimerSection struct {
+	OnCalendar         []string
+	OnActiveSec        time.Duration
+	OnBootSec          time.Duration
+	OnUnitActiveSec    time.Duration
+	OnUnitInactiveSec  time.Duration
+	AccuracySec        time.Duration
+	RandomizedDelaySec time.Duration
+	Persistent         bool
+	Unit               string
+	Extra              []Option
+}
+
+// InstallSection is the [Install] section, see systemd.unit(5).
+type InstallSection struct {
+	WantedBy        []string
+	RequiredBy      []string
+	Alias           []string
+	Also            []string
+	DefaultInstance string
+	Extra           []Option
+}
+
+// File converts u into a generic File.
+func (u *UnitFile) File() *File {
+	f := &File{}
+	if s := u.Unit; s != nil {
+		w := Writer{Section: f.newSection("Unit"), Literal: true}
+		w.Str("Description", s.Description)
+		w.List("Documentation", s.Documentation)
+		w.List("Requires", s.Requires)
+		w.List("Requisite", s.Requisite)
+		w.List("Wants", s.Wants)
+		w.List("BindsTo", s.BindsTo)
+		w.List("PartOf", s.PartOf)
+		w.List("Conflicts", s.Conflicts)
+		w.List("Before", s.Before)
+		w.List("After", s.After)
+		w.List("OnFailure", s.OnFailure)
+		w.Each("ConditionPathExists", s.ConditionPathExists)
+		w.Span("StartLimitIntervalSec", s.StartLimitIntervalSec)
+		w.Int("StartLimitBurst", s.StartLimitBurst)
+		w.Extra(s.Extra)
+	}
+	if s := u.Service; s != nil {
+		w := Writer{Section: f.newSection("Service"), Literal: true}
+		w.Str("Type", s.Type)
+		w.Exec("ExecStartPre", s.ExecStartPre)
+		w.Exec("ExecStart", s.ExecStart)
+		w.Exec("ExecStartPost", s.ExecStartPost)
+		w.Exec("ExecReload", s.ExecReload)
+		w.Exec("ExecStop", s.ExecStop)
+		w.Exec("ExecStopPost", s.ExecStopPost)
+		w.Str("Restart", s.Restart)
+		w.Span("RestartSec", s.RestartSec)
+		w.Span("TimeoutStartSec", s.TimeoutStartSec)
+		w.Span("TimeoutStopSec", s.TimeoutStopSec)
+		w.Span("WatchdogSec", s.WatchdogSec)
+		w.Bool("RemainAfterExit", s.RemainAfterExit)
+		w.Str("NotifyAccess", s.NotifyAccess)
+		w.Str("KillMode", s.KillMode)
+		w.Str("User", s.User)
+		w.Str("Group", s.Group)
+		w.Str("WorkingDirectory", s.WorkingDirectory)
+		for _, env := range s.Environment {
+			w.Add("Environment", quoteWord(escapeSpecifiers(env)))
+		}
+		w.Each("EnvironmentFile", s.EnvironmentFile)
+		w.Int("FileDescriptorStoreMax", s.FileDescriptorStoreMax)
+		w.Extra(s.Extra)
+	}
+	if s := u.Socket; s != nil {
+		w := Writer{Section: f.newSection("Socket"), Literal: true}
+		w.Each("ListenStream", s.ListenStream)
+		w.Each("ListenDatagram", s.ListenDatagram)
+		w.Each("ListenSequentialPacket", s.ListenSequential)
+		w.Bool("Accept", s.Accept)
+		w.Str("Service", s.Service)
+		w.Str("FileDescriptorName", s.FileDescriptorName)
+		w.Str("SocketUser", s.SocketUser)
+		w.Str("SocketGroup", s.SocketGroup)
+		w.Str("SocketMode", s.SocketMode)
+		w.Extra(s.Extra)
+	}
+	if s := u.Timer; s != nil {
+		w := Writer{Section: f.newSection("Timer"), Literal: true}
+		w.Each("OnCalendar", s.OnCalendar)
+		w.Span("OnActiveSec", s.OnActiveSec)
+		w.Span("OnBootSec", s.OnBootSec)
+		w.Span("OnUnitActiveSec", s.OnUnitActiveSec)
+		w.Span("OnUnitInactiveSec", s.OnUnitInactiveSec)
+		w.Span("AccuracySec", s.AccuracySec)
+		w.Span("RandomizedDelaySec", s.RandomizedDelaySec)
+		w.Bool("Persistent", s.Persistent)
+		w.Str("Unit", s.Unit)
+		w.Extra(s.Extra)
+	}
+	if s := u.Install; s != nil {
+		w := Writer{Section: f.newSection("Install"), Literal: true}
+		w.List("WantedBy", s.WantedBy)
+		w.List("RequiredBy", s.RequiredBy)
+		w.List("Alias", s.Alias)
+		w.List("Also", s.Also)
+		w.Str("DefaultInstance", s.DefaultInstance)
+		w.Extra(s.Extra)
+	}
+	return f
+}
+
+// Marshal returns the unit file content of u.
+func Marshal(u *UnitFile) ([]byte, error) {
+	return u.File().Marshal()
+}
+
+// UnitFile converts f into a typed unit file, the reverse of UnitFile.File.
+// Options which are not typed fields, or which values can't be represented literally by their field
+// (eg: they contain specifiers or variables), are kept as is in the Extra options of their section.
+// An empty assignment resets the field. Sections other than the UnitFile ones are ignored.
+// It fails if a typed value is invalid (eg: a malformed time span).
+func (f *File) UnitFile() (*UnitFile, error) {
+	u := &UnitFile{}
+	for _, section := range f.Sections {
+		var (
+			fields map[string]field
+			extra  *[]Option
+		)
+		switch section.Name {
+		case "Unit":
+			if u.Unit == nil {
+				u.Unit = &UnitSection{}
+			}
+			s := u.Unit
+			fields = map[string]field{
+				"Description":           strField(&s.Description),
+				"Documentation":         listField(&s.Documentation),
+				"Requires":              listField(&s.Requires),
+				"Requisite":             listField(&s.Requisite),
+				"Wants":                 listField(&s.Wants),
+				"BindsTo":               listField(&s.BindsTo),
+				"PartOf":                listField(&s.PartOf),
+				"Conflicts":             listField(&s.Conflicts),
+				"Before":                listField(&s.Before),
+				"After":                 listField(&s.After),
+				"OnFailure":             listField(&s.OnFailure),
+				"ConditionPathExists":   eachField(&s.ConditionPathExists),
+				"StartLimitIntervalSec": spanField(&s.StartLimitIntervalSec),
+				"StartLimitBurst":       intField(&s.StartLimitBurst),
+			}
+			extra = &s.Extra
+		case "Service":
+			if u.Service == nil {
+				u.Service = &ServiceSection{}
+			}
+			s := u.Service
+			fields = map[string]field{
+				"Type":                   strField(&s.Type),
+				"ExecStartPre":           execField(&s.ExecStartPre),
+				"ExecStart":              execField(&s.ExecStart),
+				"ExecStartPost":          execField(&s.ExecStartPost),
+				"ExecReload":             execField(&s.ExecReload),
+				"ExecStop":               execField(&s.ExecStop),
+				"ExecStopPost":           execField(&s.ExecStopPost),
+				"Restart":                strField(&s.Restart),
+				"RestartSec":             spanField(&s.RestartSec),
+				"TimeoutStartSec":        spanField(&s.TimeoutStartSec),
+				"TimeoutStopSec":         spanField(&s.TimeoutStopSec),
+				"WatchdogSec":            spanField(&s.WatchdogSec),
+				"RemainAfterExit":        boolField(&s.RemainAfterExit),
+				"NotifyAccess":           strField(&s.NotifyAccess),
+				"KillMode":               strField(&s.KillMode),
+				"User":                   strField(&s.User),
+				"Group":                  strField(&s.Group),
+				"WorkingDirectory":       strField(&s.WorkingDirectory),
+				"Environment":            envField(&s.Environment),
+				"EnvironmentFile":        eachField(&s.EnvironmentFile),
+				"FileDescriptorStoreMax": intField(&s.FileDescriptorStoreMax),
+			}
+			extra = &s.Extra
+		case "Socket":
+			if u.Socket == nil {
+				u.Socket = &SocketSection{}
+			}
+			s := u.Socket
+			fields = map[string]field{
+				"ListenStream":           eachField(&s.ListenStream),
+				"ListenDatagram":         eachField(&s.ListenDatagram),
+				"ListenSequentialPacket": eachField(&s.ListenSequential),
+				"Accept":                 boolField(&s.Accept),
+				"Service":                strField(&s.Service),
+				"FileDescriptorName":     strField(&s.FileDescriptorName),
+				"SocketUser":             strField(&s.SocketUser),
+				"SocketGroup":            strField(&s.SocketGroup),
+				"SocketMode":             strField(&s.SocketMode),
+			}
+			extra = &s.Extra
+		case "Timer":
+			if u.Timer == nil {
+				u.Timer = &TimerSection{}
+			}
+			s := u.Timer
+			fields = map[string]field{
+				"OnCalendar":         eachField(&s.OnCalendar),
+				"OnActiveSec":        spanField(&s.OnActiveSec),
+				"OnBootSec":          spanField(&s.OnBootSec),
+				"OnUnitActiveSec":    spanField(&s.OnUnitActiveSec),
+				"OnUnitInactiveSec":  spanField(&s.OnUnitInactiveSec),
+				"AccuracySec":        spanField(&s.AccuracySec),
+				"RandomizedDelaySec": spanField(&s.RandomizedDelaySec),
+				"Persistent":         boolField(&s.Persistent),
+				"Unit":               strField(&s.Unit),
+			}
+			extra = &s.Extra
+		case "Install":
+			if u.Install == nil {
+				u.Install = &InstallSection{}
+			}
+			s := u.Install
+			fields = map[string]field{
+				"WantedBy":        listField(&s.WantedBy),
+				"RequiredBy":      listField(&s.RequiredBy),
+				"Alias":           listField(&s.Alias),
+				"Also":            listField(&s.Also),
+				"DefaultInstance": strField(&s.DefaultInstance),
+			}
+			extra = &s.Extra
+		default:
+			continue
+		}
+		for _, o := range section.Options {
+			set, ok := fields[o.Name]
+			if !ok {
+				*extra = append(*extra, o)
+				continue
+			}
+			literal, err := set(o.Value)
+			if err != nil {
+				return nil, fmt.Errorf("[%s] %s: %w", section.Name, o.Name, err)
+			}
+			if !literal {
+				*extra = append(*extra, o)
+			}
+		}
+	}
+	return u, nil
+}
+
+// Unmarshal parses the unit file content data into a typed unit file, see File.UnitFile.
+func Unmarshal(data []byte) (*UnitFile, error) {
+	f, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return f.UnitFile()
+}
+
+// field sets a typed field from a raw value, it returns false if the value can't be represented
+// literally by the field, which is then left untouched.
+type field func(value string) (bool, error)
+
+func strField(p *string) field {
+	return func(value string) (bool, error) {
+		v, ok := unescapeSpecifiers(value)
+		if ok {
+			*p = v
+		}
+		return ok, nil
+	}
+}
+
+func eachField(p *[]string) field {
+	return func(value string) (bool, error) {
+		if value == "" {
+			*p = nil
+			return true, nil
+		}
+		v, ok := unescapeSpecifiers(value)
+		if ok {
+			*p = append(*p, v)
+		}
+		return ok, nil
+	}
+}
+
+func listField(p *[]string) field {
+	return func(value string) (bool, error) {
+		if value == "" {
+			*p = nil
+			return true, nil
+		}
+		v, ok := unescapeSpecifiers(value)
+		if ok {
+			*p = append(*p, strings.Fields(v)...)
+		}
+		return ok, nil
+	}
+}
+
+func boolField(p *bool) field {
+	return func(value string) (bool, error) {
+		switch strings.ToLower(value) {
+		case "1", "yes", "y", "true", "t", "on":
+			*p = true
+		case "0", "no", "n", "false", "f", "off", "":
+			*p = false
+		default:
+			return false, fmt.Errorf("invalid boolean %q", value)
+		}
+		return true, nil
+	}
+}
+
+func intField(p *int) field {
+	return func(value string) (bool, error) {
+		if value == "" {
+			*p = 0
+			return true, nil
+		}
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return false, err
+		}
+		*p = v
+		return true, nil
+	}
+}
+
+func spanField(p *time.Duration) field {
+	return func(value string) (bool, error) {
+		if value == "" {
+			*p = 0
+			return true, nil
+		}
+		d, err := ParseTimeSpan(value)
+		if err != nil {
+			return false, err
+		}
+		*p = d
+		return true, nil
+	}
+}
+
+func execField(p *[][]string) field {
+	return func(value string) (bool, error) {
+		if value == "" {
+			*p = nil
+			return true, nil
+		}
+		argv, ok := unquoteCommandLine(value)
+		if ok {
+			*p = append(*p, argv)
+		}
+		return ok, nil
+	}
+}
+
+func envField(p *[]string) field {
+	return func(value string) (bool, error) {
+		if value == "" {
+			*p = nil
+			return true, nil
+		}
+		v, ok := unescapeSpecifiers(value)
+		if !ok {
+			return false, nil
+		}
+		words, ok := splitWords(v)
+		if ok {
+			*p = append(*p, words...)
+		}
+		return ok, nil
+	}
+}
+
+// escapeSpecifiers escapes '%' so the value is not subject to specifier expansion.
+func escapeSpecifiers(s string) string {
+	return strings.ReplaceAll(s, "%", "%%")
+}
+
+// unescapeSpecifiers reverts escapeSpecifiers, it returns false if s contains specifiers.
+func unescapeSpecifiers(s string) (string, bool) {
+	if strings.Count(strings.ReplaceAll(s, "%%", ""), "%") > 0 {
+		return "", false
+	}
+	return strings.ReplaceAll(s, "%%", "%"), true
+}
+
+// QuoteCommandLine returns argv as a literal Exec*= command line: arguments are quoted when needed,
+// and '$' and '%' are escaped so they are not subject to variable nor specifier expansion.
+func QuoteCommandLine(argv []string) string {
+	words := make([]string, len(argv))
+	for i, arg := range argv {
+		arg = strings.ReplaceAll(escapeSpecifiers(arg), "$", "$$")
+		words[i] = quoteWord(arg)
+	}
+	return strings.Join(words, " ")
+}
+
+// unquoteCommandLine reverts QuoteCommandLine, it returns false if s is not a literal command line
+// (eg: it has an Exec*= prefix, specifiers or variables).
+func unquoteCommandLine(s string) ([]string, bool) {
+	if strings.ContainsAny(s[:1], "-@:+!|") {
+		return nil, false
+	}
+	s, ok := unescapeSpecifiers(s)
+	if !ok || strings.Count(strings.ReplaceAll(s, "$$", ""), "$") > 0 {
+		return nil, false
+	}
+	words, ok := splitWords(strings.ReplaceAll(s, "$$", "$"))
+	if !ok || len(words) == 0 {
+		return nil, false
+	}
+	return words, true
+}
+
+// splitWords splits s on whitespace, unquoting the words quoted by quoteWord (or single quoted).
+// It returns false on an unterminated quote.
+func splitWords(s string) ([]string, bool) {
+	var (
+		words  []string
+		word   strings.Builder
+		inWord bool
+		quote  byte
+	)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote == 0 && (c == ' ' || c == '\t'):
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+			continue
+		case quote == 0 && (c == '"' || c == '\''):
+			quote = c
+		case c == quote:
+			quote = 0
+		case c == '\\' && quote != '\'' && i+1 < len(s):
+			i++
+			word.WriteByte(s[i])
+		default:
+			word.WriteByte(c)
+		}
+		inWord = true
+	}
+	if quote != 0 {
+		return nil, false
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, true
+}
+
+// quoteWord double quotes s if it is empty or contains whitespace, quotes or backslashes.
+func quoteWord(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\"'\\;") {
+		return s
+	}
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,314 @@
+package unitfile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	sysdid128 "github.com/iguanesolutions/go-systemd/v6/id128"
+	"github.com/iguanesolutions/go-systemd/v6/internal/osrelease"
+)
+
+// ErrUnknownSpecifier is returned when a value contains a specifier which is not supported.
+var ErrUnknownSpecifier = errors.New("unknown specifier")
+
+// Specifiers holds what unit file specifiers (see systemd.unit(5)) expand to for a unit.
+// The unit related ones (%n, %N, %p, %P, %i, %I, %j, %J, %f, %y, %Y) are computed from Unit and FragmentPath,
+// the others are the host context fields, filled from the running host by HostSpecifiers.
+type Specifiers struct {
+	Unit         string // full unit name, eg: backup@home-user.service
+	FragmentPath string // path of the unit file, eg: /etc/systemd/system/backup@.service
+
+	Architecture   string // %a, eg: x86-64
+	OSImageVersion string // %A
+	BootID         string // %b
+	OSBuildID      string // %B
+	CacheDir       string // %C
+	CredentialsDir string // %d
+	DataDir        string // %D
+	ConfigDir      string // %E
+	GroupName      string // %g
+	GID            string // %G
+	Home           string // %h
+	Hostname       string // %H
+	LogsDir        string // %L
+	MachineID      string // %m
+	OSImageID      string // %M
+	OSID           string // %o
+	PrettyHostname string // %q
+	Shell          string // %s
+	StateDir       string // %S
+	RuntimeDir     string // %t
+	TempDir        string // %T
+	UserName       string // %u
+	UID            string // %U
+	KernelRelease  string // %v
+	VarTempDir     string // %V
+	OSVersionID    string // %w
+	OSVariantID    string // %W
+}
+
+// HostSpecifiers returns the specifiers of unit on the running host as the system manager
+// (or the user manager of the calling user if userManager is true) expands them.
+// Host details which can't be read are left empty.
+// unit: full unit name
+// userManager: expand for the user manager
+func HostSpecifiers(unit string, userManager bool) (*Specifiers, error) {
+	if !IsValidName(unit) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidName, unit)
+	}
+	s := &Specifiers{
+		Unit:         unit,
+		Architecture: architecture(),
+		TempDir:      os.Getenv("TMPDIR"),
+		VarTempDir:   os.Getenv("TMPDIR"),
+	}
+	if s.TempDir == "" {
+		s.TempDir, s.VarTempDir = "/tmp", "/var/tmp"
+	}
+	if id, err := sysdid128.MachineID(); err == nil {
+		s.MachineID = id.String()
+	}
+	if id, err := sysdid128.BootID(); err == nil {
+		s.BootID = id.String()
+	}
+	s.Hostname, _ = os.Hostname()
+	s.PrettyHostname = s.Hostname
+	if data, err := os.ReadFile("/etc/machine-info"); err == nil {
+		if pretty := osrelease.Parse(data)["PRETTY_HOSTNAME"]; pretty != "" {
+			s.PrettyHostname = pretty
+		}
+	}
+	if release, err := os.ReadFile("/proc/sys/kernel/osrelease"); err == nil {
+		s.KernelRelease = strings.TrimSpace(string(release))
+	}
+	osRelease, _ := osrelease.Read()
+	s.OSID = osRelease["ID"]
+	s.OSVersionID = osRelease["VERSION_ID"]
+	s.OSBuildID = osRelease["BUILD_ID"]
+	s.OSVariantID = osRelease["VARIANT_ID"]
+	s.OSImageID = osRelease["IMAGE_ID"]
+	s.OSImageVersion = osRelease["IMAGE_VERSION"]
+	if !userManager {
+		s.UserName, s.UID, s.GroupName, s.GID = "root", "0", "root", "0"
+		s.Home, s.Shell = "/root", "/bin/sh"
+		s.RuntimeDir, s.StateDir, s.CacheDir, s.LogsDir = "/run", "/var/lib", "/var/cache", "/var/log"
+		s.ConfigDir, s.DataDir = "/etc", "/usr/share"
+		s.CredentialsDir = "/run/credentials/" + unit
+		return s, nil
+	}
+	u, err := user.Current()
+	if err != nil {
+		return nil, err
+	}
+	s.UserName, s.UID, s.GID, s.Home = u.Username, u.Uid, u.Gid, u.HomeDir
+	if g, err := user.LookupGroupId(u.Gid); err == nil {
+		s.GroupName = g.Name
+	}
+	s.Shell = loginShell(u.Username)
+	s.RuntimeDir = os.Getenv("XDG_RUNTIME_DIR")
+	if s.RuntimeDir == "" {
+		s.RuntimeDir = "/run/user/" + u.Uid
+	}
+	s.StateDir = xdgDir("XDG_STATE_HOME", u.HomeDir, ".local/state")
+	s.CacheDir = xdgDir("XDG_CACHE_HOME", u.HomeDir, ".cache")
+	s.LogsDir = filepath.Join(s.StateDir, "log")
+	s.ConfigDir = xdgDir("XDG_CONFIG_HOME", u.HomeDir, ".config")
+	s.DataDir = xdgDir("XDG_DATA_HOME", u.HomeDir, ".local/share")
+	s.CredentialsDir = filepath.Join(s.RuntimeDir, "credentials", unit)
+	return s, nil
+}
+
+// Expand returns value with its specifiers expanded, "%%" becoming "%".
+func (s *Specifiers) Expand(value string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '%' {
+			b.WriteByte(value[i])
+			continue
+		}
+		if i+1 >= len(value) {
+			return "", fmt.Errorf("%w: trailing %% in %q", ErrUnknownSpecifier, value)
+		}
+		i++
+		expanded, err := s.expand(value[i])
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(expanded)
+	}
+	return b.String(), nil
+}
+
+func (s *Specifiers) expand(spec byte) (string, error) {
+	switch spec {
+	case '%':
+		return "%", nil
+	case 'n':
+		return s.Unit, nil
+	case 'N':
+		return s.unitName(), nil
+	case 'p':
+		return s.prefix(), nil
+	case 'P':
+		return Unescape(s.prefix())
+	case 'i':
+		return s.instance(), nil
+	case 'I':
+		return Unescape(s.instance())
+	case 'j':
+		return s.lastPrefixComponent(), nil
+	case 'J':
+		return Unescape(s.lastPrefixComponent())
+	case 'f':
+		if instance := s.instance(); instance != "" {
+			return UnescapePath(instance)
+		}
+		return UnescapePath(s.prefix())
+	case 'y':
+		return s.FragmentPath, nil
+	case 'Y':
+		if s.FragmentPath == "" {
+			return "", nil
+		}
+		return path.Dir(s.FragmentPath), nil
+	case 'l':
+		short, _, _ := strings.Cut(s.Hostname, ".")
+		return short, nil
+	case 'a':
+		return s.Architecture, nil
+	case 'A':
+		return s.OSImageVersion, nil
+	case 'b':
+		return s.BootID, nil
+	case 'B':
+		return s.OSBuildID, nil
+	case 'C':
+		return s.CacheDir, nil
+	case 'd':
+		return s.CredentialsDir, nil
+	case 'D':
+		return s.DataDir, nil
+	case 'E':
+		return s.ConfigDir, nil
+	case 'g':
+		return s.GroupName, nil
+	case 'G':
+		return s.GID, nil
+	case 'h':
+		return s.Home, nil
+	case 'H':
+		return s.Hostname, nil
+	case 'L':
+		return s.LogsDir, nil
+	case 'm':
+		return s.MachineID, nil
+	case 'M':
+		return s.OSImageID, nil
+	case 'o':
+		return s.OSID, nil
+	case 'q':
+		return s.PrettyHostname, nil
+	case 's':
+		return s.Shell, nil
+	case 'S':
+		return s.StateDir, nil
+	case 't':
+		return s.RuntimeDir, nil
+	case 'T':
+		return s.TempDir, nil
+	case 'u':
+		return s.UserName, nil
+	case 'U':
+		return s.UID, nil
+	case 'v':
+		return s.KernelRelease, nil
+	case 'V':
+		return s.VarTempDir, nil
+	case 'w':
+		return s.OSVersionID, nil
+	case 'W':
+		return s.OSVariantID, nil
+	}
+	return "", fmt.Errorf("%w: %%%c", ErrUnknownSpecifier, spec)
+}
+
+// unitName returns the unit name without its type suffix.
+func (s *Specifiers) unitName() string {
+	if dot := strings.LastIndexByte(s.Unit, '.'); dot >= 0 {
+		return s.Unit[:dot]
+	}
+	return s.Unit
+}
+
+// prefix returns the part of the unit name before '@', or the name without its suffix.
+func (s *Specifiers) prefix() string {
+	prefix, _, _ := strings.Cut(s.unitName(), "@")
+	return prefix
+}
+
+func (s *Specifiers) instance() string {
+	_, instance, _ := strings.Cut(s.unitName(), "@")
+	return instance
+}
+
+// lastPrefixComponent returns the part of the prefix after its last '-'.
+func (s *Specifiers) lastPrefixComponent() string {
+	prefix := s.prefix()
+	return prefix[strings.LastIndexByte(prefix, '-')+1:]
+}
+
+// architecture returns the systemd name of the architecture (ConditionArchitecture=).
+func architecture() string {
+	switch runtime.GOARCH {
+	case "amd64":
+		return "x86-64"
+	case "386":
+		return "x86"
+	case "arm64":
+		return "arm64"
+	case "arm":
+		return "arm"
+	case "ppc64":
+		return "ppc64"
+	case "ppc64le":
+		return "ppc64-le"
+	case "riscv64":
+		return "riscv64"
+	case "loong64":
+		return "loongarch64"
+	case "mips64le":
+		return "mips64-le"
+	case "s390x":
+		return "s390x"
+	default:
+		return runtime.GOARCH
+	}
+}
+
+// loginShell returns the shell of name from /etc/passwd, /bin/sh if not found.
+func loginShell(name string) string {
+	data, err := os.ReadFile("/etc/passwd")
+	if err != nil {
+		return "/bin/sh"
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Split(line, ":")
+		if len(fields) == 7 && fields[0] == name && fields[6] != "" {
+			return fields[6]
+		}
+	}
+	return "/bin/sh"
+}
+
+func xdgDir(env, home, def string) string {
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(home, def)
+}
@@ -0,0 +1,57 @@
+package unitfile
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSpecifiersExpand(t *testing.T) {
+	s := &Specifiers{
+		Unit:         "backup-home@home-user-my\\x20docs.service",
+		FragmentPath: "/etc/systemd/system/backup-home@.service",
+		Hostname:     "host.example.com",
+		RuntimeDir:   "/run",
+	}
+	for value, expected := range map[string]string{
+		"%n":         "backup-home@home-user-my\\x20docs.service",
+		"%N":         "backup-home@home-user-my\\x20docs",
+		"%p %P":      "backup-home backup/home",
+		"%i":         "home-user-my\\x20docs",
+		"%I":         "home/user/my docs",
+		"%j %J":      "home home",
+		"%f":         "/home/user/my docs",
+		"%Y/%y":      "/etc/systemd/system//etc/systemd/system/backup-home@.service",
+		"%t/%p.sock": "/run/backup-home.sock",
+		"%H %l":      "host.example.com host",
+		"100%%":      "100%",
+	} {
+		expanded, err := s.Expand(value)
+		if err != nil {
+			t.Errorf("%s: %v", value, err)
+		} else if expanded != expected {
+			t.Errorf("%s: expected %q, got %q", value, expected, expanded)
+		}
+	}
+	for _, value := range []string{"%z", "trailing %"} {
+		if _, err := s.Expand(value); !errors.Is(err, ErrUnknownSpecifier) {
+			t.Errorf("%q: expected ErrUnknownSpecifier, got %v", value, err)
+		}
+	}
+}
+
+func TestHostSpecifiers(t *testing.T) {
+	s, err := HostSpecifiers("foo.service", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expanded, err := s.Expand("%t/%N %d %u")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expanded != "/run/foo /run/credentials/foo.service root" {
+		t.Errorf("unexpected expansion %q", expanded)
+	}
+	if _, err = HostSpecifiers("foo", false); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("expected ErrInvalidName, got %v", err)
+	}
+}
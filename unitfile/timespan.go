@@ -0,0 +1,147 @@
+package unitfile
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Infinity is the "infinity" time span, eg: TimeoutStartSec=infinity.
+const Infinity time.Duration = math.MaxInt64
+
+// ErrInvalidTimeSpan is returned when a time span can't be parsed.
+var ErrInvalidTimeSpan = errors.New("invalid time span")
+
+// timeSpanUnits are the units of systemd.time(7), longest suffixes first so they match before their prefixes.
+var timeSpanUnits = []struct {
+	suffix string
+	d      time.Duration
+}{
+	{"seconds", time.Second},
+	{"second", time.Second},
+	{"minutes", time.Minute},
+	{"minute", time.Minute},
+	{"months", 2629800 * time.Second},
+	{"month", 2629800 * time.Second},
+	{"hours", time.Hour},
+	{"years", 31557600 * time.Second},
+	{"weeks", 7 * 24 * time.Hour},
+	{"usec", time.Microsecond},
+	{"msec", time.Millisecond},
+	{"nsec", time.Nanosecond},
+	{"hour", time.Hour},
+	{"days", 24 * time.Hour},
+	{"week", 7 * 24 * time.Hour},
+	{"year", 31557600 * time.Second},
+	{"sec", time.Second},
+	{"min", time.Minute},
+	{"day", 24 * time.Hour},
+	{"us", time.Microsecond},
+	{"µs", time.Microsecond},
+	{"μs", time.Microsecond},
+	{"ms", time.Millisecond},
+	{"ns", time.Nanosecond},
+	{"hr", time.Hour},
+	{"s", time.Second},
+	{"m", time.Minute},
+	{"h", time.Hour},
+	{"d", 24 * time.Hour},
+	{"w", 7 * 24 * time.Hour},
+	{"M", 2629800 * time.Second},
+	{"y", 31557600 * time.Second},
+}
+
+// ParseTimeSpan parses a time span of systemd.time(7), eg: "1h 30min 10s", "2.5s", "300" (seconds
+// by default) or "infinity" (Infinity).
+func ParseTimeSpan(s string) (time.Duration, error) {
+	d, err := parseTimeSpan(strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("%w %q: %w", ErrInvalidTimeSpan, s, err)
+	}
+	return d, nil
+}
+
+func parseTimeSpan(s string) (total time.Duration, err error) {
+	if s == "" {
+		return 0, errors.New("empty time span")
+	}
+	if s == "infinity" {
+		return Infinity, nil
+	}
+	for s != "" {
+		// number, possibly fractional
+		end := strings.IndexFunc(s, func(r rune) bool {
+			return (r < '0' || r > '9') && r != '.'
+		})
+		if end < 0 {
+			end = len(s)
+		}
+		if end == 0 {
+			return 0, fmt.Errorf("expected a number at %q", s)
+		}
+		value, err := strconv.ParseFloat(s[:end], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number %q", s[:end])
+		}
+		s = strings.TrimLeft(s[end:], " \t")
+		// unit, seconds if none
+		unit := time.Second
+		for _, u := range timeSpanUnits {
+			if rest, ok := strings.CutPrefix(s, u.suffix); ok && !startsWithLetter(rest) {
+				unit, s = u.d, rest
+				break
+			}
+		}
+		if startsWithLetter(s) {
+			return 0, fmt.Errorf("unknown unit at %q", s)
+		}
+		s = strings.TrimLeft(s, " \t")
+		span := value * float64(unit)
+		if span >= float64(Infinity-total) {
+			return 0, errors.New("time span too large")
+		}
+		total += time.Duration(math.Round(span))
+	}
+	return total, nil
+}
+
+func startsWithLetter(s string) bool {
+	return s != "" && (isLetter(s[0]) || strings.HasPrefix(s, "µ") || strings.HasPrefix(s, "μ"))
+}
+
+// FormatTimeSpan formats d using the units of systemd.time(7) from weeks to microseconds,
+// eg: "1min 30s". Infinity is formatted as "infinity", negative spans as "0".
+func FormatTimeSpan(d time.Duration) string {
+	if d == Infinity {
+		return "infinity"
+	}
+	if d < 0 {
+		return "0"
+	}
+	units := []struct {
+		suffix string
+		d      time.Duration
+	}{
+		{"w", 7 * 24 * time.Hour},
+		{"d", 24 * time.Hour},
+		{"h", time.Hour},
+		{"min", time.Minute},
+		{"s", time.Second},
+		{"ms", time.Millisecond},
+		{"us", time.Microsecond},
+	}
+	var parts []string
+	for _, u := range units {
+		if n := d / u.d; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d%s", n, u.suffix))
+			d -= n * u.d
+		}
+	}
+	if len(parts) == 0 {
+		return "0"
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,53 @@
+package unitfile
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseTimeSpan(t *testing.T) {
+	for s, expected := range map[string]time.Duration{
+		"1h 30min 10s":  time.Hour + 30*time.Minute + 10*time.Second,
+		"1h30min10s":    time.Hour + 30*time.Minute + 10*time.Second,
+		"300":           300 * time.Second,
+		"2.5s":          2500 * time.Millisecond,
+		"5 min":         5 * time.Minute,
+		"2weeks 1d":     15 * 24 * time.Hour,
+		"1y":            31557600 * time.Second,
+		"1M":            2629800 * time.Second,
+		"1m":            time.Minute,
+		"50ms 20µs 3us": 50*time.Millisecond + 23*time.Microsecond,
+		"0":             0,
+		"infinity":      Infinity,
+	} {
+		d, err := ParseTimeSpan(s)
+		if err != nil {
+			t.Errorf("%s: %v", s, err)
+		} else if d != expected {
+			t.Errorf("%s: expected %s, got %s", s, expected, d)
+		}
+	}
+	for _, s := range []string{"", "abc", "5 parsecs", "-1s", "1h infinity", "1000000y"} {
+		if _, err := ParseTimeSpan(s); !errors.Is(err, ErrInvalidTimeSpan) {
+			t.Errorf("%q: expected ErrInvalidTimeSpan, got %v", s, err)
+		}
+	}
+}
+
+func TestFormatTimeSpan(t *testing.T) {
+	for d, expected := range map[time.Duration]string{
+		0:                                      "0",
+		90 * time.Second:                       "1min 30s",
+		8*24*time.Hour + 1500*time.Microsecond: "1w 1d 1ms 500us",
+		Infinity:                               "infinity",
+	} {
+		s := FormatTimeSpan(d)
+		if s != expected {
+			t.Errorf("%s: expected %q, got %q", d, expected, s)
+		}
+		if parsed, err := ParseTimeSpan(s); err != nil || parsed != d {
+			t.Errorf("%q: does not parse back to %s: %s %v", s, d, parsed, err)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+// Package unitfile reads and writes systemd unit files, either generically through File
+// or from typed section structs through UnitFile, and escapes unit names like systemd-escape.
+package unitfile
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Option is a single "Name=Value" line of a section.
+// Value is raw: it is written and read as is, specifiers and escapes included.
+type Option struct {
+	Name  string
+	Value string
+}
+
+// Section is a "[Name]" section of a unit file, options are kept in order.
+type Section struct {
+	Name    string
+	Options []Option
+}
+
+// File is a generic unit file, sections are kept in order.
+type File struct {
+	Sections []*Section
+}
+
+// Section returns the first section named name, nil if missing.
+func (f *File) Section(name string) *Section {
+	for _, s := range f.Sections {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// Get returns every value of the option name in the section named section.
+func (f *File) Get(section, name string) (values []string) {
+	for _, s := range f.Sections {
+		if s.Name != section {
+			continue
+		}
+		for _, o := range s.Options {
+			if o.Name == name {
+				values = append(values, o.Value)
+			}
+		}
+	}
+	return
+}
+
+// Add appends the option name=value to the section named section, creating it if needed.
+func (f *File) Add(section, name, value string) {
+	s := f.Section(section)
+	if s == nil {
+		s = &Section{Name: section}
+		f.Sections = append(f.Sections, s)
+	}
+	s.Options = append(s.Options, Option{Name: name, Value: value})
+}
+
+// Marshal returns the unit file content.
+// It fails if a name or a value can't be represented (eg: it contains a newline).
+func (f *File) Marshal() ([]byte, error) {
+	var b bytes.Buffer
+	for i, s := range f.Sections {
+		if strings.ContainsAny(s.Name, "[]\n") || s.Name == "" {
+			return nil, fmt.Errorf("invalid section name %q", s.Name)
+		}
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(&b, "[%s]\n", s.Name)
+		for _, o := range s.Options {
+			if o.Name == "" || strings.ContainsAny(o.Name, "=\n \t") {
+				return nil, fmt.Errorf("[%s]: invalid option name %q", s.Name, o.Name)
+			}
+			if strings.ContainsAny(o.Value, "\r\n") {
+				return nil, fmt.Errorf("[%s] %s: value contains a newline", s.Name, o.Name)
+			}
+			if strings.HasSuffix(o.Value, `\`) {
+				return nil, fmt.Errorf("[%s] %s: value ends with a line continuation", s.Name, o.Name)
+			}
+			fmt.Fprintf(&b, "%s=%s\n", o.Name, o.Value)
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// Parse reads a unit file: comments are dropped, continuation lines are joined
+// and surrounding whitespace is trimmed, like systemd does.
+func Parse(r io.Reader) (*File, error) {
+	f := &File{}
+	var (
+		current *Section
+		pending string // continued line
+		lineNo  int
+	)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && (line[0] == '#' || line[0] == ';') {
+			// comments are ignored, even inside a continuation which goes on
+			continue
+		}
+		if pending != "" {
+			line = pending + " " + line
+			pending = ""
+		}
+		if line == "" {
+			continue
+		}
+		if strings.HasSuffix(line, `\`) {
+			pending = strings.TrimSpace(line[:len(line)-1])
+			continue
+		}
+		if line[0] == '[' {
+			if line[len(line)-1] != ']' || len(line) < 3 {
+				return nil, fmt.Errorf("line %d: invalid section header %q", lineNo, line)
+			}
+			current = &Section{Name: line[1 : len(line)-1]}
+			f.Sections = append(f.Sections, current)
+			continue
+		}
+		if current == nil {
+			return nil, fmt.Errorf("line %d: option outside of any section", lineNo)
+		}
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: missing '='", lineNo)
+		}
+		current.Options = append(current.Options, Option{
+			Name:  strings.TrimSpace(name),
+			Value: strings.TrimSpace(value),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if pending != "" {
+		return nil, fmt.Errorf("line %d: unterminated line continuation", lineNo)
+	}
+	return f, nil
+}
@@ -0,0 +1,193 @@
+package unitfile
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarshal(t *testing.T) {
+	u := &UnitFile{
+		Unit: &UnitSection{
+			Description: "Backup 100% of home",
+			After:       []string{"network-online.target", "local-fs.target"},
+		},
+		Service: &ServiceSection{
+			Type:        "oneshot",
+			ExecStart:   [][]string{{"/usr/bin/backup", "--dest", "/mnt/my backups", "$HOME"}},
+			Environment: []string{"GREETING=hello world"},
+			RestartSec:  90 * time.Second,
+		},
+		Install: &InstallSection{
+			WantedBy: []string{"multi-user.target"},
+		},
+	}
+	data, err := Marshal(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `[Unit]
+Description=Backup 100%% of home
+After=network-online.target local-fs.target
+
+[Service]
+Type=oneshot
+ExecStart=/usr/bin/backup --dest "/mnt/my backups" $$HOME
+RestartSec=1min 30s
+Environment="GREETING=hello world"
+
+[Install]
+WantedBy=multi-user.target
+`
+	if string(data) != expected {
+		t.Errorf("unexpected unit file:\n%s", data)
+	}
+	// round trip
+	again, err := Unmarshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again, u) {
+		t.Errorf("round trip mismatch: %+v %+v", again.Unit, again.Service)
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	u, err := Unmarshal([]byte(`[Unit]
+After=a.target
+After=b.target c.target
+Description=Worker %i
+[Service]
+ExecStart=-/usr/bin/worker
+ExecStart=/usr/bin/worker 'single quoted' "double \"quoted\""
+ExecStop=/bin/kill $MAINPID
+Environment="A=1 2" B=3
+TimeoutStopSec=infinity
+RemainAfterExit=on
+X-Custom=yes
+[X-Other]
+Foo=bar
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(u.Unit.After, ",") != "a.target,b.target,c.target" || u.Unit.Description != "" {
+		t.Errorf("unexpected [Unit]: %+v", u.Unit)
+	}
+	s := u.Service
+	if len(s.ExecStart) != 1 || strings.Join(s.ExecStart[0], "|") != `/usr/bin/worker|single quoted|double "quoted"` || len(s.ExecStop) != 0 {
+		t.Errorf("unexpected commands: %q %q", s.ExecStart, s.ExecStop)
+	}
+	if strings.Join(s.Environment, ",") != "A=1 2,B=3" || s.TimeoutStopSec != Infinity || !s.RemainAfterExit {
+		t.Errorf("unexpected [Service]: %+v", s)
+	}
+	// values with specifiers, variables or prefixes are kept raw
+	var extra []string
+	for _, o := range append(u.Unit.Extra, s.Extra...) {
+		extra = append(extra, o.Name+"="+o.Value)
+	}
+	if strings.Join(extra, ",") != "Description=Worker %i,ExecStart=-/usr/bin/worker,ExecStop=/bin/kill $MAINPID,X-Custom=yes" {
+		t.Errorf("unexpected extra options: %q", extra)
+	}
+	if _, err = Unmarshal([]byte("[Service]\nRestartSec=soon\n")); err == nil {
+		t.Error("invalid time span should fail")
+	}
+}
+
+func TestParse(t *testing.T) {
+	f, err := Parse(strings.NewReader(`# comment
+[Service]
+ExecStart=/bin/echo \
+# comment inside a continuation
+	hello
+; other comment
+Environment=A=1
+Environment=B=2
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exec := f.Get("Service", "ExecStart"); len(exec) != 1 || exec[0] != "/bin/echo hello" {
+		t.Error("unexpected ExecStart", exec)
+	}
+	if env := f.Get("Service", "Environment"); len(env) != 2 || env[1] != "B=2" {
+		t.Error("unexpected Environment", env)
+	}
+	if _, err = Parse(strings.NewReader("Foo=bar\n")); err == nil {
+		t.Error("option outside of a section should fail")
+	}
+	if _, err = (&File{Sections: []*Section{{Name: "Unit", Options: []Option{{"Description", "a\nb"}}}}}).Marshal(); err == nil {
+		t.Error("newline in value should fail")
+	}
+}
+
+func TestEscape(t *testing.T) {
+	for in, expected := range map[string]string{
+		"home/user data": `home-user\x20data`,
+		".hidden-dir":    `\x2ehidden\x2ddir`,
+		"a:b_c.d":        "a:b_c.d",
+		"Ünicode":        `\xc3\x9cnicode`,
+		`back\slash`:     `back\x5cslash`,
+	} {
+		escaped := Escape(in)
+		if escaped != expected {
+			t.Errorf("Escape(%q) = %q, expected %q", in, escaped, expected)
+		}
+		if unescaped, err := Unescape(escaped); err != nil || unescaped != in {
+			t.Errorf("Unescape(%q) = %q, %v", escaped, unescaped, err)
+		}
+	}
+	for in, expected := range map[string]string{
+		"/":                "-",
+		"/home//user/":     "home-user",
+		"/var/lib/my-data": `var-lib-my\x2ddata`,
+	} {
+		if escaped, err := EscapePath(in); err != nil || escaped != expected {
+			t.Errorf("EscapePath(%q) = %q, %v, expected %q", in, escaped, err, expected)
+		}
+	}
+	if _, err := EscapePath("/home/../etc"); err == nil {
+		t.Error("non normalized path should fail")
+	}
+	if p, err := UnescapePath("home-user"); err != nil || p != "/home/user" {
+		t.Errorf("unexpected UnescapePath: %q, %v", p, err)
+	}
+}
+
+func TestInstanceName(t *testing.T) {
+	name, err := InstanceName("backup@.service", "/home/user/data")
+	if err != nil || name != "backup@-home-user-data.service" {
+		t.Errorf("unexpected instance name: %q, %v", name, err)
+	}
+	template, instance, err := TemplateInstance(name)
+	if err != nil || template != "backup@.service" || instance != "/home/user/data" {
+		t.Errorf("unexpected template instance: %q %q, %v", template, instance, err)
+	}
+	if _, err = InstanceName("backup.service", "x"); err == nil {
+		t.Error("non template should fail")
+	}
+	name, err = InstancePathName("backup@.service", "/home/user/my-data/")
+	if err != nil || name != `backup@home-user-my\x2ddata.service` {
+		t.Errorf("unexpected path instance name: %q, %v", name, err)
+	}
+	if name, err = InstancePathName("backup@.service", "/"); err != nil || name != "backup@-.service" {
+		t.Errorf("unexpected root instance name: %q, %v", name, err)
+	}
+}
+
+func TestMangle(t *testing.T) {
+	for in, expected := range map[string]string{
+		"nginx":             "nginx.service",
+		"nginx.service":     "nginx.service",
+		"foo@bar":           "foo@bar.service",
+		"my app":            `my\x20app.service`,
+		"/dev/sda1":         "dev-sda1.device",
+		"/home":             "home.mount",
+		"multi-user.target": "multi-user.target",
+	} {
+		if mangled, err := Mangle(in, ".service"); err != nil || mangled != expected {
+			t.Errorf("Mangle(%q) = %q, %v, expected %q", in, mangled, err, expected)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package unitfile
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Writer appends typed values to a section as options, zero values being omitted.
+// It builds the sections of UnitFile, and can build the ones of other typed files.
+type Writer struct {
+	Section *Section
+	// Literal escapes '%' in the string values so they are not subject to specifier expansion,
+	// for the files which support specifiers.
+	Literal bool
+}
+
+// newSection appends a new section named name to f, sections of the same name are not merged.
+func (f *File) newSection(name string) *Section {
+	s := &Section{Name: name}
+	f.Sections = append(f.Sections, s)
+	return s
+}
+
+// Add appends the raw option name=value.
+func (w Writer) Add(name, value string) {
+	w.Section.Options = append(w.Section.Options, Option{Name: name, Value: value})
+}
+
+func (w Writer) literal(value string) string {
+	if w.Literal {
+		return escapeSpecifiers(value)
+	}
+	return value
+}
+
+// Str appends name=value if value is not empty.
+func (w Writer) Str(name, value string) {
+	if value != "" {
+		w.Add(name, w.literal(value))
+	}
+}
+
+// Each appends one name= option per value.
+func (w Writer) Each(name string, values []string) {
+	for _, v := range values {
+		w.Add(name, w.literal(v))
+	}
+}
+
+// List appends a single name= option holding the space separated values, if any.
+func (w Writer) List(name string, values []string) {
+	if len(values) > 0 {
+		w.Add(name, w.literal(strings.Join(values, " ")))
+	}
+}
+
+// Bool appends name=yes if value is true.
+func (w Writer) Bool(name string, value bool) {
+	if value {
+		w.Add(name, "yes")
+	}
+}
+
+// Int appends name=value if value is not 0.
+func (w Writer) Int(name string, value int) {
+	if value != 0 {
+		w.Add(name, strconv.Itoa(value))
+	}
+}
+
+// Span appends name=value formatted by FormatTimeSpan if value is not 0.
+func (w Writer) Span(name string, value time.Duration) {
+	if value != 0 {
+		w.Add(name, FormatTimeSpan(value))
+	}
+}
+
+// Exec appends one name= option per command line, quoted by QuoteCommandLine.
+func (w Writer) Exec(name string, cmds [][]string) {
+	for _, argv := range cmds {
+		w.Add(name, QuoteCommandLine(argv))
+	}
+}
+
+// Extra appends the raw options.
+func (w Writer) Extra(options []Option) {
+	w.Section.Options = append(w.Section.Options, options...)
+}